@@ -0,0 +1,70 @@
+package v1
+
+import (
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// ApprovalSpec defines the desired state of Approval.
+type ApprovalSpec struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Name of the Promotion which is approved.
+	// The target stage of the Promotion should require approvals.
+	Promotion string `json:"promotion"`
+
+	// UID of the approved Promotion.
+	// It is set by the admission webhook, so the Approval isn't counted for another Promotion with the same name.
+	// +optional
+	PromotionUID types.UID `json:"promotionUID,omitempty"`
+
+	// Generation of the approved Promotion.
+	// It is set by the admission webhook.
+	// +optional
+	PromotionGeneration int64 `json:"promotionGeneration,omitempty"`
+
+	// Name of the user who approved the Promotion.
+	// It is set from the admission request user info and can't be specified by the user.
+	// +optional
+	Approver string `json:"approver,omitempty"`
+
+	// Groups of the user who approved the Promotion.
+	// They are set from the admission request user info and can't be specified by the user.
+	// +optional
+	ApproverGroups []string `json:"approverGroups,omitempty"`
+
+	// Comment of the approver.
+	// +optional
+	Comment string `json:"comment,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Promotion",type="string",JSONPath=".spec.promotion",description="Approved Promotion"
+// +kubebuilder:printcolumn:name="Approver",type="string",JSONPath=".spec.approver",description="User who approved the Promotion"
+
+// Approval is the Schema for the approvals API.
+// It records that the user has approved the Promotion to the stage which requires approvals.
+// The approver identity is verified by the admission webhook, and the Approval can't be changed after creation.
+type Approval struct {
+	metaV1.TypeMeta   `json:",inline"`
+	metaV1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApprovalSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ApprovalList contains a list of Approval.
+type ApprovalList struct {
+	metaV1.TypeMeta `json:",inline"`
+	metaV1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Approval `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Approval{}, &ApprovalList{})
+}
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"k8s.io/apimachinery/pkg/api/meta"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -13,7 +14,8 @@ type CDPipelineSpec struct {
 	// Name of CD pipeline
 	Name string `json:"name"`
 
-	// Which type of kind will be deployed e.g. Container, Custom
+	// Which type of kind will be deployed.
+	// Supported values are "container" and "custom".
 	DeploymentType string `json:"deploymentType"`
 
 	// +kubebuilder:validation:MinItems=1
@@ -30,8 +32,29 @@ type CDPipelineSpec struct {
 	// +nullable
 	// +optional
 	ApplicationsToPromote []string `json:"applicationsToPromote,omitempty"`
+
+	// Labels which are set on the target namespaces of all the pipeline stages,
+	// e.g. Pod Security Admission levels or Istio injection.
+	// +optional
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty"`
+
+	// Annotations which are set on the target namespaces of all the pipeline stages.
+	// +optional
+	NamespaceAnnotations map[string]string `json:"namespaceAnnotations,omitempty"`
 }
 
+const (
+	// DeploymentTypeContainer is a deployment type for the container applications.
+	DeploymentTypeContainer = "container"
+
+	// DeploymentTypeCustom is a deployment type for the applications with custom deployment.
+	DeploymentTypeCustom = "custom"
+
+	// ForceRemoveApplicationsAnnotation allows removing applications
+	// that have been already promoted by the CDPipeline stages.
+	ForceRemoveApplicationsAnnotation = "deploy.edp.epam.com/force-remove-applications"
+)
+
 type ActionType string
 
 const (
@@ -82,6 +105,55 @@ type CDPipelineStatus struct {
 
 	// Specifies a current state of CDPipeline.
 	Value string `json:"value"`
+
+	// Conditions represent the latest available observations of the CDPipeline state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metaV1.Condition `json:"conditions,omitempty"`
+
+	// The generation of the CDPipeline that was last processed by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// InputDockerStreams which were applied by the operator during the last successful reconciliation.
+	// It is used to find streams removed from the spec and clean up their environment labels.
+	// +optional
+	AppliedInputDockerStreams []string `json:"appliedInputDockerStreams,omitempty"`
+
+	// Stages of the CDPipeline in the promotion order.
+	// +optional
+	Stages []StageOverview `json:"stages,omitempty"`
+}
+
+// StageOverview contains the state of the stage in the CDPipeline promotion flow.
+type StageOverview struct {
+	// Name of the stage (spec.name).
+	Name string `json:"name"`
+
+	// Order of the stage in the CDPipeline.
+	Order int `json:"order"`
+
+	// Specifies if the stage has been reconciled successfully.
+	Ready bool `json:"ready"`
+
+	// Namespace where the stage applications are deployed.
+	Namespace string `json:"namespace"`
+
+	// Name of the cluster where the stage applications are deployed.
+	ClusterName string `json:"clusterName"`
+
+	// Image tags of the applications in the stage.
+	// +optional
+	Applications []ApplicationVersion `json:"applications,omitempty"`
+
+	// Specifies if the stage has verified application tags that differ from the previous stage.
+	// +optional
+	LagsBehind bool `json:"lagsBehind,omitempty"`
+
+	// Applications which verified tags differ from the previous stage.
+	// +optional
+	LaggingApplications []string `json:"laggingApplications,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -89,6 +161,7 @@ type CDPipelineStatus struct {
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.available",description="This flag indicates neither CDPipeline are initialized and ready to work"
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.status",description="Specifies a current status of CDPipeline"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Is CDPipeline reconciled successfully"
 
 // CDPipeline is the Schema for the cdpipelines API.
 type CDPipeline struct {
@@ -99,6 +172,18 @@ type CDPipeline struct {
 	Status CDPipelineStatus `json:"status,omitempty"`
 }
 
+// SetCondition sets the condition of the given type in the CDPipeline status.
+// LastTransitionTime is changed only if the condition status has been changed.
+func (p *CDPipeline) SetCondition(conditionType string, status metaV1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&p.Status.Conditions, metaV1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: p.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
 // +kubebuilder:object:root=true
 
 // CDPipelineList contains a list of CDPipeline.
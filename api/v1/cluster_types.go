@@ -0,0 +1,75 @@
+package v1
+
+import (
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// ClusterSpec defines the desired state of Cluster.
+type ClusterSpec struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Name of the Secret in the operator namespace that contains credentials of the cluster.
+	// The Secret should contain kubeconfig in the "config" key or API server URL and bearer token in the "server" and "token" keys.
+	SecretName string `json:"secretName"`
+
+	// A description of the cluster.
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// ClusterStatus defines the observed state of Cluster.
+type ClusterStatus struct {
+	// Specifies if the cluster is reachable by the operator.
+	// +optional
+	Connected bool `json:"connected"`
+
+	// Kubernetes version of the cluster.
+	// +optional
+	ServerVersion string `json:"serverVersion,omitempty"`
+
+	// Information when the last connectivity check was performed.
+	// +optional
+	LastProbeTime metaV1.Time `json:"lastProbeTime,omitempty"`
+
+	// Detailed information about the last connectivity check failure.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Connected",type="boolean",JSONPath=".status.connected",description="Is cluster reachable"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.serverVersion",description="Kubernetes version of the cluster"
+// +kubebuilder:printcolumn:name="Last Probe",type="date",JSONPath=".status.lastProbeTime",description="The last connectivity check time"
+
+// Cluster is the Schema for the clusters API.
+// It registers an external cluster where the stages can be deployed.
+type Cluster struct {
+	metaV1.TypeMeta   `json:",inline"`
+	metaV1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterSpec   `json:"spec,omitempty"`
+	Status ClusterStatus `json:"status,omitempty"`
+}
+
+// IsHealthy returns true if the cluster is reachable by the operator.
+func (c *Cluster) IsHealthy() bool {
+	return c.Status.Connected
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterList contains a list of Cluster.
+type ClusterList struct {
+	metaV1.TypeMeta `json:",inline"`
+	metaV1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Cluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Cluster{}, &ClusterList{})
+}
@@ -0,0 +1,82 @@
+package v1
+
+// Condition types of the Stage and CDPipeline resources.
+const (
+	// ConditionReady indicates that the resource has been reconciled successfully.
+	ConditionReady = "Ready"
+
+	// ConditionNamespaceReady indicates that the stage namespace, OpenShift project or Kiosk space exists.
+	ConditionNamespaceReady = "NamespaceReady"
+
+	// ConditionRBACReady indicates that the stage role bindings have been configured.
+	ConditionRBACReady = "RBACReady"
+
+	// ConditionImageStreamsReady indicates that the stage CodebaseImageStreams have been created and labeled.
+	ConditionImageStreamsReady = "ImageStreamsReady"
+
+	// ConditionJenkinsJobReady indicates that the stage JenkinsJob has been created.
+	ConditionJenkinsJobReady = "JenkinsJobReady"
+
+	// ConditionNamespaceTemplateReady indicates that the stage NamespaceTemplate has been applied in the stage namespace.
+	ConditionNamespaceTemplateReady = "NamespaceTemplateReady"
+
+	// ConditionJenkinsFolderReady indicates that the CDPipeline JenkinsFolder has been created.
+	ConditionJenkinsFolderReady = "JenkinsFolderReady"
+
+	// ConditionPlanned indicates that the plan of the Stage dry-run reconciliation has been made.
+	ConditionPlanned = "Planned"
+)
+
+// Condition reasons of the Stage and CDPipeline resources.
+const (
+	// ReasonSucceeded is set when the condition is satisfied.
+	ReasonSucceeded = "Succeeded"
+
+	// ReasonFailed is set when the condition is not satisfied because of an error.
+	ReasonFailed = "Failed"
+)
+
+// Reasons of the events emitted for the Stage, CDPipeline and Promotion resources.
+const (
+	// EventReasonNamespaceCreated is emitted when the stage namespace, OpenShift project or Kiosk space has been created.
+	EventReasonNamespaceCreated = "NamespaceCreated"
+
+	// EventReasonNamespaceDeleted is emitted when the stage namespace, OpenShift project or Kiosk space has been deleted.
+	EventReasonNamespaceDeleted = "NamespaceDeleted"
+
+	// EventReasonRBACConfigured is emitted when the stage role bindings have been configured.
+	EventReasonRBACConfigured = "RBACConfigured"
+
+	// EventReasonJenkinsJobCreated is emitted when the stage JenkinsJob has been created.
+	EventReasonJenkinsJobCreated = "JenkinsJobCreated"
+
+	// EventReasonJenkinsJobUpdated is emitted when the stage JenkinsJob config has been updated.
+	EventReasonJenkinsJobUpdated = "JenkinsJobUpdated"
+
+	// EventReasonImageStreamLabelsMoved is emitted when the stage environment labels have been moved between CodebaseImageStreams.
+	EventReasonImageStreamLabelsMoved = "ImageStreamLabelsMoved"
+
+	// EventReasonImageStreamNotFound is emitted when the reconciliation is requeued because CodebaseImageStream doesn't exist yet.
+	EventReasonImageStreamNotFound = "ImageStreamNotFound"
+
+	// EventReasonDeletionPostponed is emitted when the resource deletion is postponed until the dependent resources are deleted.
+	EventReasonDeletionPostponed = "DeletionPostponed"
+
+	// EventReasonReconcileFailed is emitted when the resource reconciliation has failed.
+	EventReasonReconcileFailed = "ReconcileFailed"
+
+	// EventReasonPromoted is emitted when the Promotion tags have been added to the target stage CodebaseImageStreams.
+	EventReasonPromoted = "Promoted"
+
+	// EventReasonPromotionFailed is emitted when the Promotion can't be made.
+	EventReasonPromotionFailed = "PromotionFailed"
+
+	// EventReasonWaitingForApproval is emitted when the Promotion needs more approvals.
+	EventReasonWaitingForApproval = "WaitingForApproval"
+
+	// EventReasonAutoPromoted is emitted when the deployment of the new versions has been requested by the promotion policy.
+	EventReasonAutoPromoted = "AutoPromoted"
+
+	// EventReasonDryRunPlanned is emitted when the plan of the dry-run reconciliation has been made.
+	EventReasonDryRunPlanned = "DryRunPlanned"
+)
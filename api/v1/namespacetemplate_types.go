@@ -0,0 +1,81 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+const (
+	// NamespaceTemplateResourceQuotaName is a name of the ResourceQuota created from the NamespaceTemplate.
+	NamespaceTemplateResourceQuotaName = "stage-resource-quota"
+
+	// NamespaceTemplateLimitRangeName is a name of the LimitRange created from the NamespaceTemplate.
+	NamespaceTemplateLimitRangeName = "stage-limit-range"
+
+	// NamespaceTemplateLabelName is a label of the resources created from the NamespaceTemplate.
+	NamespaceTemplateLabelName = "app.edp.epam.com/namespace-template"
+)
+
+// NamespaceTemplateSpec defines the resources which are applied in the stage namespace.
+type NamespaceTemplateSpec struct {
+	// ResourceQuota is applied in the stage namespace as the stage-resource-quota ResourceQuota.
+	// +optional
+	ResourceQuota *corev1.ResourceQuotaSpec `json:"resourceQuota,omitempty"`
+
+	// LimitRange is applied in the stage namespace as the stage-limit-range LimitRange.
+	// +optional
+	LimitRange *corev1.LimitRangeSpec `json:"limitRange,omitempty"`
+
+	// A list of NetworkPolicies applied in the stage namespace.
+	// +optional
+	NetworkPolicies []NetworkPolicyTemplate `json:"networkPolicies,omitempty"`
+
+	// A list of arbitrary namespaced resources applied in the stage namespace.
+	// The namespace of the resources is always set to the stage namespace, cluster-scoped resources are rejected.
+	// The operator should be allowed to get, list, create, update and delete the resources of these kinds,
+	// e.g. with the namespaceTemplate.extraRules Helm chart value.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Manifests []runtime.RawExtension `json:"manifests,omitempty"`
+}
+
+// NetworkPolicyTemplate defines the NetworkPolicy applied in the stage namespace.
+type NetworkPolicyTemplate struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Name of the NetworkPolicy.
+	Name string `json:"name"`
+
+	// Specification of the NetworkPolicy.
+	Spec networkingv1.NetworkPolicySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+
+// NamespaceTemplate is the Schema for the namespacetemplates API.
+// It holds the resources which are applied in the namespaces of the stages that reference the template.
+type NamespaceTemplate struct {
+	metaV1.TypeMeta   `json:",inline"`
+	metaV1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NamespaceTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespaceTemplateList contains a list of NamespaceTemplate.
+type NamespaceTemplateList struct {
+	metaV1.TypeMeta `json:",inline"`
+	metaV1.ListMeta `json:"metadata,omitempty"`
+
+	Items []NamespaceTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NamespaceTemplate{}, &NamespaceTemplateList{})
+}
@@ -0,0 +1,136 @@
+package v1
+
+import (
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Phases of the Promotion.
+const (
+	// PromotionPhaseSucceeded is set when the tags have been added to the target stage CodebaseImageStreams.
+	PromotionPhaseSucceeded = "Succeeded"
+
+	// PromotionPhaseWaitingForApproval is set when the target stage requires more approvals for the promotion.
+	PromotionPhaseWaitingForApproval = "WaitingForApproval"
+
+	// PromotionPhaseFailed is set when the promotion can't be made, e.g. the tag doesn't exist in the source stage.
+	PromotionPhaseFailed = "Failed"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// PromotionSpec defines the desired state of Promotion.
+type PromotionSpec struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Name of the CDPipeline.
+	Pipeline string `json:"pipeline"`
+
+	// +kubebuilder:validation:MinLength=1
+
+	// Name of the stage (spec.name) from which the tags are promoted.
+	SourceStage string `json:"sourceStage"`
+
+	// +kubebuilder:validation:MinLength=1
+
+	// Name of the stage (spec.name) to which the tags are promoted.
+	// It should follow the source stage in the CDPipeline.
+	TargetStage string `json:"targetStage"`
+
+	// +kubebuilder:validation:MinItems=1
+
+	// Applications and their tags to promote.
+	Applications []PromotedApplication `json:"applications"`
+}
+
+// PromotedApplication is an application tag to promote.
+type PromotedApplication struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Name of the application (codebase).
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:MinLength=1
+
+	// Tag of the application image.
+	// It should exist in the source stage verified CodebaseImageStream.
+	Tag string `json:"tag"`
+}
+
+// PromotionStatus defines the observed state of Promotion.
+type PromotionStatus struct {
+	// Result of the promotion. Succeeded, Failed or WaitingForApproval.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// Detailed information about the promotion result.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Information when the promotion was completed.
+	// +optional
+	CompletionTime *metaV1.Time `json:"completionTime,omitempty"`
+
+	// Target stage CodebaseImageStreams with the promoted tags.
+	// +optional
+	ImageStreams []PromotedImageStream `json:"imageStreams,omitempty"`
+
+	// Users whose approvals have been counted for the promotion.
+	// +optional
+	ApprovedBy []string `json:"approvedBy,omitempty"`
+
+	// The generation of the Promotion that was reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// PromotedImageStream is a CodebaseImageStream to which the tag has been promoted.
+type PromotedImageStream struct {
+	// Name of the application (codebase).
+	Application string `json:"application"`
+
+	// Name of the target stage CodebaseImageStream.
+	Name string `json:"name"`
+
+	// Promoted tag.
+	Tag string `json:"tag"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Pipeline",type="string",JSONPath=".spec.pipeline",description="Name of the CDPipeline"
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.sourceStage",description="Source stage"
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetStage",description="Target stage"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Result of the promotion"
+
+// Promotion is the Schema for the promotions API.
+// It moves the verified image tags of the applications from one stage to another.
+// Promotion is made once, so the object is kept as a record of it.
+type Promotion struct {
+	metaV1.TypeMeta   `json:",inline"`
+	metaV1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec can't be changed, so the Approvals are given to the exact tags.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+	Spec   PromotionSpec   `json:"spec,omitempty"`
+	Status PromotionStatus `json:"status,omitempty"`
+}
+
+// IsCompleted returns true if the promotion has been made.
+func (p *Promotion) IsCompleted() bool {
+	return p.Status.Phase == PromotionPhaseSucceeded
+}
+
+// +kubebuilder:object:root=true
+
+// PromotionList contains a list of Promotion.
+type PromotionList struct {
+	metaV1.TypeMeta `json:",inline"`
+	metaV1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Promotion `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Promotion{}, &PromotionList{})
+}
@@ -0,0 +1,5 @@
+package v1
+
+// Hub marks Stage as a conversion hub.
+// v1 is the storage version, all other versions are converted to and from it.
+func (*Stage) Hub() {}
@@ -1,12 +1,25 @@
 package v1
 
 import (
+	rbacApi "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	StageCdPipelineLabelName = "app.edp.epam.com/cdPipelineName"
 	InCluster                = "in-cluster"
+
+	// DryRunAnnotation enables the dry-run mode for the Stage if it is set to "true".
+	// In the dry-run mode, the changes that would be made by the reconciliation are recorded
+	// in the Stage status plan instead of being applied. Stage deletion is not affected.
+	DryRunAnnotation = "deploy.edp.epam.com/dry-run"
+)
+
+// Quality gate types.
+const (
+	QualityGateTypeManual    = "manual"
+	QualityGateTypeAutotests = "autotests"
 )
 
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
@@ -46,18 +59,122 @@ type StageSpec struct {
 	JobProvisioning string `json:"jobProvisioning"`
 
 	// Namespace where the application will be deployed.
+	// If it is not set, the operator uses <stage namespace>-<stage name> name.
+	// The namespace is created by the operator if it doesn't exist.
+	// Existing namespaces can be used only if they are listed in the operator shared namespaces.
+	// The operator configures and deletes only the namespaces created for the stage.
+	// The namespace can't be changed.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="namespace is immutable"
 	Namespace string `json:"namespace,omitempty"`
 
+	// Name of the NamespaceTemplate in the stage namespace.
+	// Resources from the template are applied in the stage target namespace and kept in sync.
+	// +optional
+	NamespaceTemplate string `json:"namespaceTemplate,omitempty"`
+
+	// Labels which are set on the stage target namespace.
+	// They override the CDPipeline namespaceLabels with the same keys.
+	// Labels are kept in sync on every reconciliation, removed keys are deleted from the namespace.
+	// +optional
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty"`
+
+	// Annotations which are set on the stage target namespace.
+	// They override the CDPipeline namespaceAnnotations with the same keys.
+	// +optional
+	NamespaceAnnotations map[string]string `json:"namespaceAnnotations,omitempty"`
+
+	// A list of RoleBindings created in the stage target namespace.
+	// If it is not set, the role bindings from the cd-pipeline-operator-rbac ConfigMap in the stage namespace are used.
+	// If neither is set, the tenant OIDC admins and developers groups get the admin ClusterRole.
+	// +optional
+	RoleBindings []RoleBindingTemplate `json:"roleBindings,omitempty"`
+
 	// Specifies a name of cluster where the application will be deployed.
 	// Default value is "in-cluster" which means that application will be deployed in the same cluster where CD Pipeline is running.
+	// For the external cluster, it should be a name of the Cluster resource in the operator namespace.
 	// +optional
 	// +kubebuilder:default:="in-cluster"
 	ClusterName string `json:"clusterName,omitempty"`
+
+	// Policy of the automatic promotion of the new application versions from the previous stage.
+	// +optional
+	PromotionPolicy *PromotionPolicy `json:"promotionPolicy,omitempty"`
+
+	// Approvals required to promote the application versions to the stage.
+	// Promotions to the stage are made only after enough approvals have been recorded.
+	// The stage which requires approvals can't have the Auto trigger type.
+	// +optional
+	Approval *ApprovalPolicy `json:"approval,omitempty"`
+}
+
+// ApprovalPolicy defines who should approve the Promotions to the stage.
+type ApprovalPolicy struct {
+	// Users who can approve the Promotions to the stage.
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// Groups which members can approve the Promotions to the stage.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+
+	// Number of approvals from different approvers required for the Promotion.
+	// +optional
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	RequiredApprovals int `json:"requiredApprovals,omitempty"`
+}
+
+// Required returns the number of approvals required for the Promotion.
+func (p *ApprovalPolicy) Required() int {
+	if p.RequiredApprovals < 1 {
+		return 1
+	}
+
+	return p.RequiredApprovals
+}
+
+// IsApprover returns true if the user or one of the user groups can approve the Promotions.
+func (p *ApprovalPolicy) IsApprover(user string, groups []string) bool {
+	for _, u := range p.Users {
+		if u == user {
+			return true
+		}
+	}
+
+	for _, g := range p.Groups {
+		for _, ug := range groups {
+			if g == ug {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// PromotionPolicy defines how the new versions of the promoted applications are moved from the previous stage to the stage.
+type PromotionPolicy struct {
+	// Specifies if the new tags of the previous stage verified CodebaseImageStreams are promoted to the stage automatically.
+	// The operator creates a CDStageDeploy of the new tag, which triggers the stage deploy pipeline.
+	// It is applied only to the stages with the Auto trigger type which are not the first in the CDPipeline.
+	// +optional
+	Auto bool `json:"auto,omitempty"`
+
+	// Specifies if the tags are promoted only after they have passed the quality gates of the previous stage,
+	// i.e. they have been added to the previous stage verified CodebaseImageStream by its deploy pipeline.
+	// Tags added to the stream by Promotions are not promoted.
+	// +optional
+	RequireQualityGates bool `json:"requireQualityGates,omitempty"`
+
+	// Time that should pass after the tag has been verified in the previous stage before it is promoted. E.g. 10m.
+	// +optional
+	Delay *metaV1.Duration `json:"delay,omitempty"`
 }
 
 // QualityGate defines a single quality for a release.
 type QualityGate struct {
-	// A type of quality gate, e.g. "Manual", "Autotests"
+	// A type of quality gate, "manual" or "autotests".
 	QualityGateType string `json:"qualityGateType"`
 
 	// +kubebuilder:validation:MinLength=2
@@ -65,7 +182,8 @@ type QualityGate struct {
 	// Specifies a name of particular
 	StepName string `json:"stepName"`
 
-	// A name of autotests to run with quality gate
+	// A name of autotests to run with quality gate.
+	// It is required for the "autotests" quality gate type.
 	// +nullable
 	// +optional
 	AutotestName *string `json:"autotestName"`
@@ -76,6 +194,30 @@ type QualityGate struct {
 	BranchName *string `json:"branchName"`
 }
 
+// RoleBindingTemplate defines a RoleBinding created in the stage target namespace.
+// The RoleBinding references either the roleRef or the Role created from the rules.
+type RoleBindingTemplate struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Name of the RoleBinding.
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:MinItems=1
+
+	// Subjects bound to the role.
+	Subjects []rbacApi.Subject `json:"subjects"`
+
+	// Role or ClusterRole referenced by the RoleBinding.
+	// It should be omitted if the rules are set.
+	// +optional
+	RoleRef *rbacApi.RoleRef `json:"roleRef,omitempty"`
+
+	// Rules of the Role which is created in the stage target namespace with the same name as the RoleBinding.
+	// It should be omitted if the roleRef is set.
+	// +optional
+	Rules []rbacApi.PolicyRule `json:"rules,omitempty"`
+}
+
 // Source defines a pipeline library.
 type Source struct {
 	// Type of pipeline library, e.g. default, library
@@ -126,9 +268,122 @@ type StageStatus struct {
 	// Specifies a current state of Stage.
 	Value string `json:"value"`
 
+	// Conditions represent the latest available observations of the Stage state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metaV1.Condition `json:"conditions,omitempty"`
+
+	// The generation of the Stage that was last processed by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Should update of status be handled. Defaults to false.
 	// +optional
 	ShouldBeHandled bool `json:"shouldBeHandled,omitempty"`
+
+	// Names of the chain steps which were used for the last Stage reconciliation.
+	// +optional
+	ChainSteps []string `json:"chainSteps,omitempty"`
+
+	// Plan contains the changes that would be made by the reconciliation.
+	// It is set only when the Stage is reconciled in the dry-run mode.
+	// +optional
+	Plan []PlannedChange `json:"plan,omitempty"`
+
+	// Applications contain the image tags of the CDPipeline applications in the stage.
+	// +optional
+	Applications []ApplicationVersion `json:"applications,omitempty"`
+
+	// AutoPromotion contains the versions promoted to the stage by the promotion policy.
+	// +optional
+	AutoPromotion *AutoPromotionStatus `json:"autoPromotion,omitempty"`
+
+	// NamespaceTemplateResources contain the resources applied in the stage namespace from the NamespaceTemplate.
+	// Resources which are removed from the template are deleted.
+	// +optional
+	NamespaceTemplateResources []AppliedResource `json:"namespaceTemplateResources,omitempty"`
+
+	// NamespaceLabels contain the keys of the labels set on the stage namespace from the Stage and CDPipeline spec.
+	// Labels which are removed from the spec are removed from the namespace.
+	// +optional
+	NamespaceLabels []string `json:"namespaceLabels,omitempty"`
+
+	// NamespaceAnnotations contain the keys of the annotations set on the stage namespace from the Stage and CDPipeline spec.
+	// Annotations which are removed from the spec are removed from the namespace.
+	// +optional
+	NamespaceAnnotations []string `json:"namespaceAnnotations,omitempty"`
+
+	// ReadyTime is the time when the Stage became ready for the first time.
+	// +optional
+	ReadyTime *metaV1.Time `json:"readyTime,omitempty"`
+}
+
+// AppliedResource is a reference to the resource applied in the stage namespace.
+type AppliedResource struct {
+	// API version of the resource.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the resource.
+	Kind string `json:"kind"`
+
+	// Name of the resource.
+	Name string `json:"name"`
+}
+
+// AutoPromotionStatus contains the versions promoted to the stage by the promotion policy.
+// A CDStageDeploy is created for each of them, so the stage deploy pipeline deploys them.
+type AutoPromotionStatus struct {
+	// The latest tags of the applications promoted to the stage.
+	// +optional
+	Applications []PromotedApplication `json:"applications,omitempty"`
+
+	// Information when the last tag was promoted.
+	// +optional
+	LastPromotionTime metaV1.Time `json:"lastPromotionTime,omitempty"`
+}
+
+// ApplicationVersion contains the image tags of the application in the stage.
+type ApplicationVersion struct {
+	// Name of the application (codebase).
+	Name string `json:"name"`
+
+	// Name of the CodebaseImageStream from which the application is promoted to the stage.
+	// It is the CDPipeline input stream or the previous stage verified stream for the promoted applications.
+	InputImageStream string `json:"inputImageStream"`
+
+	// The latest tag of the input CodebaseImageStream, which is available for the deployment to the stage.
+	// It isn't necessarily deployed, the operator doesn't track the deployments.
+	// +optional
+	AvailableTag string `json:"availableTag,omitempty"`
+
+	// Name of the stage verified CodebaseImageStream.
+	VerifiedImageStream string `json:"verifiedImageStream"`
+
+	// The latest tag of the stage verified CodebaseImageStream.
+	// +optional
+	VerifiedTag string `json:"verifiedTag,omitempty"`
+}
+
+// PlannedChange is a change of the resource that would be made by the Stage reconciliation.
+type PlannedChange struct {
+	// Action that would be performed. E.g. Create, Update, Delete.
+	Action string `json:"action"`
+
+	// Kind of the resource.
+	Kind string `json:"kind"`
+
+	// Namespace of the resource.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the resource.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Diff is a JSON patch between the current and the desired state of the resource.
+	// +optional
+	Diff string `json:"diff,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -138,6 +393,7 @@ type StageStatus struct {
 // +kubebuilder:printcolumn:name="CDPipeline Name",type="string",JSONPath=".spec.cdPipeline",description="CDPipeline that owns the Stage"
 // +kubebuilder:printcolumn:name="Trigger Type",type="string",JSONPath=".spec.triggerType",description="Stage deployment trigger type. E.g. Manual, Auto"
 // +kubebuilder:printcolumn:name="Order",type="integer",JSONPath=".spec.order",description="The order in the CDPipeline promotion flow (starts from 0)"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Is Stage reconciled successfully"
 
 // Stage is the Schema for the stages API.
 type Stage struct {
@@ -152,8 +408,33 @@ func (s *Stage) IsFirst() bool {
 	return s.Spec.Order == 0
 }
 
+// IsDryRun returns true if the Stage should be reconciled in the dry-run mode.
+func (s *Stage) IsDryRun() bool {
+	return s.GetAnnotations()[DryRunAnnotation] == "true"
+}
+
+// HasAutoPromotion returns true if the new versions are promoted to the Stage by its promotion policy.
+// Stages which require approvals are promoted only by the approved Promotions.
+func (s *Stage) HasAutoPromotion() bool {
+	return s.Spec.PromotionPolicy != nil && s.Spec.PromotionPolicy.Auto && s.Spec.Approval == nil
+}
+
+// InCluster returns true if the stage is deployed in the same cluster where the operator is running.
+// Empty cluster name is treated as in-cluster for the stages created before the clusterName field was added.
 func (s *Stage) InCluster() bool {
-	return s.Spec.ClusterName == InCluster
+	return s.Spec.ClusterName == InCluster || s.Spec.ClusterName == ""
+}
+
+// SetCondition sets the condition of the given type in the Stage status.
+// LastTransitionTime is changed only if the condition status has been changed.
+func (s *Stage) SetCondition(conditionType string, status metaV1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&s.Status.Conditions, metaV1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: s.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
 }
 
 // +kubebuilder:object:root=true
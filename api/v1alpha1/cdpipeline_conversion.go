@@ -0,0 +1,87 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+// cdPipelineConversionData contains the v1 CDPipeline fields which are kept in the conversion annotation.
+type cdPipelineConversionData struct {
+	Spec   cdPipeApi.CDPipelineSpec   `json:"spec"`
+	Status cdPipeApi.CDPipelineStatus `json:"status"`
+}
+
+// ConvertTo converts this CDPipeline to the Hub version (v1).
+func (in *CDPipeline) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*cdPipeApi.CDPipeline)
+	if !ok {
+		return fmt.Errorf("unexpected type %T of the conversion hub", dstRaw)
+	}
+
+	dst.ObjectMeta = *in.ObjectMeta.DeepCopy()
+
+	data := cdPipelineConversionData{}
+	if _, err := unmarshalConversionData(&dst.ObjectMeta, &data); err != nil {
+		return err
+	}
+
+	spec := in.Spec.DeepCopy()
+
+	dst.Spec = data.Spec
+	dst.Spec.Name = spec.Name
+	dst.Spec.DeploymentType = spec.DeploymentType
+	dst.Spec.InputDockerStreams = spec.InputDockerStreams
+	dst.Spec.Applications = spec.Applications
+	dst.Spec.ApplicationsToPromote = spec.ApplicationsToPromote
+
+	dst.Status = data.Status
+	dst.Status.Available = in.Status.Available
+	dst.Status.LastTimeUpdated = in.Status.LastTimeUpdated
+	dst.Status.Status = in.Status.Status
+	dst.Status.Username = in.Status.Username
+	dst.Status.Action = cdPipeApi.ActionType(in.Status.Action)
+	dst.Status.Result = cdPipeApi.Result(in.Status.Result)
+	dst.Status.DetailedMessage = in.Status.DetailedMessage
+	dst.Status.Value = in.Status.Value
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1) to this version.
+func (in *CDPipeline) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*cdPipeApi.CDPipeline)
+	if !ok {
+		return fmt.Errorf("unexpected type %T of the conversion hub", srcRaw)
+	}
+
+	in.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	spec := src.Spec.DeepCopy()
+
+	in.Spec = CDPipelineSpec{
+		Name:                  spec.Name,
+		DeploymentType:        spec.DeploymentType,
+		InputDockerStreams:    spec.InputDockerStreams,
+		Applications:          spec.Applications,
+		ApplicationsToPromote: spec.ApplicationsToPromote,
+	}
+
+	in.Status = CDPipelineStatus{
+		Available:       src.Status.Available,
+		LastTimeUpdated: src.Status.LastTimeUpdated,
+		Status:          src.Status.Status,
+		Username:        src.Status.Username,
+		Action:          ActionType(src.Status.Action),
+		Result:          Result(src.Status.Result),
+		DetailedMessage: src.Status.DetailedMessage,
+		Value:           src.Status.Value,
+	}
+
+	return marshalConversionData(&in.ObjectMeta, cdPipelineConversionData{
+		Spec:   src.Spec,
+		Status: src.Status,
+	})
+}
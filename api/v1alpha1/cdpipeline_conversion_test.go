@@ -0,0 +1,138 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+func TestCDPipeline_IsConvertible(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+	require.NoError(t, AddToScheme(scheme))
+
+	ok, err := conversion.IsConvertible(scheme, &cdPipeApi.CDPipeline{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestCDPipeline_ConvertFrom_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	hub := &cdPipeApi.CDPipeline{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:       "pipeline",
+			Namespace:  "default",
+			Generation: 2,
+		},
+		Spec: cdPipeApi.CDPipelineSpec{
+			Name:                  "pipeline",
+			DeploymentType:        "container",
+			InputDockerStreams:    []string{"app-main"},
+			Applications:          []string{"app"},
+			ApplicationsToPromote: []string{"app"},
+		},
+		Status: cdPipeApi.CDPipelineStatus{
+			Available: true,
+			Status:    "created",
+			Action:    cdPipeApi.SetupInitialStructureForCDPipeline,
+			Result:    cdPipeApi.Success,
+			Value:     "active",
+			Conditions: []metaV1.Condition{
+				{
+					Type:   cdPipeApi.ConditionReady,
+					Status: metaV1.ConditionTrue,
+					Reason: cdPipeApi.ReasonSucceeded,
+				},
+			},
+			ObservedGeneration: 2,
+		},
+	}
+
+	spoke := &CDPipeline{}
+	require.NoError(t, spoke.ConvertFrom(hub.DeepCopy()))
+
+	assert.Equal(t, []string{"app-main"}, spoke.Spec.InputDockerStreams)
+	assert.Equal(t, ActionType(cdPipeApi.SetupInitialStructureForCDPipeline), spoke.Status.Action)
+
+	restored := &cdPipeApi.CDPipeline{}
+	require.NoError(t, spoke.ConvertTo(restored))
+
+	assert.Equal(t, hub, restored)
+}
+
+func TestCDPipeline_ConvertTo_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	spoke := &CDPipeline{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "pipeline",
+			Namespace: "default",
+		},
+		Spec: CDPipelineSpec{
+			Name:               "pipeline",
+			DeploymentType:     "container",
+			InputDockerStreams: []string{"app-main"},
+			Applications:       []string{"app"},
+		},
+		Status: CDPipelineStatus{
+			Status: "created",
+			Result: Success,
+		},
+	}
+
+	hub := &cdPipeApi.CDPipeline{}
+	require.NoError(t, spoke.DeepCopy().ConvertTo(hub))
+
+	assert.Equal(t, []string{"app"}, hub.Spec.Applications)
+	assert.Equal(t, cdPipeApi.Success, hub.Status.Result)
+
+	restored := &CDPipeline{}
+	require.NoError(t, restored.ConvertFrom(hub))
+	delete(restored.Annotations, ConversionDataAnnotation)
+
+	if len(restored.Annotations) == 0 {
+		restored.Annotations = nil
+	}
+
+	assert.Equal(t, spoke, restored)
+}
+
+func TestCDPipeline_ConvertTo_KeepsHubFields(t *testing.T) {
+	t.Parallel()
+
+	hub := &cdPipeApi.CDPipeline{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "pipeline",
+			Namespace: "default",
+		},
+		Spec: cdPipeApi.CDPipelineSpec{
+			Name:         "pipeline",
+			Applications: []string{"app"},
+		},
+		Status: cdPipeApi.CDPipelineStatus{
+			ObservedGeneration: 5,
+		},
+	}
+
+	spoke := &CDPipeline{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+
+	// v1alpha1 client updates the object.
+	spoke.Spec.Applications = []string{"app", "app2"}
+
+	updated := &cdPipeApi.CDPipeline{}
+	require.NoError(t, spoke.ConvertTo(updated))
+
+	assert.Equal(t, []string{"app", "app2"}, updated.Spec.Applications)
+	assert.Equal(t, int64(5), updated.Status.ObservedGeneration)
+	assert.NotContains(t, updated.Annotations, ConversionDataAnnotation)
+}
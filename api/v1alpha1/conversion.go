@@ -0,0 +1,56 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConversionDataAnnotation is an annotation that keeps the v1 fields which can't be represented in v1alpha1.
+// It is set when the v1 object is converted to v1alpha1 and is removed when the object is converted back,
+// so the fields are not lost when v1alpha1 clients update the object.
+const ConversionDataAnnotation = "v2.edp.epam.com/conversion-data"
+
+// marshalConversionData stores the data of the hub object in the conversion annotation of the given object.
+func marshalConversionData(obj metaV1.Object, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal conversion data: %w", err)
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+
+	annotations[ConversionDataAnnotation] = string(raw)
+	obj.SetAnnotations(annotations)
+
+	return nil
+}
+
+// unmarshalConversionData restores the hub object data from the conversion annotation of the given object.
+// The annotation is removed from the object. It returns false if the object doesn't contain the annotation.
+func unmarshalConversionData(obj metaV1.Object, data interface{}) (bool, error) {
+	annotations := obj.GetAnnotations()
+
+	raw, ok := annotations[ConversionDataAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	if err := json.Unmarshal([]byte(raw), data); err != nil {
+		return false, fmt.Errorf("failed to unmarshal conversion data: %w", err)
+	}
+
+	delete(annotations, ConversionDataAnnotation)
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	obj.SetAnnotations(annotations)
+
+	return true, nil
+}
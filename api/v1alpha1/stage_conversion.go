@@ -0,0 +1,132 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+// stageConversionData contains the v1 Stage fields which are kept in the conversion annotation.
+type stageConversionData struct {
+	Spec   cdPipeApi.StageSpec   `json:"spec"`
+	Status cdPipeApi.StageStatus `json:"status"`
+}
+
+// ConvertTo converts this Stage to the Hub version (v1).
+func (in *Stage) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*cdPipeApi.Stage)
+	if !ok {
+		return fmt.Errorf("unexpected type %T of the conversion hub", dstRaw)
+	}
+
+	dst.ObjectMeta = *in.ObjectMeta.DeepCopy()
+
+	data := stageConversionData{}
+	if _, err := unmarshalConversionData(&dst.ObjectMeta, &data); err != nil {
+		return err
+	}
+
+	dst.Spec = data.Spec
+	dst.Spec.Name = in.Spec.Name
+	dst.Spec.CdPipeline = in.Spec.CdPipeline
+	dst.Spec.Description = in.Spec.Description
+	dst.Spec.TriggerType = in.Spec.TriggerType
+	dst.Spec.Order = in.Spec.Order
+	dst.Spec.JobProvisioning = in.Spec.JobProvisioning
+	dst.Spec.Source = cdPipeApi.Source{
+		Type: in.Spec.Source.Type,
+		Library: cdPipeApi.Library{
+			Name:   in.Spec.Source.Library.Name,
+			Branch: in.Spec.Source.Library.Branch,
+		},
+	}
+
+	dst.Spec.QualityGates = nil
+	if in.Spec.QualityGates != nil {
+		dst.Spec.QualityGates = make([]cdPipeApi.QualityGate, 0, len(in.Spec.QualityGates))
+
+		for i := range in.Spec.QualityGates {
+			qg := in.Spec.QualityGates[i].DeepCopy()
+
+			dst.Spec.QualityGates = append(dst.Spec.QualityGates, cdPipeApi.QualityGate{
+				QualityGateType: qg.QualityGateType,
+				StepName:        qg.StepName,
+				AutotestName:    qg.AutotestName,
+				BranchName:      qg.BranchName,
+			})
+		}
+	}
+
+	dst.Status = data.Status
+	dst.Status.Available = in.Status.Available
+	dst.Status.LastTimeUpdated = in.Status.LastTimeUpdated
+	dst.Status.Status = in.Status.Status
+	dst.Status.Username = in.Status.Username
+	dst.Status.Action = cdPipeApi.ActionType(in.Status.Action)
+	dst.Status.Result = cdPipeApi.Result(in.Status.Result)
+	dst.Status.DetailedMessage = in.Status.DetailedMessage
+	dst.Status.Value = in.Status.Value
+	dst.Status.ShouldBeHandled = in.Status.ShouldBeHandled
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1) to this version.
+func (in *Stage) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*cdPipeApi.Stage)
+	if !ok {
+		return fmt.Errorf("unexpected type %T of the conversion hub", srcRaw)
+	}
+
+	in.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	in.Spec = StageSpec{
+		Name:            src.Spec.Name,
+		CdPipeline:      src.Spec.CdPipeline,
+		Description:     src.Spec.Description,
+		TriggerType:     src.Spec.TriggerType,
+		Order:           src.Spec.Order,
+		JobProvisioning: src.Spec.JobProvisioning,
+		Source: Source{
+			Type: src.Spec.Source.Type,
+			Library: Library{
+				Name:   src.Spec.Source.Library.Name,
+				Branch: src.Spec.Source.Library.Branch,
+			},
+		},
+	}
+
+	if src.Spec.QualityGates != nil {
+		in.Spec.QualityGates = make([]QualityGate, 0, len(src.Spec.QualityGates))
+
+		for i := range src.Spec.QualityGates {
+			qg := src.Spec.QualityGates[i].DeepCopy()
+
+			in.Spec.QualityGates = append(in.Spec.QualityGates, QualityGate{
+				QualityGateType: qg.QualityGateType,
+				StepName:        qg.StepName,
+				AutotestName:    qg.AutotestName,
+				BranchName:      qg.BranchName,
+			})
+		}
+	}
+
+	in.Status = StageStatus{
+		Available:       src.Status.Available,
+		LastTimeUpdated: src.Status.LastTimeUpdated,
+		Status:          src.Status.Status,
+		Username:        src.Status.Username,
+		Action:          ActionType(src.Status.Action),
+		Result:          Result(src.Status.Result),
+		DetailedMessage: src.Status.DetailedMessage,
+		Value:           src.Status.Value,
+		ShouldBeHandled: src.Status.ShouldBeHandled,
+	}
+
+	return marshalConversionData(&in.ObjectMeta, stageConversionData{
+		Spec:   src.Spec,
+		Status: src.Status,
+	})
+}
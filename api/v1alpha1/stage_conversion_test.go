@@ -0,0 +1,188 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+func TestStage_IsConvertible(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+	require.NoError(t, AddToScheme(scheme))
+
+	ok, err := conversion.IsConvertible(scheme, &cdPipeApi.Stage{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestStage_ConvertFrom_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	hub := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:        "stage",
+			Namespace:   "default",
+			Annotations: map[string]string{"key": "value"},
+			Generation:  3,
+		},
+		Spec: cdPipeApi.StageSpec{
+			Name:        "dev",
+			CdPipeline:  "pipeline",
+			Description: "description",
+			TriggerType: "Auto",
+			Order:       1,
+			QualityGates: []cdPipeApi.QualityGate{
+				{
+					QualityGateType: "autotests",
+					StepName:        "step",
+					AutotestName:    pointer.String("autotest"),
+					BranchName:      pointer.String("master"),
+				},
+			},
+			Source: cdPipeApi.Source{
+				Type: "library",
+				Library: cdPipeApi.Library{
+					Name:   "lib",
+					Branch: "main",
+				},
+			},
+			JobProvisioning: "default",
+			Namespace:       "custom-namespace",
+			ClusterName:     "external",
+		},
+		Status: cdPipeApi.StageStatus{
+			Available:       true,
+			Status:          "created",
+			Username:        "system",
+			Action:          cdPipeApi.AcceptCDStageRegistration,
+			Result:          cdPipeApi.Success,
+			Value:           "active",
+			ShouldBeHandled: true,
+			Conditions: []metaV1.Condition{
+				{
+					Type:   cdPipeApi.ConditionReady,
+					Status: metaV1.ConditionTrue,
+					Reason: cdPipeApi.ReasonSucceeded,
+				},
+			},
+			ObservedGeneration: 3,
+		},
+	}
+
+	spoke := &Stage{}
+	require.NoError(t, spoke.ConvertFrom(hub.DeepCopy()))
+
+	assert.Equal(t, "dev", spoke.Spec.Name)
+	assert.Equal(t, "autotest", *spoke.Spec.QualityGates[0].AutotestName)
+	assert.Equal(t, "lib", spoke.Spec.Source.Library.Name)
+	assert.Equal(t, ActionType(cdPipeApi.AcceptCDStageRegistration), spoke.Status.Action)
+	assert.Contains(t, spoke.Annotations, ConversionDataAnnotation)
+
+	restored := &cdPipeApi.Stage{}
+	require.NoError(t, spoke.ConvertTo(restored))
+
+	assert.Equal(t, hub, restored)
+}
+
+func TestStage_ConvertTo_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	spoke := &Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "stage",
+			Namespace: "default",
+		},
+		Spec: StageSpec{
+			Name:        "dev",
+			CdPipeline:  "pipeline",
+			TriggerType: "Manual",
+			QualityGates: []QualityGate{
+				{
+					QualityGateType: "manual",
+					StepName:        "approve",
+				},
+			},
+			Source: Source{
+				Type: "default",
+			},
+		},
+		Status: StageStatus{
+			Status: "failed",
+			Result: Error,
+		},
+	}
+
+	hub := &cdPipeApi.Stage{}
+	require.NoError(t, spoke.DeepCopy().ConvertTo(hub))
+
+	assert.Equal(t, "dev", hub.Spec.Name)
+	assert.True(t, hub.InCluster())
+	assert.Equal(t, cdPipeApi.Error, hub.Status.Result)
+
+	restored := &Stage{}
+	require.NoError(t, restored.ConvertFrom(hub))
+	delete(restored.Annotations, ConversionDataAnnotation)
+
+	if len(restored.Annotations) == 0 {
+		restored.Annotations = nil
+	}
+
+	assert.Equal(t, spoke, restored)
+}
+
+func TestStage_ConvertTo_KeepsHubFields(t *testing.T) {
+	t.Parallel()
+
+	hub := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "stage",
+			Namespace: "default",
+		},
+		Spec: cdPipeApi.StageSpec{
+			Name:        "dev",
+			CdPipeline:  "pipeline",
+			Description: "old description",
+			Namespace:   "custom-namespace",
+			ClusterName: "external",
+		},
+	}
+
+	spoke := &Stage{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+
+	// v1alpha1 client updates the object.
+	spoke.Spec.Description = "new description"
+
+	updated := &cdPipeApi.Stage{}
+	require.NoError(t, spoke.ConvertTo(updated))
+
+	assert.Equal(t, "new description", updated.Spec.Description)
+	assert.Equal(t, "custom-namespace", updated.Spec.Namespace)
+	assert.Equal(t, "external", updated.Spec.ClusterName)
+	assert.NotContains(t, updated.Annotations, ConversionDataAnnotation)
+}
+
+func TestStage_ConvertTo_InvalidConversionData(t *testing.T) {
+	t.Parallel()
+
+	spoke := &Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:        "stage",
+			Annotations: map[string]string{ConversionDataAnnotation: "invalid"},
+		},
+	}
+
+	err := spoke.ConvertTo(&cdPipeApi.Stage{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to unmarshal conversion data")
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,21 +25,29 @@ import (
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/cluster"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/consts"
+	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
 	jenkinsApi "github.com/epam/edp-jenkins-operator/v2/pkg/apis/v2/v1"
 )
 
-func NewReconcileCDPipeline(c client.Client, scheme *runtime.Scheme, log logr.Logger) *ReconcileCDPipeline {
+func NewReconcileCDPipeline(
+	c client.Client,
+	scheme *runtime.Scheme,
+	log logr.Logger,
+	recorder record.EventRecorder,
+) *ReconcileCDPipeline {
 	return &ReconcileCDPipeline{
-		client: c,
-		scheme: scheme,
-		log:    log.WithName("cd-pipeline"),
+		client:   c,
+		scheme:   scheme,
+		log:      log.WithName("cd-pipeline"),
+		recorder: recorder,
 	}
 }
 
 type ReconcileCDPipeline struct {
-	client client.Client
-	scheme *runtime.Scheme
-	log    logr.Logger
+	client   client.Client
+	scheme   *runtime.Scheme
+	log      logr.Logger
+	recorder record.EventRecorder
 }
 
 const (
@@ -75,6 +87,8 @@ func (r *ReconcileCDPipeline) SetupWithManager(mgr ctrl.Manager) error {
 //+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=cdpipelines,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=cdpipelines/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=cdpipelines/finalizers,verbs=update
+//+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=stages,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",namespace=placeholder,resources=events,verbs=create;patch
 
 func (r *ReconcileCDPipeline) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -100,8 +114,24 @@ func (r *ReconcileCDPipeline) Reconcile(ctx context.Context, request reconcile.R
 
 	if cluster.JenkinsEnabled(ctx, r.client, request.Namespace, log) {
 		if err := r.createJenkinsFolder(ctx, pipeline); err != nil {
+			pipeline.SetCondition(cdPipeApi.ConditionJenkinsFolderReady, metaV1.ConditionFalse, cdPipeApi.ReasonFailed, err.Error())
+
+			if statusErr := r.setFailedStatus(ctx, pipeline, err); statusErr != nil {
+				return reconcile.Result{}, statusErr
+			}
+
 			return reconcile.Result{}, err
 		}
+
+		pipeline.SetCondition(cdPipeApi.ConditionJenkinsFolderReady, metaV1.ConditionTrue, cdPipeApi.ReasonSucceeded, "JenkinsFolder has been created")
+	}
+
+	if err := r.removeEnvLabelsFromRemovedStreams(ctx, pipeline); err != nil {
+		if statusErr := r.setFailedStatus(ctx, pipeline, err); statusErr != nil {
+			return reconcile.Result{}, statusErr
+		}
+
+		return reconcile.Result{}, err
 	}
 
 	if err := r.setFinishStatus(ctx, pipeline); err != nil {
@@ -151,6 +181,8 @@ func (r *ReconcileCDPipeline) tryToDeletePipeline(ctx context.Context, pipeline
 		}
 
 		log.Info("CDPipeline has active stages. Postpone deletion")
+		r.recorder.Event(pipeline, corev1.EventTypeNormal, cdPipeApi.EventReasonDeletionPostponed,
+			"Deletion is postponed until the stages of the pipeline are deleted")
 
 		return &reconcile.Result{RequeueAfter: waitForOwnedStagesDeletion}, nil
 	}
@@ -168,16 +200,20 @@ func (r *ReconcileCDPipeline) tryToDeletePipeline(ctx context.Context, pipeline
 	return &reconcile.Result{}, nil
 }
 
+// setFinishStatus marks the CDPipeline as ready. Other status fields are kept.
 func (r *ReconcileCDPipeline) setFinishStatus(ctx context.Context, p *cdPipeApi.CDPipeline) error {
-	p.Status = cdPipeApi.CDPipelineStatus{
-		Status:          consts.FinishedStatus,
-		Available:       true,
-		LastTimeUpdated: metaV1.Now(),
-		Username:        "system",
-		Action:          cdPipeApi.SetupInitialStructureForCDPipeline,
-		Result:          cdPipeApi.Success,
-		Value:           "active",
-	}
+	p.SetCondition(cdPipeApi.ConditionReady, metaV1.ConditionTrue, cdPipeApi.ReasonSucceeded, "CDPipeline has been reconciled successfully")
+
+	p.Status.Status = consts.FinishedStatus
+	p.Status.Available = true
+	p.Status.LastTimeUpdated = metaV1.Now()
+	p.Status.Username = "system"
+	p.Status.Action = cdPipeApi.SetupInitialStructureForCDPipeline
+	p.Status.Result = cdPipeApi.Success
+	p.Status.DetailedMessage = ""
+	p.Status.Value = "active"
+	p.Status.ObservedGeneration = p.Generation
+	p.Status.AppliedInputDockerStreams = slices.Clone(p.Spec.InputDockerStreams)
 
 	if err := r.client.Status().Update(ctx, p); err != nil {
 		if err = r.client.Update(ctx, p); err != nil {
@@ -188,6 +224,27 @@ func (r *ReconcileCDPipeline) setFinishStatus(ctx context.Context, p *cdPipeApi.
 	return nil
 }
 
+// setFailedStatus marks the CDPipeline as failed. Other status fields are kept.
+func (r *ReconcileCDPipeline) setFailedStatus(ctx context.Context, p *cdPipeApi.CDPipeline, err error) error {
+	p.SetCondition(cdPipeApi.ConditionReady, metaV1.ConditionFalse, cdPipeApi.ReasonFailed, err.Error())
+	r.recorder.Event(p, corev1.EventTypeWarning, cdPipeApi.EventReasonReconcileFailed, err.Error())
+
+	p.Status.Status = consts.FailedStatus
+	p.Status.Available = false
+	p.Status.LastTimeUpdated = metaV1.Now()
+	p.Status.Action = cdPipeApi.SetupInitialStructureForCDPipeline
+	p.Status.Result = cdPipeApi.Error
+	p.Status.DetailedMessage = err.Error()
+	p.Status.Value = consts.FailedStatus
+	p.Status.ObservedGeneration = p.Generation
+
+	if err = r.client.Status().Update(ctx, p); err != nil {
+		return fmt.Errorf("failed to update pipeline status: %w", err)
+	}
+
+	return nil
+}
+
 func (r *ReconcileCDPipeline) createJenkinsFolder(ctx context.Context, p *cdPipeApi.CDPipeline) error {
 	jfn := fmt.Sprintf("%v-%v", p.Name, "cd-pipeline")
 	log := r.log.WithValues("Jenkins folder name", jfn)
@@ -217,6 +274,53 @@ func (r *ReconcileCDPipeline) createJenkinsFolder(ctx context.Context, p *cdPipe
 	return nil
 }
 
+// removeEnvLabelsFromRemovedStreams removes the pipeline environment labels from the CodebaseImageStreams
+// which were applied during the last reconciliation but are not in the spec anymore.
+func (r *ReconcileCDPipeline) removeEnvLabelsFromRemovedStreams(ctx context.Context, pipeline *cdPipeApi.CDPipeline) error {
+	log := ctrl.LoggerFrom(ctx)
+	envLabelPrefix := pipeline.Name + "/"
+
+	for _, stream := range pipeline.Status.AppliedInputDockerStreams {
+		if slices.Contains(pipeline.Spec.InputDockerStreams, stream) {
+			continue
+		}
+
+		cis := &codebaseApi.CodebaseImageStream{}
+		if err := r.client.Get(ctx, client.ObjectKey{
+			Namespace: pipeline.Namespace,
+			Name:      cluster.CodebaseImageStreamName(stream),
+		}, cis); err != nil {
+			if k8sErrors.IsNotFound(err) {
+				continue
+			}
+
+			return fmt.Errorf("failed to get CodebaseImageStream %s: %w", stream, err)
+		}
+
+		changed := false
+
+		for label := range cis.Labels {
+			if strings.HasPrefix(label, envLabelPrefix) {
+				delete(cis.Labels, label)
+
+				changed = true
+			}
+		}
+
+		if !changed {
+			continue
+		}
+
+		if err := r.client.Update(ctx, cis); err != nil {
+			return fmt.Errorf("failed to update CodebaseImageStream %s: %w", stream, err)
+		}
+
+		log.Info("Environment labels have been removed from CodebaseImageStream", "stream", stream)
+	}
+
+	return nil
+}
+
 // hasActiveOwnedStages checks if there are any active stages owned by the pipeline.
 func (r *ReconcileCDPipeline) hasActiveOwnedStages(ctx context.Context, pipeline *cdPipeApi.CDPipeline) (bool, error) {
 	stages := &cdPipeApi.StageList{}
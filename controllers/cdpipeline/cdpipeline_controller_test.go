@@ -10,9 +10,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -20,6 +22,7 @@ import (
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/consts"
+	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
 	jenkinsApi "github.com/epam/edp-jenkins-operator/v2/pkg/apis/v2/v1"
 )
 
@@ -76,13 +79,16 @@ func TestNewReconcileCDPipeline_Success(t *testing.T) {
 	client := fake.NewClientBuilder().Build()
 	log := logr.Discard()
 
+	recorder := record.NewFakeRecorder(10)
+
 	expectedReconcileCdPipeline := &ReconcileCDPipeline{
-		client: client,
-		scheme: scheme,
-		log:    log.WithName("cd-pipeline"),
+		client:   client,
+		scheme:   scheme,
+		log:      log.WithName("cd-pipeline"),
+		recorder: recorder,
 	}
 
-	reconciledCdPipeline := NewReconcileCDPipeline(client, scheme, log)
+	reconciledCdPipeline := NewReconcileCDPipeline(client, scheme, log, recorder)
 	assert.Equal(t, expectedReconcileCdPipeline, reconciledCdPipeline)
 }
 
@@ -97,7 +103,7 @@ func TestReconcile_Success(t *testing.T) {
 	scheme := createScheme(t)
 	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(emptyCdPipeline, jenkins).Build()
 
-	reconcileCDPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard())
+	reconcileCDPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard(), record.NewFakeRecorder(10))
 
 	_, err := reconcileCDPipeline.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{
 		Namespace: namespace,
@@ -112,6 +118,8 @@ func TestReconcile_Success(t *testing.T) {
 	}, cdPipeline)
 	require.NoError(t, err)
 	assert.Equal(t, cdPipeline.Status.Status, "created")
+	assert.True(t, meta.IsStatusConditionTrue(cdPipeline.Status.Conditions, cdPipeApi.ConditionReady))
+	assert.True(t, meta.IsStatusConditionTrue(cdPipeline.Status.Conditions, cdPipeApi.ConditionJenkinsFolderReady))
 
 	jenkinsFolder := reconcileCDPipeline.getJenkinsFolder(t)
 	assert.Equal(t, jenkinsKind, jenkinsFolder.Kind)
@@ -124,7 +132,7 @@ func TestReconcile_PipelineIsNotFound(t *testing.T) {
 	scheme := createScheme(t)
 	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&cdPipeline).Build()
 
-	reconcileCDPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard())
+	reconcileCDPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard(), record.NewFakeRecorder(10))
 
 	_, err := reconcileCDPipeline.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{
 		Namespace: namespace,
@@ -138,7 +146,7 @@ func TestReconcile_GetCdPipelineError(t *testing.T) {
 	scheme := runtime.NewScheme()
 	client := fake.NewClientBuilder().WithScheme(scheme).Build()
 
-	reconcileCDPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard())
+	reconcileCDPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard(), record.NewFakeRecorder(10))
 
 	_, err := reconcileCDPipeline.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{
 		Namespace: namespace,
@@ -162,7 +170,7 @@ func TestAddFinalizer_DeletionTimestampNotZero(t *testing.T) {
 	scheme := createScheme(t)
 	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&cdPipeline).Build()
 
-	reconcileCdPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard())
+	reconcileCdPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard(), record.NewFakeRecorder(10))
 
 	res, err := reconcileCdPipeline.tryToDeletePipeline(ctrl.LoggerInto(context.Background(), logr.Discard()), &cdPipeline)
 	assert.NoError(t, err)
@@ -201,11 +209,14 @@ func TestAddFinalizer_PostponeDeletion(t *testing.T) {
 	scheme := createScheme(t)
 	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&cdPipeline, stage).Build()
 
-	reconcileCdPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard())
+	recorder := record.NewFakeRecorder(10)
+	reconcileCdPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard(), recorder)
 
 	res, err := reconcileCdPipeline.tryToDeletePipeline(ctrl.LoggerInto(context.Background(), logr.Discard()), &cdPipeline)
 	assert.NoError(t, err)
 	assert.Equal(t, &reconcile.Result{RequeueAfter: waitForOwnedStagesDeletion}, res)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, cdPipeApi.EventReasonDeletionPostponed)
 }
 
 func TestAddFinalizer_DeletionTimestampIsZero(t *testing.T) {
@@ -221,7 +232,7 @@ func TestAddFinalizer_DeletionTimestampIsZero(t *testing.T) {
 	scheme := createScheme(t)
 	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cdPipeline).Build()
 
-	reconcileCdPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard())
+	reconcileCdPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard(), record.NewFakeRecorder(10))
 
 	res, err := reconcileCdPipeline.tryToDeletePipeline(ctrl.LoggerInto(context.Background(), logr.Discard()), cdPipeline)
 	assert.NoError(t, err)
@@ -241,7 +252,7 @@ func TestSetFinishStatus_Success(t *testing.T) {
 	scheme := createScheme(t)
 	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cdPipeline).Build()
 
-	reconcileCdPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard())
+	reconcileCdPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard(), record.NewFakeRecorder(10))
 
 	err := reconcileCdPipeline.setFinishStatus(context.Background(), cdPipeline)
 	assert.NoError(t, err)
@@ -255,12 +266,69 @@ func TestSetFinishStatus_Success(t *testing.T) {
 	assert.Equal(t, cdPipelineProcessed.Status.Status, consts.FinishedStatus)
 }
 
+func TestReconcile_RemoveEnvLabelsFromRemovedStreams(t *testing.T) {
+	cdPipeline := emptyCdPipelineInit(t)
+	cdPipeline.Spec.InputDockerStreams = []string{"app-main"}
+	cdPipeline.Status.AppliedInputDockerStreams = []string{"app-main", "removed-app-main"}
+
+	newStream := func(streamName string) *codebaseApi.CodebaseImageStream {
+		return &codebaseApi.CodebaseImageStream{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      streamName,
+				Namespace: namespace,
+				Labels: map[string]string{
+					name + "/dev":   "",
+					name + "/qa":    "",
+					"other-pipe/qa": "",
+				},
+			},
+		}
+	}
+
+	scheme := createScheme(t)
+	require.NoError(t, codebaseApi.AddToScheme(scheme))
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(cdPipeline, newStream("app-main"), newStream("removed-app-main")).
+		Build()
+
+	reconcileCDPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard(), record.NewFakeRecorder(10))
+
+	_, err := reconcileCDPipeline.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}})
+	require.NoError(t, err)
+
+	removed := &codebaseApi.CodebaseImageStream{}
+	require.NoError(t, client.Get(context.Background(), types.NamespacedName{
+		Namespace: namespace,
+		Name:      "removed-app-main",
+	}, removed))
+	assert.Equal(t, map[string]string{"other-pipe/qa": ""}, removed.Labels)
+
+	kept := &codebaseApi.CodebaseImageStream{}
+	require.NoError(t, client.Get(context.Background(), types.NamespacedName{
+		Namespace: namespace,
+		Name:      "app-main",
+	}, kept))
+	assert.Len(t, kept.Labels, 3)
+
+	processed := &cdPipeApi.CDPipeline{}
+	require.NoError(t, client.Get(context.Background(), types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}, processed))
+	assert.Equal(t, []string{"app-main"}, processed.Status.AppliedInputDockerStreams)
+}
+
 func TestCreateJenkinsFolder_Success(t *testing.T) {
 	cdPipeline := emptyCdPipelineInit(t)
 	scheme := createScheme(t)
 	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cdPipeline).Build()
 
-	reconcileCdPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard())
+	reconcileCdPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard(), record.NewFakeRecorder(10))
 
 	err := reconcileCdPipeline.createJenkinsFolder(context.Background(), cdPipeline)
 	assert.NoError(t, err)
@@ -289,7 +357,7 @@ func TestCreateJenkinsFolder_AlreadyExists(t *testing.T) {
 	scheme := createScheme(t)
 	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cdPipeline, jenkins).Build()
 
-	reconcileCdPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard())
+	reconcileCdPipeline := NewReconcileCDPipeline(client, scheme, logr.Discard(), record.NewFakeRecorder(10))
 
 	err := reconcileCdPipeline.createJenkinsFolder(context.Background(), cdPipeline)
 	assert.NoError(t, err)
@@ -0,0 +1,187 @@
+package cdpipeline
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+)
+
+// NewReconcileCDPipelineOverview creates a new ReconcileCDPipelineOverview.
+func NewReconcileCDPipelineOverview(c client.Client, log logr.Logger) *ReconcileCDPipelineOverview {
+	return &ReconcileCDPipelineOverview{
+		client: c,
+		log:    log.WithName("cd-pipeline-overview"),
+	}
+}
+
+// ReconcileCDPipelineOverview refreshes the stages overview in the CDPipeline status.
+// It is separated from the CDPipeline reconciliation, so the stage changes don't reconcile the whole pipeline.
+type ReconcileCDPipelineOverview struct {
+	client client.Client
+	log    logr.Logger
+}
+
+func (r *ReconcileCDPipelineOverview) SetupWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("cd-pipeline-overview").
+		For(&cdPipeApi.CDPipeline{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &cdPipeApi.Stage{}},
+			handler.EnqueueRequestsFromMapFunc(mapStageToPipeline),
+			builder.WithPredicates(stageOverviewChangedPredicate()),
+		).
+		Complete(r); err != nil {
+		return fmt.Errorf("failed to create cd pipeline overview controller: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ReconcileCDPipelineOverview) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	pipeline := &cdPipeApi.CDPipeline{}
+	if err := r.client.Get(ctx, request.NamespacedName, pipeline); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, fmt.Errorf("failed to get pipeline: %w", err)
+	}
+
+	if !pipeline.GetDeletionTimestamp().IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	stages, err := r.getStagesOverview(ctx, pipeline)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if equality.Semantic.DeepEqual(pipeline.Status.Stages, stages) {
+		return reconcile.Result{}, nil
+	}
+
+	pipeline.Status.Stages = stages
+
+	if err = r.client.Status().Update(ctx, pipeline); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to update pipeline status: %w", err)
+	}
+
+	log.Info("CDPipeline stages overview has been updated")
+
+	return reconcile.Result{}, nil
+}
+
+// getStagesOverview returns the state of the pipeline stages in the promotion order.
+func (r *ReconcileCDPipelineOverview) getStagesOverview(ctx context.Context, pipeline *cdPipeApi.CDPipeline) ([]cdPipeApi.StageOverview, error) {
+	stages := &cdPipeApi.StageList{}
+	if err := r.client.List(
+		ctx,
+		stages,
+		client.InNamespace(pipeline.Namespace),
+		client.MatchingLabels{cdPipeApi.StageCdPipelineLabelName: pipeline.Name},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list stages: %w", err)
+	}
+
+	sort.Slice(stages.Items, func(i, j int) bool {
+		return stages.Items[i].Spec.Order < stages.Items[j].Spec.Order
+	})
+
+	overview := make([]cdPipeApi.StageOverview, 0, len(stages.Items))
+
+	for i := range stages.Items {
+		stage := newStageOverview(&stages.Items[i])
+
+		if i > 0 {
+			stage.LaggingApplications = getLaggingApplications(&overview[i-1], &stage)
+			stage.LagsBehind = len(stage.LaggingApplications) > 0
+		}
+
+		overview = append(overview, stage)
+	}
+
+	return overview, nil
+}
+
+func newStageOverview(stage *cdPipeApi.Stage) cdPipeApi.StageOverview {
+	return cdPipeApi.StageOverview{
+		Name:         stage.Spec.Name,
+		Order:        stage.Spec.Order,
+		Ready:        meta.IsStatusConditionTrue(stage.Status.Conditions, cdPipeApi.ConditionReady),
+		Namespace:    util.GetTargetNamespace(stage),
+		ClusterName:  stage.Spec.ClusterName,
+		Applications: stage.Status.Applications,
+	}
+}
+
+// getLaggingApplications returns the applications which verified tags in the stage differ from the previous stage.
+// Applications which haven't been verified in the previous stage yet are skipped.
+func getLaggingApplications(previous, stage *cdPipeApi.StageOverview) []string {
+	var lagging []string
+
+	for _, prev := range previous.Applications {
+		if prev.VerifiedTag == "" {
+			continue
+		}
+
+		for _, app := range stage.Applications {
+			if app.Name == prev.Name && app.VerifiedTag != prev.VerifiedTag {
+				lagging = append(lagging, app.Name)
+			}
+		}
+	}
+
+	return lagging
+}
+
+// stageOverviewChangedPredicate passes the Stage events which change the pipeline overview.
+func stageOverviewChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oo, ok := e.ObjectOld.(*cdPipeApi.Stage)
+			if !ok {
+				return false
+			}
+
+			no, ok := e.ObjectNew.(*cdPipeApi.Stage)
+			if !ok {
+				return false
+			}
+
+			return !reflect.DeepEqual(newStageOverview(oo), newStageOverview(no))
+		},
+	}
+}
+
+// mapStageToPipeline maps the Stage to its CDPipeline, so the pipeline overview is refreshed.
+func mapStageToPipeline(obj client.Object) []reconcile.Request {
+	stage, ok := obj.(*cdPipeApi.Stage)
+	if !ok || stage.Spec.CdPipeline == "" {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Namespace: stage.Namespace,
+		Name:      stage.Spec.CdPipeline,
+	}}}
+}
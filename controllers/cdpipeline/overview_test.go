@@ -0,0 +1,125 @@
+package cdpipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/consts"
+)
+
+func newOverviewStage(stageName string, order int, ready bool, versions map[string]string) *cdPipeApi.Stage {
+	stage := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      name + "-" + stageName,
+			Namespace: namespace,
+			Labels:    map[string]string{cdPipeApi.StageCdPipelineLabelName: name},
+		},
+		Spec: cdPipeApi.StageSpec{
+			Name:        stageName,
+			CdPipeline:  name,
+			Order:       order,
+			ClusterName: cdPipeApi.InCluster,
+			Namespace:   "ns-" + stageName,
+		},
+	}
+
+	if ready {
+		stage.SetCondition(cdPipeApi.ConditionReady, metaV1.ConditionTrue, cdPipeApi.ReasonSucceeded, "")
+	}
+
+	for _, app := range []string{"app1", "app2"} {
+		if tag, ok := versions[app]; ok {
+			stage.Status.Applications = append(stage.Status.Applications, cdPipeApi.ApplicationVersion{
+				Name:        app,
+				VerifiedTag: tag,
+			})
+		}
+	}
+
+	return stage
+}
+
+func TestReconcileCDPipelineOverview_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	scheme := createScheme(t)
+
+	pipeline := emptyCdPipelineInit(t)
+	pipeline.Status.Status = consts.FinishedStatus
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		pipeline,
+		newOverviewStage("prod", 2, false, map[string]string{"app1": "1.0.0", "app2": ""}),
+		newOverviewStage("dev", 0, true, map[string]string{"app1": "1.0.1", "app2": "2.0.0"}),
+		newOverviewStage("qa", 1, true, map[string]string{"app1": "1.0.0", "app2": "2.0.0"}),
+	).Build()
+
+	r := NewReconcileCDPipelineOverview(k8sClient, logr.Discard())
+
+	_, err := r.Reconcile(ctrl.LoggerInto(context.Background(), logr.Discard()), reconcile.Request{
+		NamespacedName: types.NamespacedName{Namespace: pipeline.Namespace, Name: pipeline.Name},
+	})
+	require.NoError(t, err)
+
+	gotPipeline := &cdPipeApi.CDPipeline{}
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Namespace: pipeline.Namespace, Name: pipeline.Name}, gotPipeline))
+	assert.Equal(t, consts.FinishedStatus, gotPipeline.Status.Status)
+
+	got := gotPipeline.Status.Stages
+
+	require.Len(t, got, 3)
+
+	assert.Equal(t, "dev", got[0].Name)
+	assert.True(t, got[0].Ready)
+	assert.Equal(t, "ns-dev", got[0].Namespace)
+	assert.Equal(t, cdPipeApi.InCluster, got[0].ClusterName)
+	assert.False(t, got[0].LagsBehind)
+
+	assert.Equal(t, "qa", got[1].Name)
+	assert.True(t, got[1].LagsBehind)
+	assert.Equal(t, []string{"app1"}, got[1].LaggingApplications)
+
+	assert.Equal(t, "prod", got[2].Name)
+	assert.False(t, got[2].Ready)
+	assert.True(t, got[2].LagsBehind)
+	assert.Equal(t, []string{"app2"}, got[2].LaggingApplications)
+}
+
+func TestStageOverviewChangedPredicate(t *testing.T) {
+	t.Parallel()
+
+	p := stageOverviewChangedPredicate()
+
+	stage := newOverviewStage("dev", 0, true, map[string]string{"app1": "1.0.0"})
+
+	updated := stage.DeepCopy()
+	updated.Status.LastTimeUpdated = metaV1.Now()
+
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: stage, ObjectNew: updated}))
+
+	updated.Status.Applications[0].VerifiedTag = "1.0.1"
+
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: stage, ObjectNew: updated}))
+	assert.True(t, p.Create(event.CreateEvent{Object: stage}))
+}
+
+func TestMapStageToPipeline(t *testing.T) {
+	t.Parallel()
+
+	got := mapStageToPipeline(newOverviewStage("dev", 0, true, nil))
+
+	require.Len(t, got, 1)
+	assert.Equal(t, name, got[0].Name)
+	assert.Equal(t, namespace, got[0].Namespace)
+}
@@ -0,0 +1,169 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/multiclusterclient"
+)
+
+const (
+	probeInterval       = 5 * time.Minute
+	failedProbeInterval = time.Minute
+	probeTimeout        = 10 * time.Second
+)
+
+// ServerVersionGetter returns the Kubernetes version of the cluster.
+type ServerVersionGetter func(config *rest.Config) (string, error)
+
+func NewReconcileCluster(c client.Client, scheme *runtime.Scheme, log logr.Logger) *ReconcileCluster {
+	return &ReconcileCluster{
+		client:        c,
+		scheme:        scheme,
+		log:           log.WithName("cluster"),
+		serverVersion: getServerVersion,
+	}
+}
+
+// ReconcileCluster checks connectivity of the external clusters.
+type ReconcileCluster struct {
+	client        client.Client
+	scheme        *runtime.Scheme
+	log           logr.Logger
+	serverVersion ServerVersionGetter
+}
+
+func (r *ReconcileCluster) SetupWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(&cdPipeApi.Cluster{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(NewSecretMapFunc(r.client, r.log)),
+		).
+		Complete(r); err != nil {
+		return fmt.Errorf("failed to create controller manager: %w", err)
+	}
+
+	return nil
+}
+
+//+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=clusters,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=clusters/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=clusters/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",namespace=placeholder,resources=secrets,verbs=get;list;watch
+
+// Reconcile probes the cluster and records the result in the Cluster status.
+// The cluster is probed periodically, failed clusters are probed more often.
+func (r *ReconcileCluster) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	log.Info("Reconciling Cluster")
+
+	cluster := &cdPipeApi.Cluster{}
+	if err := r.client.Get(ctx, request.NamespacedName, cluster); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	version, probeErr := r.probe(ctx, cluster)
+
+	cluster.Status = cdPipeApi.ClusterStatus{
+		Connected:     probeErr == nil,
+		ServerVersion: version,
+		LastProbeTime: metaV1.Now(),
+	}
+
+	requeue := probeInterval
+
+	if probeErr != nil {
+		log.Error(probeErr, "Cluster is unreachable")
+
+		cluster.Status.Message = probeErr.Error()
+		requeue = failedProbeInterval
+	}
+
+	if err := r.client.Status().Update(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to update cluster status: %w", err)
+	}
+
+	log.Info("Reconciling of Cluster has been finished", "connected", cluster.Status.Connected)
+
+	return reconcile.Result{RequeueAfter: requeue}, nil
+}
+
+func (r *ReconcileCluster) probe(ctx context.Context, cluster *cdPipeApi.Cluster) (string, error) {
+	restConfig, err := multiclusterclient.GetRestConfig(ctx, r.client, cluster.Namespace, cluster.Spec.SecretName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get cluster config: %w", err)
+	}
+
+	restConfig.Timeout = probeTimeout
+
+	version, err := r.serverVersion(restConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to get cluster version: %w", err)
+	}
+
+	return version, nil
+}
+
+// NewSecretMapFunc returns a function which maps Secret to the clusters that use it,
+// so the clusters are probed again as soon as the credentials are changed.
+func NewSecretMapFunc(c client.Client, log logr.Logger) func(obj client.Object) []reconcile.Request {
+	return func(obj client.Object) []reconcile.Request {
+		clusters := &cdPipeApi.ClusterList{}
+		if err := c.List(context.Background(), clusters, client.InNamespace(obj.GetNamespace())); err != nil {
+			log.Error(err, "unable to get clusters for secret", "secret", obj.GetName())
+			return nil
+		}
+
+		var requests []reconcile.Request
+
+		for i := range clusters.Items {
+			if clusters.Items[i].Spec.SecretName != obj.GetName() {
+				continue
+			}
+
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: clusters.Items[i].Namespace,
+				Name:      clusters.Items[i].Name,
+			}})
+		}
+
+		return requests
+	}
+}
+
+func getServerVersion(config *rest.Config) (string, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	info, err := dc.ServerVersion()
+	if err != nil {
+		return "", fmt.Errorf("failed to get server version: %w", err)
+	}
+
+	return info.GitVersion, nil
+}
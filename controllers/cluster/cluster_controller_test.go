@@ -0,0 +1,193 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/multiclusterclient"
+)
+
+const (
+	namespace = "stub-namespace"
+	name      = "stub-cluster"
+)
+
+func TestReconcileCluster_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	cluster := &cdPipeApi.Cluster{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: cdPipeApi.ClusterSpec{
+			SecretName: "cluster-secret",
+		},
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "cluster-secret",
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			multiclusterclient.ServerSecretKey: []byte("https://external-cluster:6443"),
+			multiclusterclient.TokenSecretKey:  []byte("token"),
+		},
+	}
+
+	tests := []struct {
+		name          string
+		objects       []client.Object
+		serverVersion ServerVersionGetter
+		wantResult    reconcile.Result
+		wantStatus    func(t *testing.T, status cdPipeApi.ClusterStatus)
+	}{
+		{
+			name:    "cluster is connected",
+			objects: []client.Object{cluster.DeepCopy(), secret},
+			serverVersion: func(config *rest.Config) (string, error) {
+				return "v1.26.1", nil
+			},
+			wantResult: reconcile.Result{RequeueAfter: probeInterval},
+			wantStatus: func(t *testing.T, status cdPipeApi.ClusterStatus) {
+				assert.True(t, status.Connected)
+				assert.Equal(t, "v1.26.1", status.ServerVersion)
+				assert.Empty(t, status.Message)
+				assert.False(t, status.LastProbeTime.IsZero())
+			},
+		},
+		{
+			name:    "cluster is unreachable",
+			objects: []client.Object{cluster.DeepCopy(), secret},
+			serverVersion: func(config *rest.Config) (string, error) {
+				return "", errors.New("connection refused")
+			},
+			wantResult: reconcile.Result{RequeueAfter: failedProbeInterval},
+			wantStatus: func(t *testing.T, status cdPipeApi.ClusterStatus) {
+				assert.False(t, status.Connected)
+				assert.Contains(t, status.Message, "connection refused")
+			},
+		},
+		{
+			name:    "cluster secret doesn't exist",
+			objects: []client.Object{cluster.DeepCopy()},
+			serverVersion: func(config *rest.Config) (string, error) {
+				return "v1.26.1", nil
+			},
+			wantResult: reconcile.Result{RequeueAfter: failedProbeInterval},
+			wantStatus: func(t *testing.T, status cdPipeApi.ClusterStatus) {
+				assert.False(t, status.Connected)
+				assert.Contains(t, status.Message, "failed to get secret cluster-secret")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewReconcileCluster(
+				fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build(),
+				scheme,
+				logr.Discard(),
+			)
+			r.serverVersion = tt.serverVersion
+
+			res, err := r.Reconcile(ctrl.LoggerInto(context.Background(), logr.Discard()), reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: namespace,
+					Name:      name,
+				},
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantResult, res)
+
+			got := &cdPipeApi.Cluster{}
+			require.NoError(t, r.client.Get(context.Background(), types.NamespacedName{
+				Namespace: namespace,
+				Name:      name,
+			}, got))
+
+			tt.wantStatus(t, got.Status)
+		})
+	}
+}
+
+func TestReconcileCluster_ReconcileNotFound(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	r := NewReconcileCluster(fake.NewClientBuilder().WithScheme(scheme).Build(), scheme, logr.Discard())
+
+	res, err := r.Reconcile(ctrl.LoggerInto(context.Background(), logr.Discard()), reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: namespace,
+			Name:      name,
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, res)
+}
+
+func TestNewSecretMapFunc(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	newCluster := func(name, namespace, secretName string) *cdPipeApi.Cluster {
+		return &cdPipeApi.Cluster{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: cdPipeApi.ClusterSpec{
+				SecretName: secretName,
+			},
+		}
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newCluster("dev", namespace, "dev-secret"),
+		newCluster("prod", namespace, "prod-secret"),
+		newCluster("dev", "other", "dev-secret"),
+	).Build()
+
+	mapFunc := NewSecretMapFunc(k8sClient, logr.Discard())
+
+	got := mapFunc(&corev1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "dev-secret",
+			Namespace: namespace,
+		},
+	})
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "dev", got[0].Name)
+	assert.Equal(t, namespace, got[0].Namespace)
+}
@@ -0,0 +1,395 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
+)
+
+func NewReconcilePromotion(
+	c client.Client,
+	scheme *runtime.Scheme,
+	log logr.Logger,
+	recorder record.EventRecorder,
+	webhooksEnabled bool,
+) *ReconcilePromotion {
+	return &ReconcilePromotion{
+		client:          c,
+		scheme:          scheme,
+		log:             log.WithName("promotion"),
+		recorder:        recorder,
+		webhooksEnabled: webhooksEnabled,
+	}
+}
+
+// ReconcilePromotion adds the promoted tags to the target stage verified CodebaseImageStreams.
+type ReconcilePromotion struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	log      logr.Logger
+	recorder record.EventRecorder
+	// webhooksEnabled shows if the Approvals are verified by the webhook.
+	// Approvals are not counted without the webhook, because the approver identity can't be trusted.
+	webhooksEnabled bool
+}
+
+// validationError is returned when the promotion can't be made with the current spec.
+// Such promotion is failed and isn't retried. The Promotion spec can't be changed, so a new Promotion should be created.
+type validationError struct {
+	msg string
+}
+
+func (e validationError) Error() string {
+	return e.msg
+}
+
+func newValidationError(format string, args ...interface{}) error {
+	return validationError{msg: fmt.Sprintf(format, args...)}
+}
+
+// imageStreamPromotion is a tag that should be added to the target CodebaseImageStream.
+type imageStreamPromotion struct {
+	application string
+	tag         string
+	target      *codebaseApi.CodebaseImageStream
+}
+
+func (r *ReconcilePromotion) SetupWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(&cdPipeApi.Promotion{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &cdPipeApi.Approval{}}, handler.EnqueueRequestsFromMapFunc(mapApprovalToPromotion)).
+		Complete(r); err != nil {
+		return fmt.Errorf("failed to create controller manager: %w", err)
+	}
+
+	return nil
+}
+
+//+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=promotions,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=promotions/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=promotions/finalizers,verbs=update
+//+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=approvals,verbs=get;list;watch
+//+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=stages,verbs=get;list;watch
+//+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=codebaseimagestreams,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups="",namespace=placeholder,resources=events,verbs=create;patch
+
+// Reconcile validates that the promoted tags exist in the source stage verified CodebaseImageStreams
+// and adds them to the target stage verified CodebaseImageStreams.
+// The tags are added only if all of them are valid and the promotion has enough approvals
+// if the target stage requires them. Completed promotions are not made again.
+func (r *ReconcilePromotion) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	log.Info("Reconciling Promotion")
+
+	promotion := &cdPipeApi.Promotion{}
+	if err := r.client.Get(ctx, request.NamespacedName, promotion); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, fmt.Errorf("failed to get promotion: %w", err)
+	}
+
+	if promotion.IsCompleted() {
+		log.Info("Promotion has been already completed")
+
+		return reconcile.Result{}, nil
+	}
+
+	promotions, target, err := r.getImageStreamPromotions(ctx, promotion)
+	if err != nil {
+		return r.handleError(ctx, promotion, err)
+	}
+
+	if target.Spec.Approval != nil && !r.webhooksEnabled {
+		return r.handleError(ctx, promotion, newValidationError(
+			"approvals of promotions to %s stage can't be verified because the webhooks are disabled", target.Spec.Name))
+	}
+
+	approvers, err := r.getApprovers(ctx, promotion, target)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if target.Spec.Approval != nil && len(approvers) < target.Spec.Approval.Required() {
+		msg := fmt.Sprintf("%d of %d approvals have been recorded for promotion to %s stage",
+			len(approvers), target.Spec.Approval.Required(), target.Spec.Name)
+
+		log.Info("Promotion is waiting for approval", "approvedBy", approvers)
+		r.recorder.Event(promotion, corev1.EventTypeNormal, cdPipeApi.EventReasonWaitingForApproval, msg)
+
+		return reconcile.Result{}, r.setStatus(ctx, promotion, cdPipeApi.PromotionPhaseWaitingForApproval, msg, nil, approvers)
+	}
+
+	if err = r.promote(ctx, promotions); err != nil {
+		return r.handleError(ctx, promotion, err)
+	}
+
+	streams := make([]cdPipeApi.PromotedImageStream, 0, len(promotions))
+	for _, p := range promotions {
+		streams = append(streams, cdPipeApi.PromotedImageStream{
+			Application: p.application,
+			Name:        p.target.Name,
+			Tag:         p.tag,
+		})
+	}
+
+	msg := fmt.Sprintf("%d tags have been promoted from %s to %s stage",
+		len(streams), promotion.Spec.SourceStage, promotion.Spec.TargetStage)
+	r.recorder.Event(promotion, corev1.EventTypeNormal, cdPipeApi.EventReasonPromoted, msg)
+
+	if err = r.setStatus(ctx, promotion, cdPipeApi.PromotionPhaseSucceeded, msg, streams, approvers); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	log.Info("Promotion has been completed")
+
+	return reconcile.Result{}, nil
+}
+
+// handleError marks the promotion as failed if the error is a validation error, otherwise the error is returned to retry.
+func (r *ReconcilePromotion) handleError(ctx context.Context, promotion *cdPipeApi.Promotion, err error) (reconcile.Result, error) {
+	var vErr validationError
+	if !errors.As(err, &vErr) {
+		return reconcile.Result{}, err
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Promotion has failed", "reason", err.Error())
+	r.recorder.Event(promotion, corev1.EventTypeWarning, cdPipeApi.EventReasonPromotionFailed, err.Error())
+
+	return reconcile.Result{}, r.setStatus(ctx, promotion, cdPipeApi.PromotionPhaseFailed, err.Error(), nil, nil)
+}
+
+// getImageStreamPromotions validates the promotion and returns the tags that should be added
+// to the target CodebaseImageStreams and the target stage.
+func (r *ReconcilePromotion) getImageStreamPromotions(
+	ctx context.Context,
+	promotion *cdPipeApi.Promotion,
+) ([]imageStreamPromotion, *cdPipeApi.Stage, error) {
+	source, target, err := r.getStages(ctx, promotion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if source.Spec.Order >= target.Spec.Order {
+		return nil, nil, newValidationError("target stage %s should follow source stage %s", target.Spec.Name, source.Spec.Name)
+	}
+
+	promotions := make([]imageStreamPromotion, 0, len(promotion.Spec.Applications))
+
+	for _, app := range promotion.Spec.Applications {
+		sourceStream, err := r.getVerifiedImageStream(ctx, promotion, source, app.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !hasTag(sourceStream, app.Tag) {
+			return nil, nil, newValidationError("tag %s of application %s doesn't exist in %s stage",
+				app.Tag, app.Name, source.Spec.Name)
+		}
+
+		targetStream, err := r.getVerifiedImageStream(ctx, promotion, target, app.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		promotions = append(promotions, imageStreamPromotion{
+			application: app.Name,
+			tag:         app.Tag,
+			target:      targetStream,
+		})
+	}
+
+	return promotions, target, nil
+}
+
+// getStages returns the source and target stages of the promotion.
+func (r *ReconcilePromotion) getStages(ctx context.Context, promotion *cdPipeApi.Promotion) (source, target *cdPipeApi.Stage, err error) {
+	stages := &cdPipeApi.StageList{}
+	if err = r.client.List(
+		ctx,
+		stages,
+		client.InNamespace(promotion.Namespace),
+		client.MatchingLabels{cdPipeApi.StageCdPipelineLabelName: promotion.Spec.Pipeline},
+	); err != nil {
+		return nil, nil, fmt.Errorf("failed to list stages: %w", err)
+	}
+
+	for i := range stages.Items {
+		switch stages.Items[i].Spec.Name {
+		case promotion.Spec.SourceStage:
+			source = &stages.Items[i]
+		case promotion.Spec.TargetStage:
+			target = &stages.Items[i]
+		}
+	}
+
+	if source == nil {
+		return nil, nil, newValidationError("source stage %s doesn't exist in %s pipeline", promotion.Spec.SourceStage, promotion.Spec.Pipeline)
+	}
+
+	if target == nil {
+		return nil, nil, newValidationError("target stage %s doesn't exist in %s pipeline", promotion.Spec.TargetStage, promotion.Spec.Pipeline)
+	}
+
+	return source, target, nil
+}
+
+// getVerifiedImageStream returns the stage verified CodebaseImageStream of the application.
+func (r *ReconcilePromotion) getVerifiedImageStream(
+	ctx context.Context,
+	promotion *cdPipeApi.Promotion,
+	stage *cdPipeApi.Stage,
+	application string,
+) (*codebaseApi.CodebaseImageStream, error) {
+	name := util.VerifiedImageStreamName(promotion.Spec.Pipeline, stage.Spec.Name, application)
+
+	stream := &codebaseApi.CodebaseImageStream{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: promotion.Namespace, Name: name}, stream); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, newValidationError("application %s doesn't have verified CodebaseImageStream %s in %s stage",
+				application, name, stage.Spec.Name)
+		}
+
+		return nil, fmt.Errorf("failed to get %s CodebaseImageStream: %w", name, err)
+	}
+
+	return stream, nil
+}
+
+// getApprovers returns the users whose Approvals of the promotion are counted by the target stage approval policy.
+// Only the Approvals of the promotion with the same UID and generation are counted,
+// so the Approvals of the deleted promotion with the same name are not reused.
+// Each user is counted once, and the users who are not approvers of the stage anymore are skipped.
+func (r *ReconcilePromotion) getApprovers(
+	ctx context.Context,
+	promotion *cdPipeApi.Promotion,
+	target *cdPipeApi.Stage,
+) ([]string, error) {
+	if target.Spec.Approval == nil {
+		return nil, nil
+	}
+
+	approvals := &cdPipeApi.ApprovalList{}
+	if err := r.client.List(ctx, approvals, client.InNamespace(promotion.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+
+	var approvers []string
+
+	for i := range approvals.Items {
+		approval := &approvals.Items[i]
+
+		if approval.Spec.Promotion != promotion.Name ||
+			approval.Spec.PromotionUID != promotion.UID ||
+			approval.Spec.PromotionGeneration != promotion.Generation ||
+			approval.Spec.Approver == "" ||
+			slices.Contains(approvers, approval.Spec.Approver) ||
+			!target.Spec.Approval.IsApprover(approval.Spec.Approver, approval.Spec.ApproverGroups) {
+			continue
+		}
+
+		approvers = append(approvers, approval.Spec.Approver)
+	}
+
+	sort.Strings(approvers)
+
+	return approvers, nil
+}
+
+// promote adds the tags to the target CodebaseImageStreams. Tags that already exist are skipped.
+func (r *ReconcilePromotion) promote(ctx context.Context, promotions []imageStreamPromotion) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	for _, p := range promotions {
+		if hasTag(p.target, p.tag) {
+			continue
+		}
+
+		patch := client.MergeFromWithOptions(p.target.DeepCopy(), client.MergeFromWithOptimisticLock{})
+
+		p.target.Spec.Tags = append(p.target.Spec.Tags, codebaseApi.Tag{
+			Name:    p.tag,
+			Created: now,
+		})
+
+		if err := r.client.Patch(ctx, p.target, patch); err != nil {
+			return fmt.Errorf("failed to add tag %s to %s CodebaseImageStream: %w", p.tag, p.target.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *ReconcilePromotion) setStatus(
+	ctx context.Context,
+	promotion *cdPipeApi.Promotion,
+	phase, msg string,
+	streams []cdPipeApi.PromotedImageStream,
+	approvers []string,
+) error {
+	promotion.Status = cdPipeApi.PromotionStatus{
+		Phase:              phase,
+		Message:            msg,
+		ImageStreams:       streams,
+		ApprovedBy:         approvers,
+		ObservedGeneration: promotion.Generation,
+	}
+
+	// The promotion which waits for approvals isn't completed yet.
+	if phase == cdPipeApi.PromotionPhaseSucceeded || phase == cdPipeApi.PromotionPhaseFailed {
+		now := metaV1.Now()
+		promotion.Status.CompletionTime = &now
+	}
+
+	if err := r.client.Status().Update(ctx, promotion); err != nil {
+		return fmt.Errorf("failed to update promotion status: %w", err)
+	}
+
+	return nil
+}
+
+func hasTag(stream *codebaseApi.CodebaseImageStream, tag string) bool {
+	for _, t := range stream.Spec.Tags {
+		if t.Name == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mapApprovalToPromotion maps the Approval to the approved Promotion, so the Promotion waiting for approval is made.
+func mapApprovalToPromotion(obj client.Object) []reconcile.Request {
+	approval, ok := obj.(*cdPipeApi.Approval)
+	if !ok || approval.Spec.Promotion == "" {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Namespace: approval.Namespace,
+		Name:      approval.Spec.Promotion,
+	}}}
+}
@@ -0,0 +1,377 @@
+package promotion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
+)
+
+const (
+	namespace = "stub-namespace"
+	name      = "stub-promotion"
+	pipeline  = "mypipeline"
+)
+
+func newStage(stageName string, order int) *cdPipeApi.Stage {
+	return &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      pipeline + "-" + stageName,
+			Namespace: namespace,
+			Labels:    map[string]string{cdPipeApi.StageCdPipelineLabelName: pipeline},
+		},
+		Spec: cdPipeApi.StageSpec{
+			Name:       stageName,
+			CdPipeline: pipeline,
+			Order:      order,
+		},
+	}
+}
+
+func newImageStream(stageName, app string, tags ...string) *codebaseApi.CodebaseImageStream {
+	stream := &codebaseApi.CodebaseImageStream{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      pipeline + "-" + stageName + "-" + app + "-verified",
+			Namespace: namespace,
+		},
+		Spec: codebaseApi.CodebaseImageStreamSpec{
+			Codebase: app,
+		},
+	}
+
+	for _, tag := range tags {
+		stream.Spec.Tags = append(stream.Spec.Tags, codebaseApi.Tag{Name: tag, Created: "2023-01-01T00:00:00Z"})
+	}
+
+	return stream
+}
+
+func newApproval(approvalName, approver string, groups ...string) *cdPipeApi.Approval {
+	return &cdPipeApi.Approval{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      approvalName,
+			Namespace: namespace,
+		},
+		Spec: cdPipeApi.ApprovalSpec{
+			Promotion:           name,
+			PromotionUID:        "promotion-uid",
+			PromotionGeneration: 1,
+			Approver:            approver,
+			ApproverGroups:      groups,
+		},
+	}
+}
+
+func newApprovalStage(stageName string, order int) *cdPipeApi.Stage {
+	stage := newStage(stageName, order)
+	stage.Spec.Approval = &cdPipeApi.ApprovalPolicy{
+		Users:             []string{"john"},
+		Groups:            []string{"release-managers"},
+		RequiredApprovals: 2,
+	}
+
+	return stage
+}
+
+func TestReconcilePromotion_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+	require.NoError(t, codebaseApi.AddToScheme(scheme))
+
+	promotion := &cdPipeApi.Promotion{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:       name,
+			Namespace:  namespace,
+			UID:        "promotion-uid",
+			Generation: 1,
+		},
+		Spec: cdPipeApi.PromotionSpec{
+			Pipeline:    pipeline,
+			SourceStage: "dev",
+			TargetStage: "qa",
+			Applications: []cdPipeApi.PromotedApplication{
+				{Name: "app1", Tag: "1.0.0"},
+				{Name: "app2", Tag: "2.0.0"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		promotion        *cdPipeApi.Promotion
+		objects          []client.Object
+		wantPhase        string
+		wantMessage      string
+		wantTags         map[string][]string
+		wantApprovedBy   []string
+		webhooksDisabled bool
+	}{
+		{
+			name:      "tags are promoted",
+			promotion: promotion.DeepCopy(),
+			objects: []client.Object{
+				newStage("dev", 0),
+				newStage("qa", 1),
+				newImageStream("dev", "app1", "0.9.0", "1.0.0"),
+				newImageStream("dev", "app2", "2.0.0"),
+				newImageStream("qa", "app1", "0.9.0"),
+				newImageStream("qa", "app2", "2.0.0"),
+			},
+			wantPhase:   cdPipeApi.PromotionPhaseSucceeded,
+			wantMessage: "2 tags have been promoted from dev to qa stage",
+			wantTags: map[string][]string{
+				"app1": {"0.9.0", "1.0.0"},
+				"app2": {"2.0.0"},
+			},
+		},
+		{
+			name:      "promotion waits for approval",
+			promotion: promotion.DeepCopy(),
+			objects: []client.Object{
+				newStage("dev", 0),
+				newApprovalStage("qa", 1),
+				newImageStream("dev", "app1", "1.0.0"),
+				newImageStream("dev", "app2", "2.0.0"),
+				newImageStream("qa", "app1"),
+				newImageStream("qa", "app2"),
+				newApproval("john-approval", "john"),
+				newApproval("john-second-approval", "john"),
+				newApproval("mallory-approval", "mallory", "developers"),
+				func() *cdPipeApi.Approval {
+					a := newApproval("jane-previous-promotion-approval", "jane", "release-managers")
+					a.Spec.PromotionUID = "previous-promotion-uid"
+
+					return a
+				}(),
+			},
+			wantPhase:   cdPipeApi.PromotionPhaseWaitingForApproval,
+			wantMessage: "1 of 2 approvals have been recorded for promotion to qa stage",
+			wantTags: map[string][]string{
+				"app1": nil,
+				"app2": nil,
+			},
+			wantApprovedBy: []string{"john"},
+		},
+		{
+			name:      "approved tags are promoted",
+			promotion: promotion.DeepCopy(),
+			objects: []client.Object{
+				newStage("dev", 0),
+				newApprovalStage("qa", 1),
+				newImageStream("dev", "app1", "1.0.0"),
+				newImageStream("dev", "app2", "2.0.0"),
+				newImageStream("qa", "app1"),
+				newImageStream("qa", "app2"),
+				newApproval("john-approval", "john"),
+				newApproval("jane-approval", "jane", "release-managers"),
+			},
+			wantPhase:   cdPipeApi.PromotionPhaseSucceeded,
+			wantMessage: "2 tags have been promoted from dev to qa stage",
+			wantTags: map[string][]string{
+				"app1": {"1.0.0"},
+				"app2": {"2.0.0"},
+			},
+			wantApprovedBy: []string{"jane", "john"},
+		},
+		{
+			name:      "approvals are not counted without webhooks",
+			promotion: promotion.DeepCopy(),
+			objects: []client.Object{
+				newStage("dev", 0),
+				newApprovalStage("qa", 1),
+				newImageStream("dev", "app1", "1.0.0"),
+				newImageStream("dev", "app2", "2.0.0"),
+				newImageStream("qa", "app1"),
+				newImageStream("qa", "app2"),
+				newApproval("john-approval", "john"),
+				newApproval("jane-approval", "jane", "release-managers"),
+			},
+			webhooksDisabled: true,
+			wantPhase:        cdPipeApi.PromotionPhaseFailed,
+			wantMessage:      "approvals of promotions to qa stage can't be verified because the webhooks are disabled",
+			wantTags: map[string][]string{
+				"app1": nil,
+				"app2": nil,
+			},
+		},
+		{
+			name:      "tag doesn't exist in source stage",
+			promotion: promotion.DeepCopy(),
+			objects: []client.Object{
+				newStage("dev", 0),
+				newStage("qa", 1),
+				newImageStream("dev", "app1", "0.9.0", "1.0.0"),
+				newImageStream("dev", "app2", "1.0.0"),
+				newImageStream("qa", "app1"),
+				newImageStream("qa", "app2"),
+			},
+			wantPhase:   cdPipeApi.PromotionPhaseFailed,
+			wantMessage: "tag 2.0.0 of application app2 doesn't exist in dev stage",
+			wantTags: map[string][]string{
+				"app1": nil,
+				"app2": nil,
+			},
+		},
+		{
+			name:      "target stage doesn't follow source stage",
+			promotion: promotion.DeepCopy(),
+			objects: []client.Object{
+				newStage("dev", 1),
+				newStage("qa", 0),
+				newImageStream("dev", "app1", "1.0.0"),
+				newImageStream("dev", "app2", "2.0.0"),
+				newImageStream("qa", "app1"),
+				newImageStream("qa", "app2"),
+			},
+			wantPhase:   cdPipeApi.PromotionPhaseFailed,
+			wantMessage: "target stage qa should follow source stage dev",
+			wantTags: map[string][]string{
+				"app1": nil,
+				"app2": nil,
+			},
+		},
+		{
+			name:      "target stage doesn't exist",
+			promotion: promotion.DeepCopy(),
+			objects: []client.Object{
+				newStage("dev", 0),
+			},
+			wantPhase:   cdPipeApi.PromotionPhaseFailed,
+			wantMessage: "target stage qa doesn't exist in mypipeline pipeline",
+		},
+		{
+			name:      "target image stream doesn't exist",
+			promotion: promotion.DeepCopy(),
+			objects: []client.Object{
+				newStage("dev", 0),
+				newStage("qa", 1),
+				newImageStream("dev", "app1", "1.0.0"),
+				newImageStream("dev", "app2", "2.0.0"),
+			},
+			wantPhase:   cdPipeApi.PromotionPhaseFailed,
+			wantMessage: "application app1 doesn't have verified CodebaseImageStream mypipeline-qa-app1-verified in qa stage",
+		},
+		{
+			name: "completed promotion is not made again",
+			promotion: func() *cdPipeApi.Promotion {
+				p := promotion.DeepCopy()
+				p.Status.Phase = cdPipeApi.PromotionPhaseSucceeded
+				p.Status.Message = "completed"
+
+				return p
+			}(),
+			objects: []client.Object{
+				newStage("dev", 0),
+				newStage("qa", 1),
+				newImageStream("dev", "app1", "1.0.0"),
+				newImageStream("dev", "app2", "2.0.0"),
+				newImageStream("qa", "app1"),
+				newImageStream("qa", "app2"),
+			},
+			wantPhase:   cdPipeApi.PromotionPhaseSucceeded,
+			wantMessage: "completed",
+			wantTags: map[string][]string{
+				"app1": nil,
+				"app2": nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewReconcilePromotion(
+				fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(tt.objects, tt.promotion)...).Build(),
+				scheme,
+				logr.Discard(),
+				record.NewFakeRecorder(10),
+				!tt.webhooksDisabled,
+			)
+
+			res, err := r.Reconcile(ctrl.LoggerInto(context.Background(), logr.Discard()), reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: namespace,
+					Name:      name,
+				},
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, reconcile.Result{}, res)
+
+			got := &cdPipeApi.Promotion{}
+			require.NoError(t, r.client.Get(context.Background(), types.NamespacedName{
+				Namespace: namespace,
+				Name:      name,
+			}, got))
+
+			assert.Equal(t, tt.wantPhase, got.Status.Phase)
+			assert.Equal(t, tt.wantMessage, got.Status.Message)
+			assert.Equal(t, tt.wantApprovedBy, got.Status.ApprovedBy)
+
+			if tt.wantPhase == cdPipeApi.PromotionPhaseWaitingForApproval {
+				assert.Nil(t, got.Status.CompletionTime)
+			}
+
+			for app, wantTags := range tt.wantTags {
+				stream := &codebaseApi.CodebaseImageStream{}
+				require.NoError(t, r.client.Get(context.Background(), types.NamespacedName{
+					Namespace: namespace,
+					Name:      pipeline + "-qa-" + app + "-verified",
+				}, stream))
+
+				var tags []string
+				for _, tag := range stream.Spec.Tags {
+					tags = append(tags, tag.Name)
+				}
+
+				assert.Equal(t, wantTags, tags)
+			}
+		})
+	}
+}
+
+func TestReconcilePromotion_ReconcileNotFound(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	r := NewReconcilePromotion(fake.NewClientBuilder().WithScheme(scheme).Build(), scheme, logr.Discard(), record.NewFakeRecorder(10), true)
+
+	res, err := r.Reconcile(ctrl.LoggerInto(context.Background(), logr.Discard()), reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: namespace,
+			Name:      name,
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, res)
+}
+
+func TestMapApprovalToPromotion(t *testing.T) {
+	t.Parallel()
+
+	got := mapApprovalToPromotion(newApproval("approval", "john"))
+
+	require.Len(t, got, 1)
+	assert.Equal(t, name, got[0].Name)
+	assert.Equal(t, namespace, got[0].Namespace)
+}
@@ -0,0 +1,317 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/handler"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+)
+
+// ApplyNamespaceTemplate applies the stage NamespaceTemplate resources in the stage namespace.
+// Resources are created or updated on every reconciliation, so the drift is corrected.
+// Resources which were applied before but are removed from the template are deleted.
+type ApplyNamespaceTemplate struct {
+	next handler.CdStageHandler
+	// client is used to get the NamespaceTemplate from the operator cluster.
+	client client.Client
+	// clusterClient is used to apply the resources in the cluster where the stage namespace is located.
+	clusterClient client.Client
+	log           logr.Logger
+}
+
+// ServeRequest applies the NamespaceTemplate if the stage references it.
+// If the stage doesn't reference the template anymore, the previously applied resources are deleted.
+func (h ApplyNamespaceTemplate) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	targetNamespace := util.GetTargetNamespace(stage)
+
+	if stage.Spec.NamespaceTemplate == "" {
+		if err := h.removeTemplate(ctx, stage, targetNamespace); err != nil {
+			return err
+		}
+
+		return nextServeOrNil(ctx, h.next, stage)
+	}
+
+	logger := h.log.WithValues("stage", stage.Name, "template", stage.Spec.NamespaceTemplate, "target-ns", targetNamespace)
+	logger.Info("Applying namespace template")
+
+	applied, err := h.applyTemplate(ctx, stage, targetNamespace)
+	if err == nil {
+		err = h.prune(ctx, stage.Status.NamespaceTemplateResources, applied, targetNamespace)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("failed to apply %s namespace template: %w", stage.Spec.NamespaceTemplate, err)
+		setConditionFailed(stage, cdPipeApi.ConditionNamespaceTemplateReady, err)
+
+		return err
+	}
+
+	stage.Status.NamespaceTemplateResources = applied
+
+	setConditionSucceeded(
+		stage,
+		cdPipeApi.ConditionNamespaceTemplateReady,
+		fmt.Sprintf("Namespace template %s has been applied", stage.Spec.NamespaceTemplate),
+	)
+
+	logger.Info("Namespace template has been applied")
+
+	return nextServeOrNil(ctx, h.next, stage)
+}
+
+// removeTemplate deletes the resources applied from the template which is not referenced by the stage anymore.
+func (h ApplyNamespaceTemplate) removeTemplate(ctx context.Context, stage *cdPipeApi.Stage, targetNamespace string) error {
+	if len(stage.Status.NamespaceTemplateResources) == 0 {
+		return nil
+	}
+
+	h.log.Info("Deleting namespace template resources", "stage", stage.Name, "target-ns", targetNamespace)
+
+	err := checkTargetNamespace(ctx, h.clusterClient, stage)
+	if err == nil {
+		err = h.prune(ctx, stage.Status.NamespaceTemplateResources, nil, targetNamespace)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("failed to delete namespace template resources: %w", err)
+		setConditionFailed(stage, cdPipeApi.ConditionNamespaceTemplateReady, err)
+
+		return err
+	}
+
+	stage.Status.NamespaceTemplateResources = nil
+	meta.RemoveStatusCondition(&stage.Status.Conditions, cdPipeApi.ConditionNamespaceTemplateReady)
+
+	return nil
+}
+
+// applyTemplate applies the template resources and returns the references to them.
+func (h ApplyNamespaceTemplate) applyTemplate(
+	ctx context.Context,
+	stage *cdPipeApi.Stage,
+	targetNamespace string,
+) ([]cdPipeApi.AppliedResource, error) {
+	if err := checkTargetNamespace(ctx, h.clusterClient, stage); err != nil {
+		return nil, err
+	}
+
+	template := &cdPipeApi.NamespaceTemplate{}
+	if err := h.client.Get(ctx, client.ObjectKey{
+		Namespace: stage.Namespace,
+		Name:      stage.Spec.NamespaceTemplate,
+	}, template); err != nil {
+		return nil, fmt.Errorf("failed to get namespace template: %w", err)
+	}
+
+	var applied []cdPipeApi.AppliedResource
+
+	if template.Spec.ResourceQuota != nil {
+		quota := &corev1.ResourceQuota{ObjectMeta: metaV1.ObjectMeta{
+			Name:      cdPipeApi.NamespaceTemplateResourceQuotaName,
+			Namespace: targetNamespace,
+		}}
+
+		if err := h.createOrUpdate(ctx, quota, template, func() {
+			template.Spec.ResourceQuota.DeepCopyInto(&quota.Spec)
+		}); err != nil {
+			return nil, err
+		}
+
+		applied = append(applied, appliedResource(corev1.SchemeGroupVersion.WithKind("ResourceQuota"), quota.Name))
+	}
+
+	if template.Spec.LimitRange != nil {
+		limitRange := &corev1.LimitRange{ObjectMeta: metaV1.ObjectMeta{
+			Name:      cdPipeApi.NamespaceTemplateLimitRangeName,
+			Namespace: targetNamespace,
+		}}
+
+		if err := h.createOrUpdate(ctx, limitRange, template, func() {
+			template.Spec.LimitRange.DeepCopyInto(&limitRange.Spec)
+		}); err != nil {
+			return nil, err
+		}
+
+		applied = append(applied, appliedResource(corev1.SchemeGroupVersion.WithKind("LimitRange"), limitRange.Name))
+	}
+
+	for i := range template.Spec.NetworkPolicies {
+		np := &template.Spec.NetworkPolicies[i]
+		policy := &networkingv1.NetworkPolicy{ObjectMeta: metaV1.ObjectMeta{
+			Name:      np.Name,
+			Namespace: targetNamespace,
+		}}
+
+		if err := h.createOrUpdate(ctx, policy, template, func() {
+			np.Spec.DeepCopyInto(&policy.Spec)
+		}); err != nil {
+			return nil, err
+		}
+
+		applied = append(applied, appliedResource(networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"), policy.Name))
+	}
+
+	for i := range template.Spec.Manifests {
+		resource, err := h.applyManifest(ctx, template.Spec.Manifests[i].Raw, targetNamespace, template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply manifest %d: %w", i, err)
+		}
+
+		applied = append(applied, resource)
+	}
+
+	return applied, nil
+}
+
+// applyManifest creates or updates the arbitrary namespaced resource from the template.
+// All the fields except metadata and status are taken from the template.
+func (h ApplyNamespaceTemplate) applyManifest(
+	ctx context.Context,
+	raw []byte,
+	targetNamespace string,
+	template *cdPipeApi.NamespaceTemplate,
+) (cdPipeApi.AppliedResource, error) {
+	desired := &unstructured.Unstructured{}
+	if err := json.Unmarshal(raw, &desired.Object); err != nil {
+		return cdPipeApi.AppliedResource{}, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+
+	if desired.GetName() == "" || desired.GetKind() == "" {
+		return cdPipeApi.AppliedResource{}, errors.New("manifest should contain kind and metadata.name")
+	}
+
+	gvk := desired.GroupVersionKind()
+
+	mapping, err := h.clusterClient.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return cdPipeApi.AppliedResource{}, fmt.Errorf("failed to get %s resource mapping: %w", gvk.Kind, err)
+	}
+
+	// The template is applied in the stage namespace only, cluster-scoped resources are not allowed.
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return cdPipeApi.AppliedResource{}, fmt.Errorf("%s %s is not a namespaced resource", gvk.Kind, desired.GetName())
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(desired.GroupVersionKind())
+	obj.SetName(desired.GetName())
+	obj.SetNamespace(targetNamespace)
+
+	if err := h.createOrUpdate(ctx, obj, template, func() {
+		for k, v := range desired.Object {
+			if k == "metadata" || k == "status" {
+				continue
+			}
+
+			obj.Object[k] = v
+		}
+
+		obj.SetLabels(mergeMaps(obj.GetLabels(), desired.GetLabels()))
+		obj.SetAnnotations(mergeMaps(obj.GetAnnotations(), desired.GetAnnotations()))
+	}); err != nil {
+		return cdPipeApi.AppliedResource{}, err
+	}
+
+	return appliedResource(gvk, desired.GetName()), nil
+}
+
+// createOrUpdate creates the object or updates it if it has been changed.
+// The object is labeled with the NamespaceTemplate name.
+func (h ApplyNamespaceTemplate) createOrUpdate(
+	ctx context.Context,
+	obj client.Object,
+	template *cdPipeApi.NamespaceTemplate,
+	mutate func(),
+) error {
+	res, err := controllerutil.CreateOrUpdate(ctx, h.clusterClient, obj, func() error {
+		mutate()
+		obj.SetLabels(mergeMaps(obj.GetLabels(), map[string]string{cdPipeApi.NamespaceTemplateLabelName: template.Name}))
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply %s: %w", obj.GetName(), err)
+	}
+
+	h.log.Info("Namespace template resource has been applied", "name", obj.GetName(), "result", res)
+
+	return nil
+}
+
+// prune deletes the previously applied resources which are not applied anymore.
+// Only the resources labeled with the NamespaceTemplateLabelName label are deleted.
+func (h ApplyNamespaceTemplate) prune(ctx context.Context, previous, applied []cdPipeApi.AppliedResource, targetNamespace string) error {
+	keep := make(map[cdPipeApi.AppliedResource]struct{}, len(applied))
+	for _, r := range applied {
+		keep[r] = struct{}{}
+	}
+
+	for _, r := range previous {
+		if _, ok := keep[r]; ok {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(r.APIVersion)
+		obj.SetKind(r.Kind)
+
+		if err := h.clusterClient.Get(ctx, client.ObjectKey{Namespace: targetNamespace, Name: r.Name}, obj); err != nil {
+			if k8sErrors.IsNotFound(err) {
+				continue
+			}
+
+			return fmt.Errorf("failed to get %s %s: %w", r.Kind, r.Name, err)
+		}
+
+		if _, ok := obj.GetLabels()[cdPipeApi.NamespaceTemplateLabelName]; !ok {
+			continue
+		}
+
+		if err := h.clusterClient.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete %s %s: %w", r.Kind, r.Name, err)
+		}
+
+		h.log.Info("Namespace template resource has been deleted", "kind", r.Kind, "name", r.Name)
+	}
+
+	return nil
+}
+
+func appliedResource(gvk schema.GroupVersionKind, name string) cdPipeApi.AppliedResource {
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+
+	return cdPipeApi.AppliedResource{APIVersion: apiVersion, Kind: kind, Name: name}
+}
+
+// mergeMaps returns a map with the values of dst overridden by the values of src.
+func mergeMaps(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+
+	for k, v := range src {
+		dst[k] = v
+	}
+
+	return dst
+}
@@ -0,0 +1,394 @@
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacApi "k8s.io/api/rbac/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+)
+
+func TestApplyNamespaceTemplate_ServeRequest(t *testing.T) {
+	t.Parallel()
+
+	const (
+		namespace       = "default"
+		targetNamespace = "team-dev"
+	)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, networkingv1.AddToScheme(scheme))
+	require.NoError(t, rbacApi.AddToScheme(scheme))
+
+	template := &cdPipeApi.NamespaceTemplate{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "small",
+			Namespace: namespace,
+		},
+		Spec: cdPipeApi.NamespaceTemplateSpec{
+			ResourceQuota: &corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{
+					corev1.ResourceLimitsCPU: resource.MustParse("2"),
+				},
+			},
+			LimitRange: &corev1.LimitRangeSpec{
+				Limits: []corev1.LimitRangeItem{
+					{
+						Type: corev1.LimitTypeContainer,
+						Default: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("500m"),
+						},
+					},
+				},
+			},
+			NetworkPolicies: []cdPipeApi.NetworkPolicyTemplate{
+				{
+					Name: "deny-all",
+					Spec: networkingv1.NetworkPolicySpec{
+						PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+					},
+				},
+			},
+			Manifests: []runtime.RawExtension{
+				{
+					Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings","labels":{"team":"a"}},"data":{"key":"value"}}`),
+				},
+			},
+		},
+	}
+
+	newStage := func(templateName string) *cdPipeApi.Stage {
+		return &cdPipeApi.Stage{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      "dev",
+				Namespace: namespace,
+			},
+			Spec: cdPipeApi.StageSpec{
+				Namespace:         targetNamespace,
+				NamespaceTemplate: templateName,
+			},
+		}
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(rbacApi.SchemeGroupVersion.WithKind("ClusterRole"), meta.RESTScopeRoot)
+
+	// The target namespace is created by the operator for the stage.
+	targetNs := &corev1.Namespace{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:   targetNamespace,
+			Labels: map[string]string{util.TenantLabelName: namespace, util.StageLabelName: "dev"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		stage     *cdPipeApi.Stage
+		objects   []client.Object
+		wantErr   require.ErrorAssertionFunc
+		wantCheck func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client)
+	}{
+		{
+			name:    "template is applied",
+			stage:   newStage("small"),
+			objects: []client.Object{template},
+			wantErr: require.NoError,
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				quota := &corev1.ResourceQuota{}
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      cdPipeApi.NamespaceTemplateResourceQuotaName,
+					Namespace: targetNamespace,
+				}, quota))
+				assert.Equal(t, "small", quota.Labels[cdPipeApi.NamespaceTemplateLabelName])
+				assert.Equal(t, resource.MustParse("2"), quota.Spec.Hard[corev1.ResourceLimitsCPU])
+
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      cdPipeApi.NamespaceTemplateLimitRangeName,
+					Namespace: targetNamespace,
+				}, &corev1.LimitRange{}))
+
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      "deny-all",
+					Namespace: targetNamespace,
+				}, &networkingv1.NetworkPolicy{}))
+
+				cm := &corev1.ConfigMap{}
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      "settings",
+					Namespace: targetNamespace,
+				}, cm))
+				assert.Equal(t, "value", cm.Data["key"])
+				assert.Equal(t, "a", cm.Labels["team"])
+				assert.Equal(t, "small", cm.Labels[cdPipeApi.NamespaceTemplateLabelName])
+
+				assert.True(t, meta.IsStatusConditionTrue(stage.Status.Conditions, cdPipeApi.ConditionNamespaceTemplateReady))
+			},
+		},
+		{
+			name:  "drift is corrected",
+			stage: newStage("small"),
+			objects: []client.Object{
+				template,
+				&corev1.ResourceQuota{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      cdPipeApi.NamespaceTemplateResourceQuotaName,
+						Namespace: targetNamespace,
+					},
+					Spec: corev1.ResourceQuotaSpec{
+						Hard: corev1.ResourceList{
+							corev1.ResourceLimitsCPU: resource.MustParse("100"),
+						},
+					},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      "settings",
+						Namespace: targetNamespace,
+					},
+					Data: map[string]string{"key": "changed"},
+				},
+			},
+			wantErr: require.NoError,
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				quota := &corev1.ResourceQuota{}
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      cdPipeApi.NamespaceTemplateResourceQuotaName,
+					Namespace: targetNamespace,
+				}, quota))
+				assert.Equal(t, resource.MustParse("2"), quota.Spec.Hard[corev1.ResourceLimitsCPU])
+				assert.Equal(t, "small", quota.Labels[cdPipeApi.NamespaceTemplateLabelName])
+
+				cm := &corev1.ConfigMap{}
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      "settings",
+					Namespace: targetNamespace,
+				}, cm))
+				assert.Equal(t, "value", cm.Data["key"])
+			},
+		},
+		{
+			name: "resources removed from template are pruned",
+			stage: func() *cdPipeApi.Stage {
+				s := newStage("small")
+				s.Status.NamespaceTemplateResources = []cdPipeApi.AppliedResource{
+					{APIVersion: "v1", Kind: "ResourceQuota", Name: cdPipeApi.NamespaceTemplateResourceQuotaName},
+					{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy", Name: "allow-all"},
+					{APIVersion: "v1", Kind: "ConfigMap", Name: "old-settings"},
+					{APIVersion: "v1", Kind: "ConfigMap", Name: "user-settings"},
+				}
+
+				return s
+			}(),
+			objects: []client.Object{
+				template,
+				&networkingv1.NetworkPolicy{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      "allow-all",
+						Namespace: targetNamespace,
+						Labels:    map[string]string{cdPipeApi.NamespaceTemplateLabelName: "small"},
+					},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      "old-settings",
+						Namespace: targetNamespace,
+						Labels:    map[string]string{cdPipeApi.NamespaceTemplateLabelName: "small"},
+					},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      "user-settings",
+						Namespace: targetNamespace,
+					},
+				},
+			},
+			wantErr: require.NoError,
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				require.True(t, k8sErrors.IsNotFound(k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      "allow-all",
+					Namespace: targetNamespace,
+				}, &networkingv1.NetworkPolicy{})))
+				require.True(t, k8sErrors.IsNotFound(k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      "old-settings",
+					Namespace: targetNamespace,
+				}, &corev1.ConfigMap{})))
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      "user-settings",
+					Namespace: targetNamespace,
+				}, &corev1.ConfigMap{}), "resource without template label should be kept")
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      cdPipeApi.NamespaceTemplateResourceQuotaName,
+					Namespace: targetNamespace,
+				}, &corev1.ResourceQuota{}))
+
+				assert.Equal(t, []cdPipeApi.AppliedResource{
+					{APIVersion: "v1", Kind: "ResourceQuota", Name: cdPipeApi.NamespaceTemplateResourceQuotaName},
+					{APIVersion: "v1", Kind: "LimitRange", Name: cdPipeApi.NamespaceTemplateLimitRangeName},
+					{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy", Name: "deny-all"},
+					{APIVersion: "v1", Kind: "ConfigMap", Name: "settings"},
+				}, stage.Status.NamespaceTemplateResources)
+			},
+		},
+		{
+			name: "resources of template removed from stage are deleted",
+			stage: func() *cdPipeApi.Stage {
+				s := newStage("")
+				s.Status.NamespaceTemplateResources = []cdPipeApi.AppliedResource{
+					{APIVersion: "v1", Kind: "ConfigMap", Name: "settings"},
+				}
+				s.SetCondition(cdPipeApi.ConditionNamespaceTemplateReady, metaV1.ConditionTrue, cdPipeApi.ReasonSucceeded, "")
+
+				return s
+			}(),
+			objects: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      "settings",
+						Namespace: targetNamespace,
+						Labels:    map[string]string{cdPipeApi.NamespaceTemplateLabelName: "small"},
+					},
+				},
+			},
+			wantErr: require.NoError,
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				require.True(t, k8sErrors.IsNotFound(k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      "settings",
+					Namespace: targetNamespace,
+				}, &corev1.ConfigMap{})))
+				assert.Empty(t, stage.Status.NamespaceTemplateResources)
+				assert.Nil(t, meta.FindStatusCondition(stage.Status.Conditions, cdPipeApi.ConditionNamespaceTemplateReady))
+			},
+		},
+		{
+			name:    "stage doesn't have template",
+			stage:   newStage(""),
+			wantErr: require.NoError,
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				assert.Nil(t, meta.FindStatusCondition(stage.Status.Conditions, cdPipeApi.ConditionNamespaceTemplateReady))
+			},
+		},
+		{
+			name: "target namespace isn't owned by the stage",
+			stage: func() *cdPipeApi.Stage {
+				s := newStage("small")
+				s.Spec.Namespace = "kube-system"
+
+				return s
+			}(),
+			objects: []client.Object{
+				template,
+				&corev1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "kube-system"}},
+			},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "namespace kube-system wasn't created for the stage")
+			},
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				require.Error(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      cdPipeApi.NamespaceTemplateResourceQuotaName,
+					Namespace: "kube-system",
+				}, &corev1.ResourceQuota{}))
+				assert.True(t, meta.IsStatusConditionFalse(stage.Status.Conditions, cdPipeApi.ConditionNamespaceTemplateReady))
+			},
+		},
+		{
+			name:  "template doesn't exist",
+			stage: newStage("large"),
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "failed to get namespace template")
+			},
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				assert.True(t, meta.IsStatusConditionFalse(stage.Status.Conditions, cdPipeApi.ConditionNamespaceTemplateReady))
+			},
+		},
+		{
+			name:  "invalid manifest",
+			stage: newStage("small"),
+			objects: []client.Object{
+				&cdPipeApi.NamespaceTemplate{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      "small",
+						Namespace: namespace,
+					},
+					Spec: cdPipeApi.NamespaceTemplateSpec{
+						Manifests: []runtime.RawExtension{
+							{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap"}`)},
+						},
+					},
+				},
+			},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "manifest should contain kind and metadata.name")
+			},
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {},
+		},
+		{
+			name:  "cluster-scoped manifest",
+			stage: newStage("small"),
+			objects: []client.Object{
+				&cdPipeApi.NamespaceTemplate{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      "small",
+						Namespace: namespace,
+					},
+					Spec: cdPipeApi.NamespaceTemplateSpec{
+						Manifests: []runtime.RawExtension{
+							{Raw: []byte(`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"admin"}}`)},
+						},
+					},
+				},
+			},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "ClusterRole admin is not a namespaced resource")
+			},
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				assert.True(t, meta.IsStatusConditionFalse(stage.Status.Conditions, cdPipeApi.ConditionNamespaceTemplateReady))
+
+				err := k8sClient.Get(context.Background(), client.ObjectKey{Name: "admin"}, &rbacApi.ClusterRole{})
+				assert.True(t, k8sErrors.IsNotFound(err))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRESTMapper(mapper).
+				WithObjects(append(tt.objects, targetNs.DeepCopy())...).
+				Build()
+
+			h := ApplyNamespaceTemplate{
+				client:        k8sClient,
+				clusterClient: k8sClient,
+				log:           logr.Discard(),
+			}
+
+			tt.wantErr(t, h.ServeRequest(context.Background(), tt.stage))
+			tt.wantCheck(t, tt.stage, k8sClient)
+		})
+	}
+}
@@ -25,22 +25,28 @@ type CheckNamespaceExist struct {
 }
 
 // ServeRequest serves request to check if namespace/project exists.
-func (h CheckNamespaceExist) ServeRequest(stage *cdPipeApi.Stage) error {
-	name := util.GenerateNamespaceName(stage)
+func (h CheckNamespaceExist) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	name := util.GetTargetNamespace(stage)
 
 	if platform.IsOpenshift() {
-		if err := h.projectExist(context.Background(), name); err != nil {
+		if err := h.projectExist(ctx, name); err != nil {
+			setConditionFailed(stage, cdPipeApi.ConditionNamespaceReady, err)
+
 			return err
 		}
 	}
 
 	if platform.IsKubernetes() {
-		if err := h.namespaceExist(context.Background(), name); err != nil {
+		if err := h.namespaceExist(ctx, name); err != nil {
+			setConditionFailed(stage, cdPipeApi.ConditionNamespaceReady, err)
+
 			return err
 		}
 	}
 
-	return nextServeOrNil(h.next, stage)
+	setConditionSucceeded(stage, cdPipeApi.ConditionNamespaceReady, fmt.Sprintf("Namespace %s exists", name))
+
+	return nextServeOrNil(ctx, h.next, stage)
 }
 
 func (h CheckNamespaceExist) namespaceExist(ctx context.Context, name string) error {
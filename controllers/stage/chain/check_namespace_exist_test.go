@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"context"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -56,6 +57,29 @@ func TestCheckNamespaceExist_ServeRequest(t *testing.T) {
 			},
 			wantErr: require.NoError,
 		},
+		{
+			name: "custom namespace exists",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Name:      "stage-1",
+					Namespace: "default",
+				},
+				Spec: cdPipeApi.StageSpec{
+					Namespace: "team-dev",
+				},
+			},
+			prepare: func(t *testing.T) {
+				t.Setenv(platform.TypeEnv, platform.Kubernetes)
+			},
+			objects: []client.Object{
+				&corev1.Namespace{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name: "team-dev",
+					},
+				},
+			},
+			wantErr: require.NoError,
+		},
 		{
 			name: "namespace doesn't exist",
 			stage: &cdPipeApi.Stage{
@@ -124,7 +148,7 @@ func TestCheckNamespaceExist_ServeRequest(t *testing.T) {
 				log:    logr.Discard(),
 			}
 
-			tt.wantErr(t, h.ServeRequest(tt.stage))
+			tt.wantErr(t, h.ServeRequest(context.Background(), tt.stage))
 		})
 	}
 }
@@ -0,0 +1,17 @@
+package chain
+
+import (
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+// setConditionSucceeded marks the stage condition of the given type as satisfied.
+func setConditionSucceeded(stage *cdPipeApi.Stage, conditionType, message string) {
+	stage.SetCondition(conditionType, metaV1.ConditionTrue, cdPipeApi.ReasonSucceeded, message)
+}
+
+// setConditionFailed marks the stage condition of the given type as not satisfied because of the error.
+func setConditionFailed(stage *cdPipeApi.Stage, conditionType string, err error) {
+	stage.SetCondition(conditionType, metaV1.ConditionFalse, cdPipeApi.ReasonFailed, err.Error())
+}
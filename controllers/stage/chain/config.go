@@ -0,0 +1,44 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// ChainsConfigMapName is a name of the ConfigMap in the operator namespace that overrides the chain steps.
+// Each key is a chain mode (e.g. ModeJenkinsAuto) with a YAML list of the step names,
+// the modes without their own key use the default steps.
+const ChainsConfigMapName = "cd-pipeline-operator-chains"
+
+// getChainSteps returns the steps of the chain mode from the ChainsConfigMapName ConfigMap or the default ones.
+func getChainSteps(ctx context.Context, c client.Client, namespace, mode string) ([]string, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{
+		Namespace: namespace,
+		Name:      ChainsConfigMapName,
+	}, cm); err != nil && !k8sErrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get %s ConfigMap: %w", ChainsConfigMapName, err)
+	}
+
+	if raw, ok := cm.Data[mode]; ok {
+		var steps []string
+		if err := yaml.Unmarshal([]byte(raw), &steps); err != nil {
+			return nil, fmt.Errorf("failed to parse %s key of %s ConfigMap: %w", mode, ChainsConfigMapName, err)
+		}
+
+		return steps, nil
+	}
+
+	steps, ok := defaultChains[mode]
+	if !ok {
+		return nil, fmt.Errorf("unknown chain mode %s", mode)
+	}
+
+	return slices.Clone(steps), nil
+}
@@ -18,39 +18,72 @@ const (
 	jenkinsAdminRbName   = "jenkins-admin"
 	adminClusterRoleName = "admin"
 	crNameLogKey         = "name"
+	jenkinsRbacComponent = "jenkins"
 )
 
-// ConfigureJenkinsRbac configures RBAC admin roles for Jenkins.
+// ConfigureJenkinsRbac creates role bindings for Jenkins in the stage target namespace.
+// Role bindings are taken from the tenant RBAC ConfigMap.
+// If they are not configured, the jenkins ServiceAccount of the tenant gets the admin ClusterRole.
 type ConfigureJenkinsRbac struct {
-	next   handler.CdStageHandler
+	next handler.CdStageHandler
+	// client is used to get the RBAC configuration from the operator cluster.
 	client client.Client
-	log    logr.Logger
-	rbac   rbac.Manager
+	// clusterClient is used to get the stage namespace from the cluster where it is located.
+	clusterClient client.Client
+	log           logr.Logger
+	rbac          rbac.Manager
 }
 
-// ServeRequest creates RoleBinding for Jenkins admin role.
-func (h ConfigureJenkinsRbac) ServeRequest(stage *cdPipeApi.Stage) error {
-	targetNamespace := util.GenerateNamespaceName(stage)
+// ServeRequest creates RoleBindings for Jenkins.
+func (h ConfigureJenkinsRbac) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	targetNamespace := util.GetTargetNamespace(stage)
 	logger := h.log.WithValues("stage", stage.Name, "target-ns", targetNamespace)
 	logger.Info("Configuring RBAC for Jenkins")
 
-	if err := h.rbac.CreateRoleBindingIfNotExists(
-		context.TODO(),
-		jenkinsAdminRbName,
-		targetNamespace,
-		getJenkinsAdminRoleSubjects(stage.Namespace),
-		rbacApi.RoleRef{
-			Name:     adminClusterRoleName,
-			APIGroup: rbacApi.GroupName,
-			Kind:     rbac.ClusterRoleKind,
-		},
-	); err != nil {
-		return fmt.Errorf("failed to create %s rolebinding: %w", jenkinsAdminRbName, err)
+	if err := checkTargetNamespace(ctx, h.clusterClient, stage); err != nil {
+		setConditionFailed(stage, cdPipeApi.ConditionRBACReady, err)
+
+		return err
+	}
+
+	roleBindings, err := rbac.GetJenkinsRoleBindings(ctx, h.client, stage)
+	if err != nil {
+		err = fmt.Errorf("failed to get jenkins role bindings configuration: %w", err)
+		setConditionFailed(stage, cdPipeApi.ConditionRBACReady, err)
+
+		return err
+	}
+
+	if len(roleBindings) == 0 {
+		roleBindings = getJenkinsAdminRoleBindings(stage.Namespace)
+	}
+
+	if err = applyRoleBindings(ctx, h.rbac, roleBindings, targetNamespace, util.GetRbacLabels(stage, jenkinsRbacComponent)); err != nil {
+		setConditionFailed(stage, cdPipeApi.ConditionRBACReady, err)
+
+		return err
 	}
 
+	setConditionSucceeded(stage, cdPipeApi.ConditionRBACReady, fmt.Sprintf("%d Jenkins RoleBindings have been configured", len(roleBindings)))
+
 	logger.Info("RBAC for Jenkins has been configured successfully")
 
-	return nextServeOrNil(h.next, stage)
+	return nextServeOrNil(ctx, h.next, stage)
+}
+
+// getJenkinsAdminRoleBindings returns the default role bindings for Jenkins.
+func getJenkinsAdminRoleBindings(sourceNamespace string) []cdPipeApi.RoleBindingTemplate {
+	return []cdPipeApi.RoleBindingTemplate{
+		{
+			Name:     jenkinsAdminRbName,
+			Subjects: getJenkinsAdminRoleSubjects(sourceNamespace),
+			RoleRef: &rbacApi.RoleRef{
+				APIGroup: rbacApi.GroupName,
+				Kind:     rbac.ClusterRoleKind,
+				Name:     adminClusterRoleName,
+			},
+		},
+	}
 }
 
 func getJenkinsAdminRoleSubjects(sourceNamespace string) []rbacApi.Subject {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	rbacApi "k8s.io/api/rbac/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -24,6 +25,7 @@ func TestConfigureJenkinsRbac_ServeRequest(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, cdPipeApi.AddToScheme(scheme))
 	require.NoError(t, rbacApi.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
 
 	tests := []struct {
 		name      string
@@ -72,10 +74,15 @@ func TestConfigureJenkinsRbac_ServeRequest(t *testing.T) {
 			},
 			wantErr: require.NoError,
 			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				rb := &rbacApi.RoleBinding{}
 				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
 					Name:      jenkinsAdminRbName,
 					Namespace: util.GenerateNamespaceName(stage),
-				}, &rbacApi.RoleBinding{}))
+				}, rb))
+				require.Equal(t, adminClusterRoleName, rb.RoleRef.Name)
+				require.Equal(t, getJenkinsAdminRoleSubjects(namespace), rb.Subjects)
+				require.Equal(t, rbac.ManagedByLabelValue, rb.Labels[rbac.ManagedByLabelName])
+				require.Equal(t, jenkinsRbacComponent, rb.Labels[util.RbacComponentLabelName])
 			},
 		},
 		{
@@ -97,6 +104,61 @@ func TestConfigureJenkinsRbac_ServeRequest(t *testing.T) {
 				}, &rbacApi.RoleBinding{}))
 			},
 		},
+		{
+			name: "role bindings from ConfigMap",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "test-stage",
+				},
+			},
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      rbac.StageRoleBindingsConfigMapName,
+						Namespace: namespace,
+					},
+					Data: map[string]string{
+						rbac.JenkinsRoleBindingsKey: `
+- name: jenkins-deployer
+  subjects:
+    - kind: ServiceAccount
+      name: jenkins
+  roleRef:
+    kind: ClusterRole
+    name: edit
+`,
+					},
+				},
+				&rbacApi.RoleBinding{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      jenkinsAdminRbName,
+						Namespace: "test-ns-test-stage",
+						Labels: map[string]string{
+							util.TenantLabelName:        namespace,
+							util.StageLabelName:         "test-stage",
+							util.RbacComponentLabelName: jenkinsRbacComponent,
+							rbac.ManagedByLabelName:     rbac.ManagedByLabelValue,
+						},
+					},
+				},
+			},
+			wantErr: require.NoError,
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				rb := &rbacApi.RoleBinding{}
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      "jenkins-deployer",
+					Namespace: util.GenerateNamespaceName(stage),
+				}, rb))
+				require.Equal(t, "edit", rb.RoleRef.Name)
+				require.Equal(t, getJenkinsAdminRoleSubjects(namespace), rb.Subjects)
+
+				require.Error(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      jenkinsAdminRbName,
+					Namespace: util.GenerateNamespaceName(stage),
+				}, &rbacApi.RoleBinding{}), "default role binding should be pruned")
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -107,12 +169,13 @@ func TestConfigureJenkinsRbac_ServeRequest(t *testing.T) {
 			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tt.objects...).Build()
 
 			h := ConfigureJenkinsRbac{
-				client: k8sClient,
-				log:    logr.Discard(),
-				rbac:   rbac.NewRbacManager(k8sClient, logr.Discard()),
+				client:        k8sClient,
+				clusterClient: k8sClient,
+				log:           logr.Discard(),
+				rbac:          rbac.NewRbacManager(k8sClient, logr.Discard()),
 			}
 
-			err := h.ServeRequest(tt.stage)
+			err := h.ServeRequest(context.Background(), tt.stage)
 			tt.wantErr(t, err)
 			tt.wantCheck(t, tt.stage, k8sClient)
 		})
@@ -15,6 +15,8 @@ import (
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/rbac"
 )
 
+const registryViewerRbacComponent = "registry-viewer"
+
 type ConfigureRegistryViewerRbac struct {
 	next   handler.CdStageHandler
 	client client.Client
@@ -22,8 +24,8 @@ type ConfigureRegistryViewerRbac struct {
 	rbac   rbac.Manager
 }
 
-func (h ConfigureRegistryViewerRbac) ServeRequest(stage *cdPipeApi.Stage) error {
-	targetNamespace := util.GenerateNamespaceName(stage)
+func (h ConfigureRegistryViewerRbac) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	targetNamespace := util.GetTargetNamespace(stage)
 	roleBindingName := generateSaRegistryViewerRoleBindingName(stage)
 	logger := h.log.WithValues("stage", stage.Name, "targetNamespace", targetNamespace, "roleBindingName", roleBindingName)
 
@@ -32,11 +34,11 @@ func (h ConfigureRegistryViewerRbac) ServeRequest(stage *cdPipeApi.Stage) error
 	if !platform.IsOpenshift() {
 		logger.Info("Skip configuring RoleBinding sa-registry-viewer for non-openshift platform")
 
-		return nextServeOrNil(h.next, stage)
+		return nextServeOrNil(ctx, h.next, stage)
 	}
 
-	if err := h.rbac.CreateRoleBindingIfNotExists(
-		context.TODO(),
+	if err := h.rbac.CreateOrUpdateRoleBinding(
+		ctx,
 		roleBindingName,
 		stage.Namespace,
 		[]rbacApi.Subject{
@@ -51,13 +53,19 @@ func (h ConfigureRegistryViewerRbac) ServeRequest(stage *cdPipeApi.Stage) error
 			APIGroup: rbacApi.GroupName,
 			Name:     "registry-viewer",
 		},
+		util.GetRbacLabels(stage, registryViewerRbacComponent),
 	); err != nil {
-		return fmt.Errorf("failed to create %s RoleBinding: %w", roleBindingName, err)
+		err = fmt.Errorf("failed to create %s RoleBinding: %w", roleBindingName, err)
+		setConditionFailed(stage, cdPipeApi.ConditionRBACReady, err)
+
+		return err
 	}
 
+	setConditionSucceeded(stage, cdPipeApi.ConditionRBACReady, fmt.Sprintf("RoleBinding %s has been configured", roleBindingName))
+
 	logger.Info("RoleBinding sa-registry-viewer has been configured")
 
-	return nextServeOrNil(h.next, stage)
+	return nextServeOrNil(ctx, h.next, stage)
 }
 
 // generateSaRegistryViewerRoleBindingName generates name for RoleBinding for registry-viewer role.
@@ -119,7 +119,7 @@ func TestConfigureRegistryViewerRbac_ServeRequest(t *testing.T) {
 				rbac:   rbac.NewRbacManager(k8sClient, logr.Discard()),
 			}
 
-			err := h.ServeRequest(tt.stage)
+			err := h.ServeRequest(context.Background(), tt.stage)
 			tt.wantErr(t, err)
 			tt.wantCheck(t, tt.stage, k8sClient)
 		})
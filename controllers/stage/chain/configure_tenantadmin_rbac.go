@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	rbacApi "k8s.io/api/rbac/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
@@ -15,47 +17,87 @@ import (
 )
 
 const (
-	tenantAdminRbName = "tenant-admin"
+	tenantAdminRbName   = "tenant-admin"
+	tenantRbacComponent = "tenant"
 )
 
+// ConfigureTenantAdminRbac creates role bindings for the tenant users in the stage target namespace.
+// Role bindings are taken from the stage spec or the tenant RBAC ConfigMap.
+// If they are not configured, the tenant OIDC admins and developers groups get the admin ClusterRole.
 type ConfigureTenantAdminRbac struct {
-	next   handler.CdStageHandler
+	next handler.CdStageHandler
+	// client is used to get the RBAC configuration from the operator cluster.
 	client client.Client
-	log    logr.Logger
-	rbac   rbac.Manager
+	// clusterClient is used to get the stage namespace from the cluster where it is located.
+	clusterClient client.Client
+	log           logr.Logger
+	// rbac manages RBAC in the cluster where the stage namespace is located.
+	rbac     rbac.Manager
+	recorder record.EventRecorder
 }
 
-func (h ConfigureTenantAdminRbac) ServeRequest(stage *cdPipeApi.Stage) error {
-	targetNamespace := util.GenerateNamespaceName(stage)
+func (h ConfigureTenantAdminRbac) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	targetNamespace := util.GetTargetNamespace(stage)
 	logger := h.log.WithValues("stage", stage.Name, "target-ns", targetNamespace)
 	logger.Info("Configuring tenant admin RBAC")
 
-	if err := h.rbac.CreateRoleBindingIfNotExists(
-		context.TODO(),
-		tenantAdminRbName,
-		targetNamespace,
-		[]rbacApi.Subject{
-			{
-				APIGroup: rbacApi.GroupName,
-				Kind:     rbacApi.GroupKind,
-				Name:     fmt.Sprintf("%s-oidc-admins", stage.Namespace),
+	if err := checkTargetNamespace(ctx, h.clusterClient, stage); err != nil {
+		setConditionFailed(stage, cdPipeApi.ConditionRBACReady, err)
+
+		return err
+	}
+
+	roleBindings, err := rbac.GetStageRoleBindings(ctx, h.client, stage)
+	if err != nil {
+		err = fmt.Errorf("failed to get role bindings configuration: %w", err)
+		setConditionFailed(stage, cdPipeApi.ConditionRBACReady, err)
+
+		return err
+	}
+
+	if len(roleBindings) == 0 {
+		roleBindings = getTenantAdminRoleBindings(stage.Namespace)
+	}
+
+	labels := util.GetRbacLabels(stage, tenantRbacComponent)
+
+	if err = applyRoleBindings(ctx, h.rbac, roleBindings, targetNamespace, labels); err != nil {
+		setConditionFailed(stage, cdPipeApi.ConditionRBACReady, err)
+
+		return err
+	}
+
+	setConditionSucceeded(stage, cdPipeApi.ConditionRBACReady, fmt.Sprintf("%d RoleBindings have been configured", len(roleBindings)))
+	h.recorder.Eventf(stage, corev1.EventTypeNormal, cdPipeApi.EventReasonRBACConfigured,
+		"%d RoleBindings have been configured in namespace %s", len(roleBindings), targetNamespace)
+
+	logger.Info("RBAC for tenant admin has been configured successfully")
+
+	return nextServeOrNil(ctx, h.next, stage)
+}
+
+// getTenantAdminRoleBindings returns the default role bindings for the tenant users.
+func getTenantAdminRoleBindings(tenant string) []cdPipeApi.RoleBindingTemplate {
+	return []cdPipeApi.RoleBindingTemplate{
+		{
+			Name: tenantAdminRbName,
+			Subjects: []rbacApi.Subject{
+				{
+					APIGroup: rbacApi.GroupName,
+					Kind:     rbacApi.GroupKind,
+					Name:     fmt.Sprintf("%s-oidc-admins", tenant),
+				},
+				{
+					APIGroup: rbacApi.GroupName,
+					Kind:     rbacApi.GroupKind,
+					Name:     fmt.Sprintf("%s-oidc-developers", tenant),
+				},
 			},
-			{
+			RoleRef: &rbacApi.RoleRef{
 				APIGroup: rbacApi.GroupName,
-				Kind:     rbacApi.GroupKind,
-				Name:     fmt.Sprintf("%s-oidc-developers", stage.Namespace),
+				Kind:     rbac.ClusterRoleKind,
+				Name:     adminClusterRoleName,
 			},
 		},
-		rbacApi.RoleRef{
-			APIGroup: rbacApi.GroupName,
-			Kind:     rbac.ClusterRoleKind,
-			Name:     "admin",
-		},
-	); err != nil {
-		return fmt.Errorf("failed to create %s rolebinding: %w", tenantAdminRbName, err)
 	}
-
-	logger.Info("RBAC for tenant admin has been configured successfully")
-
-	return nextServeOrNil(h.next, stage)
 }
@@ -6,9 +6,13 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	rbacApi "k8s.io/api/rbac/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -25,6 +29,7 @@ func TestConfigureTenantAdminRbac_ServeRequest(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, cdPipeApi.AddToScheme(scheme))
 	require.NoError(t, rbacApi.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
 
 	tests := []struct {
 		name      string
@@ -47,6 +52,7 @@ func TestConfigureTenantAdminRbac_ServeRequest(t *testing.T) {
 					Name:      tenantAdminRbName,
 					Namespace: util.GenerateNamespaceName(stage),
 				}, &rbacApi.RoleBinding{}))
+				require.True(t, meta.IsStatusConditionTrue(stage.Status.Conditions, cdPipeApi.ConditionRBACReady))
 			},
 		},
 		{
@@ -78,6 +84,253 @@ func TestConfigureTenantAdminRbac_ServeRequest(t *testing.T) {
 				}, &rbacApi.RoleBinding{}))
 			},
 		},
+		{
+			name: "manually edited rbac is corrected",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "test-stage",
+				},
+			},
+			objects: []runtime.Object{
+				&rbacApi.RoleBinding{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      tenantAdminRbName,
+						Namespace: "test-ns-test-stage",
+					},
+					Subjects: []rbacApi.Subject{
+						{APIGroup: rbacApi.GroupName, Kind: rbacApi.UserKind, Name: "intruder"},
+					},
+					RoleRef: rbacApi.RoleRef{APIGroup: rbacApi.GroupName, Kind: rbac.ClusterRoleKind, Name: "cluster-admin"},
+				},
+			},
+			wantErr: require.NoError,
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				rb := &rbacApi.RoleBinding{}
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      tenantAdminRbName,
+					Namespace: util.GenerateNamespaceName(stage),
+				}, rb))
+				require.Equal(t, adminClusterRoleName, rb.RoleRef.Name)
+				require.Equal(t, getTenantAdminRoleBindings(namespace)[0].Subjects, rb.Subjects)
+				require.Equal(t, tenantRbacComponent, rb.Labels[util.RbacComponentLabelName])
+			},
+		},
+		{
+			name: "orphaned rbac is pruned",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "test-stage",
+				},
+			},
+			objects: []runtime.Object{
+				&rbacApi.RoleBinding{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      "removed",
+						Namespace: "test-ns-test-stage",
+						Labels: map[string]string{
+							rbac.ManagedByLabelName:     rbac.ManagedByLabelValue,
+							util.TenantLabelName:        namespace,
+							util.StageLabelName:         "test-stage",
+							util.RbacComponentLabelName: tenantRbacComponent,
+						},
+					},
+				},
+				&rbacApi.Role{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      "removed",
+						Namespace: "test-ns-test-stage",
+						Labels: map[string]string{
+							rbac.ManagedByLabelName:     rbac.ManagedByLabelValue,
+							util.TenantLabelName:        namespace,
+							util.StageLabelName:         "test-stage",
+							util.RbacComponentLabelName: tenantRbacComponent,
+						},
+					},
+				},
+				&rbacApi.RoleBinding{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      jenkinsAdminRbName,
+						Namespace: "test-ns-test-stage",
+						Labels: map[string]string{
+							rbac.ManagedByLabelName:     rbac.ManagedByLabelValue,
+							util.TenantLabelName:        namespace,
+							util.StageLabelName:         "test-stage",
+							util.RbacComponentLabelName: jenkinsRbacComponent,
+						},
+					},
+				},
+			},
+			wantErr: require.NoError,
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				targetNs := util.GenerateNamespaceName(stage)
+
+				require.True(t, k8sErrors.IsNotFound(k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      "removed",
+					Namespace: targetNs,
+				}, &rbacApi.RoleBinding{})))
+				require.True(t, k8sErrors.IsNotFound(k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      "removed",
+					Namespace: targetNs,
+				}, &rbacApi.Role{})))
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      jenkinsAdminRbName,
+					Namespace: targetNs,
+				}, &rbacApi.RoleBinding{}))
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      tenantAdminRbName,
+					Namespace: targetNs,
+				}, &rbacApi.RoleBinding{}))
+			},
+		},
+		{
+			name: "role bindings from stage spec",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "test-stage",
+				},
+				Spec: cdPipeApi.StageSpec{
+					Name: "prod",
+					RoleBindings: []cdPipeApi.RoleBindingTemplate{
+						{
+							Name: "developers",
+							Subjects: []rbacApi.Subject{
+								{APIGroup: rbacApi.GroupName, Kind: rbacApi.GroupKind, Name: "developers"},
+							},
+							RoleRef: &rbacApi.RoleRef{APIGroup: rbacApi.GroupName, Kind: rbac.ClusterRoleKind, Name: "view"},
+						},
+						{
+							Name: "log-readers",
+							Subjects: []rbacApi.Subject{
+								{APIGroup: rbacApi.GroupName, Kind: rbacApi.GroupKind, Name: "support"},
+							},
+							Rules: []rbacApi.PolicyRule{
+								{APIGroups: []string{""}, Resources: []string{"pods/log"}, Verbs: []string{"get"}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: require.NoError,
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				targetNs := util.GenerateNamespaceName(stage)
+
+				rb := &rbacApi.RoleBinding{}
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "developers", Namespace: targetNs}, rb))
+				require.Equal(t, "view", rb.RoleRef.Name)
+
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "log-readers", Namespace: targetNs}, rb))
+				require.Equal(t, rbac.RoleKind, rb.RoleRef.Kind)
+				require.Equal(t, "log-readers", rb.RoleRef.Name)
+
+				role := &rbacApi.Role{}
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "log-readers", Namespace: targetNs}, role))
+				require.Len(t, role.Rules, 1)
+
+				require.Error(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      tenantAdminRbName,
+					Namespace: targetNs,
+				}, &rbacApi.RoleBinding{}))
+				require.True(t, meta.IsStatusConditionTrue(stage.Status.Conditions, cdPipeApi.ConditionRBACReady))
+			},
+		},
+		{
+			name: "role bindings from ConfigMap",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "test-stage",
+				},
+				Spec: cdPipeApi.StageSpec{
+					Name: "prod",
+				},
+			},
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      rbac.StageRoleBindingsConfigMapName,
+						Namespace: namespace,
+					},
+					Data: map[string]string{
+						"prod": `
+- name: developers
+  subjects:
+    - kind: Group
+      apiGroup: rbac.authorization.k8s.io
+      name: developers
+  roleRef:
+    kind: ClusterRole
+    apiGroup: rbac.authorization.k8s.io
+    name: view
+`,
+					},
+				},
+			},
+			wantErr: require.NoError,
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				rb := &rbacApi.RoleBinding{}
+				require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      "developers",
+					Namespace: util.GenerateNamespaceName(stage),
+				}, rb))
+				require.Equal(t, "view", rb.RoleRef.Name)
+			},
+		},
+		{
+			name: "role bindings are not created in namespace which isn't owned by the stage",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "test-stage",
+				},
+				Spec: cdPipeApi.StageSpec{
+					Namespace: "kube-system",
+				},
+			},
+			objects: []runtime.Object{
+				&corev1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "kube-system"}},
+			},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "namespace kube-system wasn't created for the stage")
+			},
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				require.Error(t, k8sClient.Get(context.Background(), client.ObjectKey{
+					Name:      tenantAdminRbName,
+					Namespace: "kube-system",
+				}, &rbacApi.RoleBinding{}))
+				require.True(t, meta.IsStatusConditionFalse(stage.Status.Conditions, cdPipeApi.ConditionRBACReady))
+			},
+		},
+		{
+			name: "invalid ConfigMap",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "test-stage",
+				},
+			},
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      rbac.StageRoleBindingsConfigMapName,
+						Namespace: namespace,
+					},
+					Data: map[string]string{
+						rbac.DefaultRoleBindingsKey: "not a list",
+					},
+				},
+			},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "failed to get role bindings configuration")
+			},
+			wantCheck: func(t *testing.T, stage *cdPipeApi.Stage, k8sClient client.Client) {
+				require.True(t, meta.IsStatusConditionFalse(stage.Status.Conditions, cdPipeApi.ConditionRBACReady))
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -87,12 +340,14 @@ func TestConfigureTenantAdminRbac_ServeRequest(t *testing.T) {
 			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tt.objects...).Build()
 
 			h := ConfigureTenantAdminRbac{
-				client: k8sClient,
-				log:    logr.Discard(),
-				rbac:   rbac.NewRbacManager(k8sClient, logr.Discard()),
+				client:        k8sClient,
+				clusterClient: k8sClient,
+				log:           logr.Discard(),
+				rbac:          rbac.NewRbacManager(k8sClient, logr.Discard()),
+				recorder:      record.NewFakeRecorder(10),
 			}
 
-			err := h.ServeRequest(tt.stage)
+			err := h.ServeRequest(context.Background(), tt.stage)
 			tt.wantErr(t, err)
 			tt.wantCheck(t, tt.stage, k8sClient)
 		})
@@ -0,0 +1,40 @@
+package chain
+
+import (
+	"context"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// handlerTimeout limits the time of a single chain handler,
+// so a stuck API call doesn't block the reconciliation worker.
+const handlerTimeout = 2 * time.Minute
+
+type chainContextKey struct{}
+
+// chainContext holds the context the chain has been started with.
+type chainContext struct {
+	ctx context.Context
+}
+
+// withChainContext stores the context of the chain,
+// so the handler contexts are derived from it instead of the context of the previous handler.
+// Otherwise, the next handlers would share the timeout of the previous ones.
+func withChainContext(ctx context.Context) context.Context {
+	c := &chainContext{}
+	c.ctx = context.WithValue(ctx, chainContextKey{}, c)
+
+	return c.ctx
+}
+
+// handlerContext returns a context of the handler with the handler timeout and the handler name in the logger.
+func handlerContext(ctx context.Context, name string) (context.Context, context.CancelFunc) {
+	if c, ok := ctx.Value(chainContextKey{}).(*chainContext); ok {
+		ctx = c.ctx
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, handlerTimeout)
+
+	return ctrl.LoggerInto(ctx, ctrl.LoggerFrom(ctx).WithValues("handler", name)), cancel
+}
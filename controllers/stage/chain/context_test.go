@@ -0,0 +1,79 @@
+package chain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/handler"
+)
+
+type deadlineTestHandler struct {
+	next      handler.CdStageHandler
+	deadlines *[]time.Time
+}
+
+func (h deadlineTestHandler) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	deadline, ok := ctx.Deadline()
+	if ok {
+		*h.deadlines = append(*h.deadlines, deadline)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	return nextServeOrNil(ctx, h.next, stage)
+}
+
+func TestInstrumentedChain_HandlerTimeout(t *testing.T) {
+	t.Parallel()
+
+	var deadlines []time.Time
+
+	ch := instrumentedChain{
+		next: deadlineTestHandler{
+			deadlines: &deadlines,
+			next: deadlineTestHandler{
+				deadlines: &deadlines,
+			},
+		},
+	}
+
+	start := time.Now()
+
+	require.NoError(t, ch.ServeRequest(context.Background(), &cdPipeApi.Stage{}))
+	require.Len(t, deadlines, 2)
+
+	assert.WithinDuration(t, start.Add(handlerTimeout), deadlines[0], time.Second)
+	// the next handler has its own timeout instead of the rest of the previous handler timeout.
+	assert.True(t, deadlines[1].After(deadlines[0]))
+}
+
+func TestInstrumentedChain_ParentContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var deadlines []time.Time
+
+	ch := instrumentedChain{
+		next: deadlineTestHandler{
+			deadlines: &deadlines,
+			next:      contextErrTestHandler{},
+		},
+	}
+
+	err := ch.ServeRequest(ctx, &cdPipeApi.Stage{})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+type contextErrTestHandler struct{}
+
+func (contextErrTestHandler) ServeRequest(ctx context.Context, _ *cdPipeApi.Stage) error {
+	return ctx.Err()
+}
@@ -1,7 +1,9 @@
 package chain
 
 import (
+	"context"
 	"github.com/go-logr/logr"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
@@ -14,20 +16,27 @@ import (
 type DelegateNamespaceCreation struct {
 	next   handler.CdStageHandler
 	client client.Client
-	log    logr.Logger
+	// pipelineClient is used to get the stage CDPipeline from the operator cluster.
+	pipelineClient client.Client
+	log            logr.Logger
+	recorder       record.EventRecorder
 }
 
 // ServeRequest creates for kubernetes platform PutNamespace or PutKioskSpace if the kiosk is enabled.
 // For platform openshift it creates PutOpenshiftProject.
 // By default, it creates PutOpenshiftProject.
 // If the namespace is not managed by the operator, it creates CheckNamespaceExist.
-func (c DelegateNamespaceCreation) ServeRequest(stage *cdPipeApi.Stage) error {
+func (c DelegateNamespaceCreation) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
 	logger := c.log.WithValues("stage name", stage.Name)
 
 	if !platform.ManageNamespace() {
 		logger.Info("Namespace is not managed by the operator")
 
-		return nextServeOrNil(CheckNamespaceExist(c), stage)
+		return nextServeOrNil(ctx, CheckNamespaceExist{
+			next:   c.next,
+			client: c.client,
+			log:    c.log,
+		}, stage)
 	}
 
 	if platform.IsKubernetes() {
@@ -36,20 +45,21 @@ func (c DelegateNamespaceCreation) ServeRequest(stage *cdPipeApi.Stage) error {
 		if platform.KioskEnabled() {
 			logger.Info("Kiosk is enabled")
 
-			return nextServeOrNil(PutKioskSpace{
-				next:   c.next,
-				space:  kiosk.InitSpace(c.client),
-				client: c.client,
-				log:    c.log,
+			return nextServeOrNil(ctx, PutKioskSpace{
+				next:           c.next,
+				space:          kiosk.InitSpace(c.client),
+				pipelineClient: c.pipelineClient,
+				log:            c.log,
+				recorder:       c.recorder,
 			}, stage)
 		}
 
 		logger.Info("Kiosk is disabled")
 
-		return nextServeOrNil(PutNamespace(c), stage)
+		return nextServeOrNil(ctx, PutNamespace(c), stage)
 	}
 
 	logger.Info("Platform is openshift")
 
-	return nextServeOrNil(PutOpenshiftProject(c), stage)
+	return nextServeOrNil(ctx, PutOpenshiftProject(c), stage)
 }
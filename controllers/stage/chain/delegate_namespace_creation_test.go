@@ -10,6 +10,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -24,6 +25,7 @@ func TestDelegateNamespaceCreation_ServeRequest(t *testing.T) {
 
 	require.NoError(t, projectApi.AddToScheme(scheme))
 	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
 
 	tests := []struct {
 		name       string
@@ -170,12 +172,15 @@ func TestDelegateNamespaceCreation_ServeRequest(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.prepare(t)
 
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
 			c := DelegateNamespaceCreation{
-				client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build(),
-				log:    logr.Discard(),
+				client:         k8sClient,
+				pipelineClient: k8sClient,
+				log:            logr.Discard(),
+				recorder:       record.NewFakeRecorder(10),
 			}
 
-			err := c.ServeRequest(tt.stage)
+			err := c.ServeRequest(context.Background(), tt.stage)
 			tt.wantErr(t, err)
 			tt.wantAssert(t, c.client, tt.stage)
 		})
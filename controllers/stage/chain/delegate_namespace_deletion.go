@@ -1,7 +1,9 @@
 package chain
 
 import (
+	"context"
 	"github.com/go-logr/logr"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
@@ -12,9 +14,10 @@ import (
 
 // DelegateNamespaceDeletion is a stage chain element that decides whether to delete a namespace or project.
 type DelegateNamespaceDeletion struct {
-	next   handler.CdStageHandler
-	client client.Client
-	log    logr.Logger
+	next     handler.CdStageHandler
+	client   client.Client
+	log      logr.Logger
+	recorder record.EventRecorder
 }
 
 // ServeRequest creates for kubernetes platform DeleteNamespace or DeleteSpace if kiosk is enabled.
@@ -22,13 +25,13 @@ type DelegateNamespaceDeletion struct {
 // The decision is made based on the environment variable PLATFORM_TYPE.
 // By default, it creates DeleteOpenshiftProject.
 // If the namespace is not managed by the operator, it creates Skip chain element.
-func (c DelegateNamespaceDeletion) ServeRequest(stage *cdPipeApi.Stage) error {
+func (c DelegateNamespaceDeletion) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
 	logger := c.log.WithValues("stage name", stage.Name)
 
 	if !platform.ManageNamespace() {
 		logger.Info("Namespace is not managed by the operator")
 
-		return nextServeOrNil(Skip{
+		return nextServeOrNil(ctx, Skip{
 			next: c.next,
 			log:  c.log,
 		}, stage)
@@ -40,19 +43,20 @@ func (c DelegateNamespaceDeletion) ServeRequest(stage *cdPipeApi.Stage) error {
 		if platform.KioskEnabled() {
 			logger.Info("Kiosk is enabled")
 
-			return nextServeOrNil(DeleteSpace{
-				next:  c.next,
-				space: kiosk.InitSpace(c.client),
-				log:   c.log,
+			return nextServeOrNil(ctx, DeleteSpace{
+				next:     c.next,
+				space:    kiosk.InitSpace(c.client),
+				log:      c.log,
+				recorder: c.recorder,
 			}, stage)
 		}
 
 		logger.Info("Kiosk is disabled")
 
-		return nextServeOrNil(DeleteNamespace(c), stage)
+		return nextServeOrNil(ctx, DeleteNamespace(c), stage)
 	}
 
 	logger.Info("Platform is openshift")
 
-	return nextServeOrNil(DeleteOpenshiftProject(c), stage)
+	return nextServeOrNil(ctx, DeleteOpenshiftProject(c), stage)
 }
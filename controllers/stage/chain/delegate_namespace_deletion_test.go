@@ -11,6 +11,7 @@ import (
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -202,11 +203,12 @@ func TestDelegateNamespaceDeletion_ServeRequest(t *testing.T) {
 			tt.prepare(t)
 
 			c := DelegateNamespaceDeletion{
-				client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build(),
-				log:    logr.Discard(),
+				client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build(),
+				log:      logr.Discard(),
+				recorder: record.NewFakeRecorder(10),
 			}
 
-			err := c.ServeRequest(tt.stage)
+			err := c.ServeRequest(context.Background(), tt.stage)
 			tt.wantErr(t, err)
 			tt.wantAssert(t, c.client, tt.stage)
 		})
@@ -24,20 +24,25 @@ type DeleteEnvironmentLabelFromCodebaseImageStreams struct {
 	log    logr.Logger
 }
 
-func (h DeleteEnvironmentLabelFromCodebaseImageStreams) ServeRequest(stage *cdPipeApi.Stage) error {
+func (h DeleteEnvironmentLabelFromCodebaseImageStreams) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
 	h.log.Info("Start deleting environment labels from codebase image streams")
 
-	if err := h.deleteEnvironmentLabel(stage); err != nil {
-		return fmt.Errorf("failed to set environment status: %w", err)
+	if err := h.deleteEnvironmentLabel(ctx, stage); err != nil {
+		err = fmt.Errorf("failed to set environment status: %w", err)
+		setConditionFailed(stage, cdPipeApi.ConditionImageStreamsReady, err)
+
+		return err
 	}
 
+	setConditionSucceeded(stage, cdPipeApi.ConditionImageStreamsReady, "Environment labels have been deleted from CodebaseImageStreams")
+
 	h.log.Info("Environment labels have been deleted from codebase image streams")
 
-	return nextServeOrNil(h.next, stage)
+	return nextServeOrNil(ctx, h.next, stage)
 }
 
-func (h DeleteEnvironmentLabelFromCodebaseImageStreams) deleteEnvironmentLabel(stage *cdPipeApi.Stage) error {
-	pipe, err := util.GetCdPipeline(h.client, stage)
+func (h DeleteEnvironmentLabelFromCodebaseImageStreams) deleteEnvironmentLabel(ctx context.Context, stage *cdPipeApi.Stage) error {
+	pipe, err := util.GetCdPipeline(ctx, h.client, stage)
 	if err != nil {
 		return fmt.Errorf("failed to get %s cd pipeline: %w", stage.Spec.CdPipeline, err)
 	}
@@ -47,25 +52,25 @@ func (h DeleteEnvironmentLabelFromCodebaseImageStreams) deleteEnvironmentLabel(s
 	}
 
 	for _, name := range pipe.Spec.InputDockerStreams {
-		stream, err := cluster.GetCodebaseImageStream(h.client, name, stage.Namespace)
+		stream, err := cluster.GetCodebaseImageStream(ctx, h.client, name, stage.Namespace)
 		if err != nil {
 			return fmt.Errorf("failed to get %s codebase image stream: %w", name, err)
 		}
 
 		if stage.IsFirst() {
-			if envErr := h.setEnvLabel(stage.Spec.Name, pipe.Spec.Name, stream); envErr != nil {
+			if envErr := h.setEnvLabel(ctx, stage.Spec.Name, pipe.Spec.Name, stream); envErr != nil {
 				return envErr
 			}
 
 			continue
 		}
 
-		if envErr := h.setEnvLabelForVerifiedImageStream(stage, stream, pipe.Spec.Name, name); envErr != nil {
+		if envErr := h.setEnvLabelForVerifiedImageStream(ctx, stage, stream, pipe.Spec.Name, name); envErr != nil {
 			return envErr
 		}
 
 		if !slices.Contains(pipe.Spec.ApplicationsToPromote, stream.Spec.Codebase) {
-			if envErr := h.setEnvLabel(stage.Spec.Name, pipe.Spec.Name, stream); envErr != nil {
+			if envErr := h.setEnvLabel(ctx, stage.Spec.Name, pipe.Spec.Name, stream); envErr != nil {
 				return envErr
 			}
 		}
@@ -74,11 +79,15 @@ func (h DeleteEnvironmentLabelFromCodebaseImageStreams) deleteEnvironmentLabel(s
 	return nil
 }
 
-func (h DeleteEnvironmentLabelFromCodebaseImageStreams) setEnvLabel(stageName, pipeName string, stream *codebaseApi.CodebaseImageStream) error {
+func (h DeleteEnvironmentLabelFromCodebaseImageStreams) setEnvLabel(
+	ctx context.Context,
+	stageName, pipeName string,
+	stream *codebaseApi.CodebaseImageStream,
+) error {
 	env := createLabelName(pipeName, stageName)
 	deleteLabel(&stream.ObjectMeta, env)
 
-	if err := h.client.Update(context.Background(), stream); err != nil {
+	if err := h.client.Update(ctx, stream); err != nil {
 		return fmt.Errorf("failed to update %v codebase image stream: %w", stream, err)
 	}
 
@@ -87,15 +96,20 @@ func (h DeleteEnvironmentLabelFromCodebaseImageStreams) setEnvLabel(stageName, p
 	return nil
 }
 
-func (h DeleteEnvironmentLabelFromCodebaseImageStreams) setEnvLabelForVerifiedImageStream(stage *cdPipeApi.Stage, stream *codebaseApi.CodebaseImageStream, pipeName, dockerStreamName string) error {
-	previousStageName, err := util.FindPreviousStageName(context.TODO(), h.client, stage)
+func (h DeleteEnvironmentLabelFromCodebaseImageStreams) setEnvLabelForVerifiedImageStream(
+	ctx context.Context,
+	stage *cdPipeApi.Stage,
+	stream *codebaseApi.CodebaseImageStream,
+	pipeName, dockerStreamName string,
+) error {
+	previousStageName, err := util.FindPreviousStageName(ctx, h.client, stage)
 	if err != nil {
 		return fmt.Errorf("failed to previous stage name: %w", err)
 	}
 
 	cisName := createCisName(pipeName, previousStageName, stream.Spec.Codebase)
 
-	stream, err = cluster.GetCodebaseImageStream(h.client, cisName, stage.Namespace)
+	stream, err = cluster.GetCodebaseImageStream(ctx, h.client, cisName, stage.Namespace)
 	if err != nil {
 		if k8sErrors.IsNotFound(err) {
 			return edpErr.CISNotFoundError(fmt.Sprintf("codebase image stream %s is not found", cisName))
@@ -107,7 +121,7 @@ func (h DeleteEnvironmentLabelFromCodebaseImageStreams) setEnvLabelForVerifiedIm
 	env := createLabelName(pipeName, stage.Spec.Name)
 	deleteLabel(&stream.ObjectMeta, env)
 
-	if err = h.client.Update(context.Background(), stream); err != nil {
+	if err = h.client.Update(ctx, stream); err != nil {
 		return fmt.Errorf("failed to update %v codebase image stream: %w", stream, err)
 	}
 
@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -52,10 +53,10 @@ func TestServeRequest_Success(t *testing.T) {
 		log:    logr.Discard(),
 	}
 
-	err := deleteEnvLabel.ServeRequest(&stage)
+	err := deleteEnvLabel.ServeRequest(context.Background(), &stage)
 	assert.NoError(t, err)
 
-	result, err := cluster.GetCodebaseImageStream(deleteEnvLabel.client, dockerImageName, namespace)
+	result, err := cluster.GetCodebaseImageStream(context.Background(), deleteEnvLabel.client, dockerImageName, namespace)
 	assert.NoError(t, err)
 	assert.Empty(t, result.Labels)
 }
@@ -108,14 +109,14 @@ func TestDeleteEnvironmentLabel_VerifiedImageStream(t *testing.T) {
 		log:    logr.Discard(),
 	}
 
-	err := deleteEnvLabel.deleteEnvironmentLabel(&stage)
+	err := deleteEnvLabel.deleteEnvironmentLabel(context.Background(), &stage)
 	assert.NoError(t, err)
 
-	previousImageStream, err := cluster.GetCodebaseImageStream(deleteEnvLabel.client, cisName, namespace)
+	previousImageStream, err := cluster.GetCodebaseImageStream(context.Background(), deleteEnvLabel.client, cisName, namespace)
 	assert.NoError(t, err)
 	assert.Empty(t, previousImageStream.Labels)
 
-	currentImageStream, err := cluster.GetCodebaseImageStream(deleteEnvLabel.client, dockerImageName, namespace)
+	currentImageStream, err := cluster.GetCodebaseImageStream(context.Background(), deleteEnvLabel.client, dockerImageName, namespace)
 	assert.NoError(t, err)
 	assert.Empty(t, currentImageStream.Labels)
 }
@@ -170,14 +171,14 @@ func TestDeleteEnvironmentLabel_ApplicationToPromote(t *testing.T) {
 		log:    logr.Discard(),
 	}
 
-	err := deleteEnvLabel.deleteEnvironmentLabel(&stage)
+	err := deleteEnvLabel.deleteEnvironmentLabel(context.Background(), &stage)
 	assert.NoError(t, err)
 
-	previousImageStream, err := cluster.GetCodebaseImageStream(deleteEnvLabel.client, cisName, namespace)
+	previousImageStream, err := cluster.GetCodebaseImageStream(context.Background(), deleteEnvLabel.client, cisName, namespace)
 	assert.NoError(t, err)
 	assert.Empty(t, previousImageStream.Labels)
 
-	currentImageStream, err := cluster.GetCodebaseImageStream(deleteEnvLabel.client, dockerImageName, namespace)
+	currentImageStream, err := cluster.GetCodebaseImageStream(context.Background(), deleteEnvLabel.client, dockerImageName, namespace)
 	assert.NoError(t, err)
 	assert.Equal(t, image.Labels, currentImageStream.Labels)
 }
@@ -190,7 +191,7 @@ func TestServeRequest_Error(t *testing.T) {
 		log:    logr.Discard(),
 	}
 
-	err := deleteEnvLabel.ServeRequest(&stage)
+	err := deleteEnvLabel.ServeRequest(context.Background(), &stage)
 	assert.True(t, k8sErrors.IsNotFound(err))
 }
 
@@ -212,7 +213,7 @@ func TestDeleteEnvironmentLabel_EmptyInputDockerStream(t *testing.T) {
 		log:    logr.Discard(),
 	}
 
-	err := deleteEnvLabel.deleteEnvironmentLabel(&stage)
+	err := deleteEnvLabel.deleteEnvironmentLabel(context.Background(), &stage)
 	assert.Equal(t, fmt.Errorf("pipeline %s doesn't contain codebase image streams", cdPipeline.Spec.Name), err)
 }
 
@@ -235,7 +236,7 @@ func TestDeleteEnvironmentLabel_CantGetImageDockerStream(t *testing.T) {
 		log:    logr.Discard(),
 	}
 
-	err := deleteEnvLabel.deleteEnvironmentLabel(&stage)
+	err := deleteEnvLabel.deleteEnvironmentLabel(context.Background(), &stage)
 	assert.True(t, k8sErrors.IsNotFound(err))
 }
 
@@ -283,7 +284,7 @@ func TestSetDeleteEnvironmentLabel_NoPreviousStageError(t *testing.T) {
 		log:    logr.Discard(),
 	}
 
-	err := deleteEnvLabel.deleteEnvironmentLabel(&stage)
+	err := deleteEnvLabel.deleteEnvironmentLabel(context.Background(), &stage)
 	assert.Contains(t, err.Error(), "previous stage not found")
 }
 
@@ -308,6 +309,6 @@ func TestSetEnvLabelForVerifiedImageStream_IsNotFoundPreviousImageStream(t *test
 
 	cisName := createCisName(name, previousStageName, image.Spec.Codebase)
 
-	err := deleteEnvLabel.setEnvLabelForVerifiedImageStream(&stage, &image, name, dockerImageName)
+	err := deleteEnvLabel.setEnvLabelForVerifiedImageStream(context.Background(), &stage, &image, name, dockerImageName)
 	assert.Equal(t, edpErr.CISNotFoundError(fmt.Sprintf("codebase image stream %s is not found", cisName)), err)
 }
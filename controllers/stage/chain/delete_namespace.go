@@ -8,6 +8,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
@@ -16,26 +17,28 @@ import (
 )
 
 type DeleteNamespace struct {
-	next   handler.CdStageHandler
-	client client.Client
-	log    logr.Logger
+	next     handler.CdStageHandler
+	client   client.Client
+	log      logr.Logger
+	recorder record.EventRecorder
 }
 
-func (h DeleteNamespace) ServeRequest(stage *cdPipeApi.Stage) error {
-	name := util.GenerateNamespaceName(stage)
-	if err := h.delete(name); err != nil {
+func (h DeleteNamespace) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	name := util.GetTargetNamespace(stage)
+	if err := h.delete(ctx, name, stage); err != nil {
 		return fmt.Errorf("unable to delete %v namespace, name : %w", name, err)
 	}
 
-	return nextServeOrNil(h.next, stage)
+	return nextServeOrNil(ctx, h.next, stage)
 }
 
-func (h DeleteNamespace) delete(name string) error {
+// delete deletes the namespace only if it was created for the stage.
+func (h DeleteNamespace) delete(ctx context.Context, name string, stage *cdPipeApi.Stage) error {
 	logger := h.log.WithValues("name", name)
 	logger.Info("trying to delete namespace")
 
 	ns := &v1.Namespace{}
-	if err := h.client.Get(context.TODO(), types.NamespacedName{
+	if err := h.client.Get(ctx, types.NamespacedName{
 		Name: name,
 	}, ns); err != nil {
 		if k8sErrors.IsNotFound(err) {
@@ -46,11 +49,17 @@ func (h DeleteNamespace) delete(name string) error {
 		return fmt.Errorf("failed to get namespace: %w", err)
 	}
 
-	if err := h.client.Delete(context.TODO(), ns); err != nil {
+	if !util.IsNamespaceOwnedByStage(ns, stage) {
+		logger.Info("namespace wasn't created for the stage. skip deleting")
+		return nil
+	}
+
+	if err := h.client.Delete(ctx, ns); err != nil {
 		return fmt.Errorf("failed to delete namespace: %w", err)
 	}
 
 	logger.Info("namespace has been deleted")
+	h.recorder.Eventf(stage, v1.EventTypeNormal, cdPipeApi.EventReasonNamespaceDeleted, "Namespace %s has been deleted", name)
 
 	return nil
 }
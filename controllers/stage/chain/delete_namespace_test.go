@@ -8,17 +8,21 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
 )
 
 func TestDeleteNamespace_NSDoestExists(t *testing.T) {
 	ch := DeleteNamespace{
-		client: fake.NewClientBuilder().Build(),
-		log:    logr.Discard(),
+		client:   fake.NewClientBuilder().Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
 	s := &cdPipeApi.Stage{
@@ -27,7 +31,7 @@ func TestDeleteNamespace_NSDoestExists(t *testing.T) {
 			Namespace: namespace,
 		},
 	}
-	err := ch.ServeRequest(s)
+	err := ch.ServeRequest(context.Background(), s)
 	assert.NoError(t, err)
 
 	ns := &v1.Namespace{}
@@ -47,8 +51,9 @@ func TestDeleteNamespace_DeleteNS(t *testing.T) {
 	}
 
 	ch := DeleteNamespace{
-		client: fake.NewClientBuilder().WithRuntimeObjects(ns).Build(),
-		log:    logr.Discard(),
+		client:   fake.NewClientBuilder().WithRuntimeObjects(ns).Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
 	s := &cdPipeApi.Stage{
@@ -57,7 +62,7 @@ func TestDeleteNamespace_DeleteNS(t *testing.T) {
 			Namespace: namespace,
 		},
 	}
-	err := ch.ServeRequest(s)
+	err := ch.ServeRequest(context.Background(), s)
 	assert.NoError(t, err)
 
 	ns = &v1.Namespace{}
@@ -66,3 +71,46 @@ func TestDeleteNamespace_DeleteNS(t *testing.T) {
 	}, ns)
 	assert.Error(t, err, "ns doesn't exist")
 }
+
+func TestDeleteNamespace_CustomNs(t *testing.T) {
+	owned := &v1.Namespace{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "team-dev",
+			Labels: map[string]string{
+				util.TenantLabelName: namespace,
+				util.StageLabelName:  name,
+			},
+		},
+	}
+	shared := &v1.Namespace{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "team-qa",
+		},
+	}
+
+	ch := DeleteNamespace{
+		client:   fake.NewClientBuilder().WithRuntimeObjects(owned, shared).Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	s := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: cdPipeApi.StageSpec{
+			Namespace: "team-dev",
+		},
+	}
+	assert.NoError(t, ch.ServeRequest(context.Background(), s))
+
+	err := ch.client.Get(context.TODO(), types.NamespacedName{Name: "team-dev"}, &v1.Namespace{})
+	assert.True(t, k8sErrors.IsNotFound(err), "owned namespace should be deleted")
+
+	s.Spec.Namespace = "team-qa"
+	assert.NoError(t, ch.ServeRequest(context.Background(), s))
+
+	err = ch.client.Get(context.TODO(), types.NamespacedName{Name: "team-qa"}, &v1.Namespace{})
+	assert.NoError(t, err, "shared namespace shouldn't be deleted")
+}
@@ -6,8 +6,9 @@ import (
 
 	"github.com/go-logr/logr"
 	projectApi "github.com/openshift/api/project/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
@@ -17,26 +18,35 @@ import (
 
 // DeleteOpenshiftProject is a handler that deletes an openshift project for a stage.
 type DeleteOpenshiftProject struct {
-	next   handler.CdStageHandler
-	client client.Client
-	log    logr.Logger
+	next     handler.CdStageHandler
+	client   client.Client
+	log      logr.Logger
+	recorder record.EventRecorder
 }
 
 // ServeRequest is a function that deletes openshift project.
-func (h DeleteOpenshiftProject) ServeRequest(stage *cdPipeApi.Stage) error {
-	projectName := util.GenerateNamespaceName(stage)
+func (h DeleteOpenshiftProject) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	projectName := util.GetTargetNamespace(stage)
 	logger := h.log.WithValues("name", projectName)
 
-	project := &projectApi.Project{
-		ObjectMeta: metaV1.ObjectMeta{
-			Name: projectName,
-		},
+	project := &projectApi.Project{}
+	if err := h.client.Get(ctx, client.ObjectKey{Name: projectName}, project); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Project has already been deleted")
+			return nil
+		}
+
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+
+	if !util.IsNamespaceOwnedByStage(project, stage) {
+		logger.Info("Project wasn't created for the stage. Skip deleting")
+		return nextServeOrNil(ctx, h.next, stage)
 	}
 
-	if err := h.client.Delete(context.TODO(), project); err != nil {
+	if err := h.client.Delete(ctx, project); err != nil {
 		if apierrors.IsNotFound(err) {
 			logger.Info("Project has already been deleted")
-
 			return nil
 		}
 
@@ -44,6 +54,7 @@ func (h DeleteOpenshiftProject) ServeRequest(stage *cdPipeApi.Stage) error {
 	}
 
 	logger.Info("Project has been deleted")
+	h.recorder.Eventf(stage, corev1.EventTypeNormal, cdPipeApi.EventReasonNamespaceDeleted, "Project %s has been deleted", projectName)
 
-	return nextServeOrNil(h.next, stage)
+	return nextServeOrNil(ctx, h.next, stage)
 }
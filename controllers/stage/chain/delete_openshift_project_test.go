@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -60,6 +61,29 @@ func TestDeleteOpenshiftProject_ServeRequest(t *testing.T) {
 				)
 			},
 		},
+		{
+			name: "project wasn't created for the stage, skip deletion",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Name:      "stage-1",
+					Namespace: "default",
+				},
+				Spec: cdPipeApi.StageSpec{
+					Namespace: "team-dev",
+				},
+			},
+			objects: []runtime.Object{
+				&projectApi.Project{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name: "team-dev",
+					},
+				},
+			},
+			wantErr: require.NoError,
+			wantAssert: func(t *testing.T, c client.Client, s *cdPipeApi.Stage) {
+				require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "team-dev"}, &projectApi.Project{}))
+			},
+		},
 		{
 			name: "project is not found, skip deletion",
 			stage: &cdPipeApi.Stage{
@@ -78,11 +102,12 @@ func TestDeleteOpenshiftProject_ServeRequest(t *testing.T) {
 			t.Parallel()
 
 			h := DeleteOpenshiftProject{
-				client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tt.objects...).Build(),
-				log:    logr.Discard(),
+				client:   fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tt.objects...).Build(),
+				log:      logr.Discard(),
+				recorder: record.NewFakeRecorder(10),
 			}
 
-			tt.wantErr(t, h.ServeRequest(tt.stage))
+			tt.wantErr(t, h.ServeRequest(context.Background(), tt.stage))
 			tt.wantAssert(t, h.client, tt.stage)
 		})
 	}
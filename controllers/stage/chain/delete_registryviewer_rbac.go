@@ -24,8 +24,8 @@ type DeleteRegistryViewerRbac struct {
 }
 
 // ServeRequest deletes sa-registry-viewer RoleBinding.
-func (h DeleteRegistryViewerRbac) ServeRequest(stage *cdPipeApi.Stage) error {
-	targetNamespace := util.GenerateNamespaceName(stage)
+func (h DeleteRegistryViewerRbac) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	targetNamespace := util.GetTargetNamespace(stage)
 	roleBindingName := generateSaRegistryViewerRoleBindingName(stage)
 	logger := h.log.WithValues("stage", stage.Name, "targetNamespace", targetNamespace, "roleBindingName", roleBindingName)
 
@@ -34,10 +34,10 @@ func (h DeleteRegistryViewerRbac) ServeRequest(stage *cdPipeApi.Stage) error {
 	if !platform.IsOpenshift() {
 		logger.Info("Skip deleting RoleBinding sa-registry-viewer non-openshift platform")
 
-		return nextServeOrNil(h.next, stage)
+		return nextServeOrNil(ctx, h.next, stage)
 	}
 
-	if err := h.client.Delete(context.TODO(), &rbacApi.RoleBinding{
+	if err := h.client.Delete(ctx, &rbacApi.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      roleBindingName,
 			Namespace: stage.Namespace,
@@ -45,7 +45,7 @@ func (h DeleteRegistryViewerRbac) ServeRequest(stage *cdPipeApi.Stage) error {
 	}); err != nil {
 		if k8sErrors.IsNotFound(err) {
 			logger.Info("RoleBinding sa-registry-viewer has been already deleted")
-			return nextServeOrNil(h.next, stage)
+			return nextServeOrNil(ctx, h.next, stage)
 		}
 
 		return fmt.Errorf("failed to delete %s RoleBinding: %w", roleBindingName, err)
@@ -53,5 +53,5 @@ func (h DeleteRegistryViewerRbac) ServeRequest(stage *cdPipeApi.Stage) error {
 
 	logger.Info("RoleBinding for registry-viewer has been deleted")
 
-	return nextServeOrNil(h.next, stage)
+	return nextServeOrNil(ctx, h.next, stage)
 }
@@ -105,7 +105,7 @@ func TestDeleteRegistryViewerRbac_ServeRequest(t *testing.T) {
 				log:    logr.Discard(),
 			}
 
-			err := h.ServeRequest(tt.stage)
+			err := h.ServeRequest(context.Background(), tt.stage)
 
 			tt.wantErr(t, err)
 			tt.wantCheck(t, tt.stage, h.client)
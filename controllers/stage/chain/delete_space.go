@@ -1,10 +1,13 @@
 package chain
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
 	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/handler"
@@ -13,17 +16,42 @@ import (
 )
 
 type DeleteSpace struct {
-	next  handler.CdStageHandler
-	log   logr.Logger
-	space kiosk.SpaceManager
+	next     handler.CdStageHandler
+	log      logr.Logger
+	space    kiosk.SpaceManager
+	recorder record.EventRecorder
 }
 
-func (h DeleteSpace) ServeRequest(stage *cdPipeApi.Stage) error {
-	name := util.GenerateNamespaceName(stage)
+func (h DeleteSpace) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	name := util.GetTargetNamespace(stage)
+	if err := h.delete(ctx, name, stage); err != nil {
+		return err
+	}
+
+	return nextServeOrNil(ctx, h.next, stage)
+}
+
+// delete deletes the kiosk space only if it was created for the stage.
+func (h DeleteSpace) delete(ctx context.Context, name string, stage *cdPipeApi.Stage) error {
 	logger := h.log.WithValues("stage name", stage.Name, "space", name, "namespace", name)
 	logger.Info("deleting loft kiosk space resource and namespace related to this space")
 
-	if err := h.space.Delete(name); err != nil {
+	space, err := h.space.Get(ctx, name)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			logger.Info("loft kiosk space resource is already deleted")
+			return nil
+		}
+
+		return fmt.Errorf("failed to get %v loft kiosk space resource: %w", name, err)
+	}
+
+	if !util.IsNamespaceOwnedByStage(space, stage) {
+		logger.Info("loft kiosk space wasn't created for the stage. skip deleting")
+		return nil
+	}
+
+	if err = h.space.Delete(ctx, name); err != nil {
 		if k8sErrors.IsNotFound(err) {
 			logger.Info("loft kiosk space resource is already deleted")
 			return nil
@@ -33,6 +61,7 @@ func (h DeleteSpace) ServeRequest(stage *cdPipeApi.Stage) error {
 	}
 
 	logger.Info("namespace has been deleted.")
+	h.recorder.Eventf(stage, corev1.EventTypeNormal, cdPipeApi.EventReasonNamespaceDeleted, "Kiosk space %s has been deleted", name)
 
-	return nextServeOrNil(h.next, stage)
+	return nil
 }
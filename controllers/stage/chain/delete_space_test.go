@@ -4,13 +4,15 @@ import (
 	"context"
 	"testing"
 
+	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
@@ -30,44 +32,77 @@ func emptyStageInit(t *testing.T) *cdPipeApi.Stage {
 	}
 }
 
-func TestDeleteSpace_DeleteSpaceSuccess(t *testing.T) {
-	logger := commonmock.NewLogr()
-	stage := emptyStageInit(t)
-	spaceName := util.GenerateNamespaceName(stage)
+func newTestSpace(t *testing.T, spaceName string, labels map[string]interface{}) *unstructured.Unstructured {
+	t.Helper()
+
 	space := &unstructured.Unstructured{}
 	space.Object = map[string]interface{}{
 		"kind":       "Space",
-		"apiVersion": "v1",
-		"spec": map[string]interface{}{
-			"name": spaceName,
+		"apiVersion": "tenancy.kiosk.sh/v1alpha1",
+		"metadata": map[string]interface{}{
+			"name":   spaceName,
+			"labels": labels,
 		},
 	}
 
-	scheme := runtime.NewScheme()
-	scheme.AddKnownTypes(v1.SchemeGroupVersion, &cdPipeApi.Stage{})
+	return space
+}
 
-	testSpace := kiosk.Space{
-		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(space).Build(),
-		Log:    logger,
-	}
+func TestDeleteSpace_ServeRequest(t *testing.T) {
+	t.Parallel()
 
-	deleteSpaceInstance := DeleteSpace{
-		log:   logger,
-		space: testSpace,
+	stage := emptyStageInit(t)
+
+	tests := []struct {
+		name        string
+		space       *unstructured.Unstructured
+		wantDeleted bool
+	}{
+		{
+			name:        "space created for the stage is deleted",
+			space:       newTestSpace(t, util.GenerateNamespaceName(stage), nil),
+			wantDeleted: true,
+		},
+		{
+			name: "space labeled with the stage is deleted",
+			space: newTestSpace(t, util.GenerateNamespaceName(stage), map[string]interface{}{
+				util.TenantLabelName: stage.Namespace,
+				util.StageLabelName:  stage.Name,
+			}),
+			wantDeleted: true,
+		},
+		{
+			name: "space of another stage is kept",
+			space: newTestSpace(t, util.GenerateNamespaceName(stage), map[string]interface{}{
+				util.TenantLabelName: stage.Namespace,
+				util.StageLabelName:  "other",
+			}),
+			wantDeleted: false,
+		},
 	}
 
-	err := deleteSpaceInstance.ServeRequest(stage)
-	assert.NoError(t, err)
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
 
-	emptySpace := &unstructured.Unstructured{}
-	emptySpace.Object = map[string]interface{}{
-		"kind":       "Space",
-		"apiVersion": "v1",
+			testSpace := kiosk.Space{
+				Client: fake.NewClientBuilder().WithObjects(tt.space).Build(),
+				Log:    logr.Discard(),
+			}
+
+			deleteSpaceInstance := DeleteSpace{
+				log:      logr.Discard(),
+				space:    testSpace,
+				recorder: record.NewFakeRecorder(10),
+			}
+
+			require.NoError(t, deleteSpaceInstance.ServeRequest(context.Background(), stage))
+
+			_, err := testSpace.Get(context.Background(), tt.space.GetName())
+			assert.Equal(t, tt.wantDeleted, k8sErrors.IsNotFound(err))
+		})
 	}
-	err = testSpace.Client.Get(context.Background(), types.NamespacedName{
-		Name: spaceName,
-	}, emptySpace)
-	assert.True(t, k8sErrors.IsNotFound(err))
 }
 
 func TestDeleteSpace_SpaceDoesntExist(t *testing.T) {
@@ -84,11 +119,12 @@ func TestDeleteSpace_SpaceDoesntExist(t *testing.T) {
 	}
 
 	deleteSpaceInstance := DeleteSpace{
-		log:   log,
-		space: testSpace,
+		log:      log,
+		space:    testSpace,
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	err := deleteSpaceInstance.ServeRequest(stage)
+	err := deleteSpaceInstance.ServeRequest(context.Background(), stage)
 
 	loggerSink, ok := log.GetSink().(*commonmock.Logger)
 	assert.True(t, ok)
@@ -0,0 +1,45 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/handler"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/rbac"
+)
+
+// DeleteStageRbac deletes RoleBindings and Roles created by the operator for the stage in the stage target namespace.
+// Resources are found by the operator ownership labels, so it works when the target namespace is not deleted.
+type DeleteStageRbac struct {
+	next handler.CdStageHandler
+	log  logr.Logger
+	// rbac manages RBAC in the cluster where the stage namespace is located.
+	rbac rbac.Manager
+}
+
+// ServeRequest deletes stage RoleBindings and Roles.
+func (h DeleteStageRbac) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	targetNamespace := util.GetTargetNamespace(stage)
+	logger := h.log.WithValues("stage", stage.Name, "targetNamespace", targetNamespace)
+
+	logger.Info("Deleting stage RBAC")
+
+	labels := util.GetStageRbacLabels(stage)
+
+	// Nothing is kept, all the stage RoleBindings and Roles are deleted.
+	if err := h.rbac.PruneRoleBindings(ctx, targetNamespace, labels, nil); err != nil {
+		return fmt.Errorf("failed to delete stage role bindings: %w", err)
+	}
+
+	if err := h.rbac.PruneRoles(ctx, targetNamespace, labels, nil); err != nil {
+		return fmt.Errorf("failed to delete stage roles: %w", err)
+	}
+
+	logger.Info("Stage RBAC has been deleted")
+
+	return nextServeOrNil(ctx, h.next, stage)
+}
@@ -0,0 +1,90 @@
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacApi "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/rbac"
+)
+
+func TestDeleteStageRbac_ServeRequest(t *testing.T) {
+	t.Parallel()
+
+	const targetNamespace = "team-dev"
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, rbacApi.AddToScheme(scheme))
+
+	stage := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Namespace: "edp",
+			Name:      "dev",
+		},
+		Spec: cdPipeApi.StageSpec{
+			Namespace: targetNamespace,
+		},
+	}
+
+	stageLabels := func(component string) map[string]string {
+		labels := util.GetRbacLabels(stage, component)
+		labels[rbac.ManagedByLabelName] = rbac.ManagedByLabelValue
+
+		return labels
+	}
+
+	otherStageLabels := map[string]string{
+		rbac.ManagedByLabelName: rbac.ManagedByLabelValue,
+		util.TenantLabelName:    "edp",
+		util.StageLabelName:     "qa",
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&rbacApi.RoleBinding{
+			ObjectMeta: metaV1.ObjectMeta{Name: tenantAdminRbName, Namespace: targetNamespace, Labels: stageLabels(tenantRbacComponent)},
+		},
+		&rbacApi.RoleBinding{
+			ObjectMeta: metaV1.ObjectMeta{Name: jenkinsAdminRbName, Namespace: targetNamespace, Labels: stageLabels(jenkinsRbacComponent)},
+		},
+		&rbacApi.Role{
+			ObjectMeta: metaV1.ObjectMeta{Name: "viewer", Namespace: targetNamespace, Labels: stageLabels(tenantRbacComponent)},
+		},
+		&rbacApi.RoleBinding{
+			ObjectMeta: metaV1.ObjectMeta{Name: "qa-binding", Namespace: targetNamespace, Labels: otherStageLabels},
+		},
+		&rbacApi.RoleBinding{
+			ObjectMeta: metaV1.ObjectMeta{Name: "user-binding", Namespace: targetNamespace},
+		},
+	).Build()
+
+	h := DeleteStageRbac{
+		log:  logr.Discard(),
+		rbac: rbac.NewRbacManager(k8sClient, logr.Discard()),
+	}
+
+	require.NoError(t, h.ServeRequest(context.Background(), stage))
+
+	roleBindings := &rbacApi.RoleBindingList{}
+	require.NoError(t, k8sClient.List(context.Background(), roleBindings, client.InNamespace(targetNamespace)))
+
+	names := make([]string, 0, len(roleBindings.Items))
+	for i := range roleBindings.Items {
+		names = append(names, roleBindings.Items[i].Name)
+	}
+
+	assert.ElementsMatch(t, []string{"qa-binding", "user-binding"}, names)
+
+	roles := &rbacApi.RoleList{}
+	require.NoError(t, k8sClient.List(context.Background(), roles, client.InNamespace(targetNamespace)))
+	assert.Empty(t, roles.Items)
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
 	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/handler"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/dryrun"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/multiclusterclient"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/rbac"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/cluster"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/consts"
@@ -22,11 +25,99 @@ const (
 	logKeyRegistryViewerRbac                      = "sa-registry-viewer-rbac"
 	logKeyTenantAdminRbac                         = "tenant-admin-rbac"
 	logKeyPutNamespace                            = "put-namespace"
+	logKeyApplyNamespaceTemplate                  = "apply-namespace-template"
+	logKeyDeleteStageRbac                         = "delete-stage-rbac"
+	logKeyJenkinsRbac                             = "configure-rbac"
+	logKeyPutJenkinsJob                           = "put-jenkins-job-chain"
+	logKeyPutEnvironmentLabel                     = "put-environment-label-to-codebase-image-streams"
+	logKeyDeleteNamespace                         = "delete-namespace"
+	logKeyDeleteRegistryViewerRbac                = "delete-registry-viewer-rbac"
 )
 
-func nextServeOrNil(next handler.CdStageHandler, stage *cdPipeApi.Stage) error {
+// Chain modes. Every mode has its own list of the chain steps.
+const (
+	ModeJenkinsAuto    = "jenkins-auto"
+	ModeJenkinsManual  = "jenkins-manual"
+	ModeTektonAuto     = "tekton-auto"
+	ModeTektonManual   = "tekton-manual"
+	ModeExternalAuto   = "external-auto"
+	ModeExternalManual = "external-manual"
+	ModeDelete         = "delete"
+	ModeExternalDelete = "external-delete"
+)
+
+// defaultChains contains the chain steps for every mode.
+// Steps can be overridden with the ChainsConfigMapName ConfigMap.
+var defaultChains = map[string][]string{
+	ModeJenkinsManual: {
+		StepPutCodebaseImageStream,
+		StepPutNamespace,
+		StepApplyNamespaceTemplate,
+		StepConfigureJenkinsRbac,
+		StepConfigureRegistryViewerRbac,
+		StepConfigureTenantAdminRbac,
+		StepPutJenkinsJob,
+		StepDeleteEnvironmentLabel,
+	},
+	ModeJenkinsAuto: {
+		StepPutCodebaseImageStream,
+		StepPutNamespace,
+		StepApplyNamespaceTemplate,
+		StepConfigureJenkinsRbac,
+		StepConfigureRegistryViewerRbac,
+		StepConfigureTenantAdminRbac,
+		StepPutJenkinsJob,
+		StepDeleteEnvironmentLabel,
+		StepPutEnvironmentLabel,
+	},
+	ModeTektonManual: {
+		StepPutCodebaseImageStream,
+		StepPutNamespace,
+		StepApplyNamespaceTemplate,
+		StepDeleteEnvironmentLabel,
+		StepConfigureRegistryViewerRbac,
+		StepConfigureTenantAdminRbac,
+	},
+	ModeTektonAuto: {
+		StepPutCodebaseImageStream,
+		StepPutNamespace,
+		StepApplyNamespaceTemplate,
+		StepDeleteEnvironmentLabel,
+		StepPutEnvironmentLabel,
+		StepConfigureRegistryViewerRbac,
+		StepConfigureTenantAdminRbac,
+	},
+	ModeExternalManual: {
+		StepPutCodebaseImageStream,
+		StepPutNamespace,
+		StepApplyNamespaceTemplate,
+		StepConfigureTenantAdminRbac,
+		StepDeleteEnvironmentLabel,
+	},
+	ModeExternalAuto: {
+		StepPutCodebaseImageStream,
+		StepPutNamespace,
+		StepApplyNamespaceTemplate,
+		StepConfigureTenantAdminRbac,
+		StepDeleteEnvironmentLabel,
+		StepPutEnvironmentLabel,
+	},
+	ModeDelete: {
+		StepDeleteEnvironmentLabel,
+		StepDeleteStageRbac,
+		StepDeleteNamespace,
+		StepDeleteRegistryViewerRbac,
+	},
+	ModeExternalDelete: {
+		StepDeleteEnvironmentLabel,
+		StepDeleteStageRbac,
+		StepDeleteNamespace,
+	},
+}
+
+func nextServeOrNil(ctx context.Context, next handler.CdStageHandler, stage *cdPipeApi.Stage) error {
 	if next != nil {
-		if err := next.ServeRequest(stage); err != nil {
+		if err := serveHandler(ctx, next, stage); err != nil {
 			return fmt.Errorf("failed to serve request: %w", err)
 		}
 
@@ -38,251 +129,98 @@ func nextServeOrNil(next handler.CdStageHandler, stage *cdPipeApi.Stage) error {
 	return nil
 }
 
-func CreateChain(ctx context.Context, c client.Client, stage *cdPipeApi.Stage) handler.CdStageHandler {
-	if !stage.InCluster() {
-		return createExternalClusterChain(ctx, c, stage.Spec.TriggerType)
-	}
+// CreateChain returns a chain of handlers for the stage.
+func CreateChain(ctx context.Context, c client.Client, recorder record.EventRecorder, stage *cdPipeApi.Stage) (handler.CdStageHandler, error) {
+	return createChain(ctx, c, recorder, stage, selectChainMode(ctx, c, stage), nil)
+}
 
-	if !cluster.JenkinsEnabled(ctx, c, stage.Namespace, log) {
-		return getTektonChain(c, stage.Spec.TriggerType)
+// CreateDryRunChain returns a chain of handlers for the stage that records the changes to the plan instead of applying them.
+func CreateDryRunChain(ctx context.Context, c client.Client, stage *cdPipeApi.Stage, plan *dryrun.Plan) (handler.CdStageHandler, error) {
+	// FakeRecorder without the events channel drops the events, so the handlers don't report the changes that are not made.
+	return createChain(ctx, c, &record.FakeRecorder{}, stage, selectChainMode(ctx, c, stage), plan)
+}
+
+// selectChainMode selects the chain mode by the stage cluster, the CI tool and the trigger type.
+// Stages which require approvals are not deployed automatically, so the new versions are deployed only by Promotions.
+func selectChainMode(ctx context.Context, c client.Client, stage *cdPipeApi.Stage) string {
+	autoDeploy := consts.AutoDeployTriggerType == stage.Spec.TriggerType && stage.Spec.Approval == nil
+	mode := selectMode(autoDeploy, ModeJenkinsAuto, ModeJenkinsManual)
+
+	switch {
+	case !stage.InCluster():
+		mode = selectMode(autoDeploy, ModeExternalAuto, ModeExternalManual)
+	case !cluster.JenkinsEnabled(ctx, c, stage.Namespace, log):
+		mode = selectMode(autoDeploy, ModeTektonAuto, ModeTektonManual)
 	}
 
-	return getDefChain(c, stage.Spec.TriggerType)
+	return mode
 }
 
-func CreateDeleteChain(ctx context.Context, c client.Client, stage *cdPipeApi.Stage) handler.CdStageHandler {
+// CreateDeleteChain returns a chain of handlers that cleans up the stage resources.
+func CreateDeleteChain(ctx context.Context, c client.Client, recorder record.EventRecorder, stage *cdPipeApi.Stage) (handler.CdStageHandler, error) {
+	mode := ModeDelete
 	if !stage.InCluster() {
-		return createExternalClusterDeleteChain(ctx, c)
+		mode = ModeExternalDelete
 	}
 
-	return createDefDeleteChain(ctx, c)
+	return createChain(ctx, c, recorder, stage, mode, nil)
 }
 
-// getDefChain returns a default chain of handlers for stage.
-// nolint:funlen // it's a chain builder without any complex logic.
-func getDefChain(c client.Client, triggerType string) handler.CdStageHandler {
-	const (
-		configureRbac      = "configure-rbac"
-		putJenkinsJobChain = "put-jenkins-job-chain"
-	)
+func createChain(
+	ctx context.Context,
+	c client.Client,
+	recorder record.EventRecorder,
+	stage *cdPipeApi.Stage,
+	mode string,
+	plan *dryrun.Plan,
+) (handler.CdStageHandler, error) {
+	clusterClient := c
 
-	logger := ctrl.Log.WithName("create-chain")
-	rbacManager := rbac.NewRbacManager(c, ctrl.Log.WithName("rbac-manager"))
-
-	if consts.AutoDeployTriggerType == triggerType {
-		logger.Info("Auto-deploy chain is selected")
+	if !stage.InCluster() {
+		var err error
 
-		return PutCodebaseImageStream{
-			next: DelegateNamespaceCreation{
-				next: ConfigureJenkinsRbac{
-					next: ConfigureRegistryViewerRbac{
-						next: ConfigureTenantAdminRbac{
-							client: c,
-							log:    ctrl.Log.WithName(logKeyTenantAdminRbac),
-							rbac:   rbacManager,
-							next: PutJenkinsJob{
-								client: c,
-								next: RemoveLabelsFromCodebaseDockerStreamsAfterCdPipelineUpdate{
-									client: c,
-									log:    ctrl.Log.WithName("remove-labels-from-codebase-docker-streams-after-cd-pipeline-update"),
-									next: DeleteEnvironmentLabelFromCodebaseImageStreams{
-										client: c,
-										log:    ctrl.Log.WithName(deleteEnvironmentLabelFromCodebaseImageStream),
-										next: PutEnvironmentLabelToCodebaseImageStreams{
-											client: c,
-											log:    ctrl.Log.WithName("put-environment-label-to-codebase-image-streams"),
-										},
-									},
-								},
-								log: ctrl.Log.WithName(putJenkinsJobChain),
-							},
-						},
-						client: c,
-						log:    ctrl.Log.WithName(logKeyRegistryViewerRbac),
-						rbac:   rbacManager,
-					},
-					client: c,
-					log:    ctrl.Log.WithName(configureRbac),
-					rbac:   rbacManager,
-				},
-				client: c,
-				log:    ctrl.Log.WithName(logKeyPutNamespace),
-			},
-			client: c,
-			log:    ctrl.Log.WithName(putCodebaseImageStreamChain),
+		clusterClient, err = multiclusterclient.NewClientProvider(c).GetClusterClient(ctx, stage.Namespace, stage.Spec.ClusterName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cluster client: %w", err)
 		}
 	}
 
-	logger.Info("Manual-deploy chain is selected")
-
-	return PutCodebaseImageStream{
-		next: DelegateNamespaceCreation{
-			next: ConfigureJenkinsRbac{
-				next: ConfigureRegistryViewerRbac{
-					client: c,
-					log:    ctrl.Log.WithName(logKeyRegistryViewerRbac),
-					rbac:   rbacManager,
-					next: ConfigureTenantAdminRbac{
-						client: c,
-						log:    ctrl.Log.WithName(logKeyTenantAdminRbac),
-						rbac:   rbacManager,
-						next: PutJenkinsJob{
-							client: c,
-							log:    ctrl.Log.WithName(putJenkinsJobChain),
-							next: DeleteEnvironmentLabelFromCodebaseImageStreams{
-								client: c,
-								log:    ctrl.Log.WithName(deleteEnvironmentLabelFromCodebaseImageStream),
-							},
-						},
-					},
-				},
-				client: c,
-				log:    ctrl.Log.WithName(configureRbac),
-				rbac:   rbacManager,
-			},
-			client: c,
-			log:    ctrl.Log.WithName(logKeyPutNamespace),
-		},
-		client: c,
-		log:    ctrl.Log.WithName(putCodebaseImageStreamChain),
-	}
-}
-
-// getTektonDeleteChain returns a chain of handlers for tekton flow.
-// nolint:funlen // it's a chain builder without any complex logic.
-func getTektonChain(c client.Client, triggerType string) handler.CdStageHandler {
-	logger := ctrl.Log.WithName("create-chain")
-	rbacManager := rbac.NewRbacManager(c, ctrl.Log.WithName("rbac-manager"))
+	if plan != nil {
+		dryRunClient := dryrun.NewClient(c, plan)
 
-	logger.Info("Tekton chain is selected")
-
-	if consts.AutoDeployTriggerType == triggerType {
-		logger.Info("Auto-deploy chain is selected")
-
-		return PutCodebaseImageStream{
-			next: DelegateNamespaceCreation{
-				client: c,
-				log:    ctrl.Log.WithName(logKeyPutNamespace),
-				next: RemoveLabelsFromCodebaseDockerStreamsAfterCdPipelineUpdate{
-					client: c,
-					log:    ctrl.Log.WithName("remove-labels-from-codebase-docker-streams-after-cd-pipeline-update"),
-					next: DeleteEnvironmentLabelFromCodebaseImageStreams{
-						client: c,
-						log:    ctrl.Log.WithName(deleteEnvironmentLabelFromCodebaseImageStream),
-						next: PutEnvironmentLabelToCodebaseImageStreams{
-							client: c,
-							log:    ctrl.Log.WithName("put-environment-label-to-codebase-image-streams-chain"),
-							next: ConfigureRegistryViewerRbac{
-								client: c,
-								log:    ctrl.Log.WithName(logKeyRegistryViewerRbac),
-								rbac:   rbacManager,
-								next: ConfigureTenantAdminRbac{
-									client: c,
-									log:    ctrl.Log.WithName(logKeyTenantAdminRbac),
-									rbac:   rbacManager,
-								},
-							},
-						},
-					},
-				},
-			},
-			client: c,
-			log:    ctrl.Log.WithName(putCodebaseImageStreamChain),
+		if stage.InCluster() {
+			clusterClient = dryRunClient
+		} else {
+			clusterClient = dryrun.NewClient(clusterClient, plan)
 		}
-	}
-
-	logger.Info("Manual-deploy chain is selected")
 
-	return PutCodebaseImageStream{
-		next: DelegateNamespaceCreation{
-			client: c,
-			log:    ctrl.Log.WithName(logKeyPutNamespace),
-			next: DeleteEnvironmentLabelFromCodebaseImageStreams{
-				client: c,
-				log:    ctrl.Log.WithName(deleteEnvironmentLabelFromCodebaseImageStream),
-				next: ConfigureRegistryViewerRbac{
-					client: c,
-					log:    ctrl.Log.WithName(logKeyRegistryViewerRbac),
-					rbac:   rbacManager,
-					next: ConfigureTenantAdminRbac{
-						client: c,
-						log:    ctrl.Log.WithName(logKeyTenantAdminRbac),
-						rbac:   rbacManager,
-					},
-				},
-			},
-		},
-		client: c,
-		log:    ctrl.Log.WithName(putCodebaseImageStreamChain),
+		c = dryRunClient
 	}
-}
-
-func createDefDeleteChain(ctx context.Context, c client.Client) handler.CdStageHandler {
-	logger := ctrl.LoggerFrom(ctx)
 
-	logger.Info("Delete chain is selected")
-
-	return DeleteEnvironmentLabelFromCodebaseImageStreams{
-		client: c,
-		log:    logger.WithName(deleteEnvironmentLabelFromCodebaseImageStream),
-		next: DelegateNamespaceDeletion{
-			client: c,
-			log:    logger.WithName("delete-namespace"),
-			next: DeleteRegistryViewerRbac{
-				client: c,
-				log:    logger.WithName("delete-registry-viewer-rbac"),
-			},
-		},
+	steps, err := getChainSteps(ctx, c, stage.Namespace, mode)
+	if err != nil {
+		return nil, err
 	}
-}
 
-// getExternalClusterChain returns a chain of handlers for external cluster flow.
-func createExternalClusterChain(ctx context.Context, c client.Client, triggerType string) handler.CdStageHandler {
-	logger := ctrl.LoggerFrom(ctx)
+	ctrl.LoggerFrom(ctx).Info("Chain has been selected", "mode", mode, "steps", steps)
 
-	logger.Info("External cluster chain is selected")
-
-	if consts.AutoDeployTriggerType == triggerType {
-		logger.Info("Auto-deploy chain is selected")
-
-		return PutCodebaseImageStream{
-			client: c,
-			log:    ctrl.Log.WithName(putCodebaseImageStreamChain),
-			next: RemoveLabelsFromCodebaseDockerStreamsAfterCdPipelineUpdate{
-				client: c,
-				log:    ctrl.Log.WithName("remove-labels-from-codebase-docker-streams-after-cd-pipeline-update"),
-				next: DeleteEnvironmentLabelFromCodebaseImageStreams{
-					client: c,
-					log:    ctrl.Log.WithName(deleteEnvironmentLabelFromCodebaseImageStream),
-					next: PutEnvironmentLabelToCodebaseImageStreams{
-						client: c,
-						log:    ctrl.Log.WithName("put-environment-label-to-codebase-image-streams-chain"),
-					},
-				},
-			},
-		}
+	next, err := buildChain(steps, StepDependencies{
+		Client:        c,
+		ClusterClient: clusterClient,
+		Rbac:          rbac.NewRbacManager(clusterClient, ctrl.Log.WithName("rbac-manager")),
+		Recorder:      recorder,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s chain: %w", mode, err)
 	}
 
-	logger.Info("Manual-deploy chain is selected")
-
-	return PutCodebaseImageStream{
-		client: c,
-		log:    ctrl.Log.WithName(putCodebaseImageStreamChain),
-		next: DeleteEnvironmentLabelFromCodebaseImageStreams{
-			client: c,
-			log:    ctrl.Log.WithName(deleteEnvironmentLabelFromCodebaseImageStream),
-		},
-	}
+	return instrumentedChain{next: next, steps: steps}, nil
 }
 
-// createExternalClusterDeleteChain returns a chain of handlers for external cluster delete flow.
-func createExternalClusterDeleteChain(ctx context.Context, c client.Client) handler.CdStageHandler {
-	logger := ctrl.LoggerFrom(ctx)
-
-	logger.Info("Delete in external cluster chain is selected")
-
-	return DeleteEnvironmentLabelFromCodebaseImageStreams{
-		client: c,
-		log:    logger.WithName(deleteEnvironmentLabelFromCodebaseImageStream),
+func selectMode(autoDeploy bool, autoMode, manualMode string) string {
+	if autoDeploy {
+		return autoMode
 	}
+
+	return manualMode
 }
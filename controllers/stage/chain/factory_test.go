@@ -7,12 +7,15 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/multiclusterclient"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/consts"
 	jenkinsApi "github.com/epam/edp-jenkins-operator/v2/pkg/apis/v2/v1"
 )
@@ -29,30 +32,65 @@ func TestCreateChain(t *testing.T) {
 	}
 
 	require.NoError(t, jenkinsApi.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	cluster := &cdPipeApi.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      "external-cluster",
+		},
+		Spec: cdPipeApi.ClusterSpec{
+			SecretName: "external-cluster-secret",
+		},
+	}
+
+	clusterSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      "external-cluster-secret",
+		},
+		Data: map[string][]byte{
+			multiclusterclient.ServerSecretKey: []byte("https://external-cluster:6443"),
+			multiclusterclient.TokenSecretKey:  []byte("token"),
+		},
+	}
 
 	tests := []struct {
-		name    string
-		stage   *cdPipeApi.Stage
-		objects []runtime.Object
+		name      string
+		stage     *cdPipeApi.Stage
+		objects   []runtime.Object
+		wantErr   require.ErrorAssertionFunc
+		wantSteps []string
 	}{
 		{
 			name: "should create default chain for manual deploy",
 			stage: &cdPipeApi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: ns,
+				},
 				Spec: cdPipeApi.StageSpec{
 					ClusterName: cdPipeApi.InCluster,
 				},
 			},
-			objects: []runtime.Object{jenkins},
+			objects:   []runtime.Object{jenkins},
+			wantErr:   require.NoError,
+			wantSteps: defaultChains[ModeJenkinsManual],
 		},
 		{
 			name: "should create default chain for auto deploy",
 			stage: &cdPipeApi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: ns,
+				},
 				Spec: cdPipeApi.StageSpec{
 					TriggerType: consts.AutoDeployTriggerType,
 					ClusterName: cdPipeApi.InCluster,
 				},
 			},
-			objects: []runtime.Object{jenkins},
+			objects:   []runtime.Object{jenkins},
+			wantErr:   require.NoError,
+			wantSteps: defaultChains[ModeJenkinsAuto],
 		},
 		{
 			name: "should create tekton chain for manual deploy",
@@ -61,6 +99,8 @@ func TestCreateChain(t *testing.T) {
 					ClusterName: cdPipeApi.InCluster,
 				},
 			},
+			wantErr:   require.NoError,
+			wantSteps: defaultChains[ModeTektonManual],
 		},
 		{
 			name: "should create tekton chain for auto deploy",
@@ -70,35 +110,129 @@ func TestCreateChain(t *testing.T) {
 					ClusterName: cdPipeApi.InCluster,
 				},
 			},
+			wantErr:   require.NoError,
+			wantSteps: defaultChains[ModeTektonAuto],
+		},
+		{
+			name: "should create manual chain for auto deploy stage which requires approvals",
+			stage: &cdPipeApi.Stage{
+				Spec: cdPipeApi.StageSpec{
+					TriggerType: consts.AutoDeployTriggerType,
+					ClusterName: cdPipeApi.InCluster,
+					Approval:    &cdPipeApi.ApprovalPolicy{Users: []string{"john"}},
+				},
+			},
+			wantErr:   require.NoError,
+			wantSteps: defaultChains[ModeTektonManual],
 		},
 		{
 			name: "should create external chain for auto deploy",
 			stage: &cdPipeApi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: ns,
+				},
 				Spec: cdPipeApi.StageSpec{
 					TriggerType: consts.AutoDeployTriggerType,
 					ClusterName: "external-cluster",
 				},
 			},
+			objects:   []runtime.Object{cluster, clusterSecret},
+			wantErr:   require.NoError,
+			wantSteps: defaultChains[ModeExternalAuto],
 		},
 		{
 			name: "should create external chain for manual deploy",
 			stage: &cdPipeApi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: ns,
+				},
+				Spec: cdPipeApi.StageSpec{
+					ClusterName: "external-cluster",
+				},
+			},
+			objects:   []runtime.Object{cluster, clusterSecret},
+			wantErr:   require.NoError,
+			wantSteps: defaultChains[ModeExternalManual],
+		},
+		{
+			name: "should use steps from ConfigMap",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: ns,
+				},
+				Spec: cdPipeApi.StageSpec{
+					ClusterName: cdPipeApi.InCluster,
+				},
+			},
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: ns,
+						Name:      ChainsConfigMapName,
+					},
+					Data: map[string]string{
+						ModeTektonManual: "- put-codebase-image-stream\n- put-namespace\n",
+					},
+				},
+			},
+			wantErr:   require.NoError,
+			wantSteps: []string{StepPutCodebaseImageStream, StepPutNamespace},
+		},
+		{
+			name: "should fail if step is not registered",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: ns,
+				},
+				Spec: cdPipeApi.StageSpec{
+					ClusterName: cdPipeApi.InCluster,
+				},
+			},
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: ns,
+						Name:      ChainsConfigMapName,
+					},
+					Data: map[string]string{
+						ModeTektonManual: "- put-namespace\n- unknown-step\n",
+					},
+				},
+			},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "chain step unknown-step is not registered")
+			},
+		},
+		{
+			name: "should fail if external cluster doesn't exist",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: ns,
+				},
 				Spec: cdPipeApi.StageSpec{
 					ClusterName: "external-cluster",
 				},
 			},
+			wantErr: require.Error,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			chain := CreateChain(
+			chain, err := CreateChain(
 				context.Background(),
 				fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tt.objects...).Build(),
+				record.NewFakeRecorder(10),
 				tt.stage,
 			)
 
-			assert.NotNil(t, chain)
+			tt.wantErr(t, err)
+
+			if err == nil {
+				require.IsType(t, instrumentedChain{}, chain)
+				assert.Equal(t, tt.wantSteps, chain.(instrumentedChain).steps)
+			}
 		})
 	}
 }
@@ -106,9 +240,18 @@ func TestCreateChain(t *testing.T) {
 func TestCreateDeleteChain(t *testing.T) {
 	t.Parallel()
 
+	const ns = "default"
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
 	tests := []struct {
-		name  string
-		stage *cdPipeApi.Stage
+		name      string
+		stage     *cdPipeApi.Stage
+		objects   []runtime.Object
+		wantErr   require.ErrorAssertionFunc
+		wantSteps []string
 	}{
 		{
 			name: "should create delete chain",
@@ -117,26 +260,72 @@ func TestCreateDeleteChain(t *testing.T) {
 					ClusterName: cdPipeApi.InCluster,
 				},
 			},
+			wantErr:   require.NoError,
+			wantSteps: defaultChains[ModeDelete],
 		},
 		{
 			name: "should create delete chain for external cluster",
 			stage: &cdPipeApi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: ns,
+				},
+				Spec: cdPipeApi.StageSpec{
+					ClusterName: "external-cluster",
+				},
+			},
+			objects: []runtime.Object{
+				&cdPipeApi.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: ns,
+						Name:      "external-cluster",
+					},
+					Spec: cdPipeApi.ClusterSpec{
+						SecretName: "external-cluster-secret",
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: ns,
+						Name:      "external-cluster-secret",
+					},
+					Data: map[string][]byte{
+						multiclusterclient.ServerSecretKey: []byte("https://external-cluster:6443"),
+						multiclusterclient.TokenSecretKey:  []byte("token"),
+					},
+				},
+			},
+			wantErr:   require.NoError,
+			wantSteps: defaultChains[ModeExternalDelete],
+		},
+		{
+			name: "should fail if external cluster doesn't exist",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: ns,
+				},
 				Spec: cdPipeApi.StageSpec{
 					ClusterName: "external-cluster",
 				},
 			},
+			wantErr: require.Error,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			chain := CreateDeleteChain(
+			chain, err := CreateDeleteChain(
 				ctrl.LoggerInto(context.Background(), logr.Discard()),
-				fake.NewClientBuilder().Build(),
+				fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tt.objects...).Build(),
+				record.NewFakeRecorder(10),
 				tt.stage,
 			)
 
-			assert.NotNil(t, chain)
+			tt.wantErr(t, err)
+
+			if err == nil {
+				require.IsType(t, instrumentedChain{}, chain)
+				assert.Equal(t, tt.wantSteps, chain.(instrumentedChain).steps)
+			}
 		})
 	}
 }
@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"context"
+
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
 )
 
 type CdStageHandler interface {
-	ServeRequest(stage *cdPipeApi.Stage) error
+	ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error
 }
@@ -0,0 +1,115 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/handler"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/metrics"
+)
+
+// instrumentedChain collects metrics of the chain handlers.
+type instrumentedChain struct {
+	next handler.CdStageHandler
+	// steps are names of the chain steps which are shown in the stage status.
+	steps []string
+}
+
+func (h instrumentedChain) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	stage.Status.ChainSteps = h.steps
+
+	return serveHandler(withChainContext(ctx), h.next, stage)
+}
+
+// handlerError marks an error that has been already counted for the handler where it has occurred.
+type handlerError struct {
+	err error
+}
+
+func (e handlerError) Error() string {
+	return e.err.Error()
+}
+
+func (e handlerError) Unwrap() error {
+	return e.err
+}
+
+// handlerTimer tracks the time spent in the next handlers of the chain,
+// so the handler duration doesn't include it.
+// Handlers are called recursively, so there is a stack of the nested handlers durations for every stage being served.
+type handlerTimer struct {
+	mu     sync.Mutex
+	nested map[*cdPipeApi.Stage][]time.Duration
+}
+
+var timer = &handlerTimer{nested: make(map[*cdPipeApi.Stage][]time.Duration)}
+
+func (t *handlerTimer) start(stage *cdPipeApi.Stage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.nested[stage] = append(t.nested[stage], 0)
+}
+
+// stop returns the handler duration without the nested handlers and adds the total duration to the parent handler.
+func (t *handlerTimer) stop(stage *cdPipeApi.Stage, total time.Duration) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stack := t.nested[stage]
+	last := len(stack) - 1
+	own := total - stack[last]
+	stack = stack[:last]
+
+	if len(stack) == 0 {
+		delete(t.nested, stage)
+
+		return own
+	}
+
+	stack[len(stack)-1] += total
+	t.nested[stage] = stack
+
+	return own
+}
+
+// serveHandler serves the request by the handler within the handler timeout and records its duration and errors.
+func serveHandler(ctx context.Context, h handler.CdStageHandler, stage *cdPipeApi.Stage) error {
+	name := handlerName(h)
+
+	ctx, cancel := handlerContext(ctx, name)
+	defer cancel()
+
+	timer.start(stage)
+
+	startTime := time.Now()
+	err := h.ServeRequest(ctx, stage)
+
+	metrics.ChainHandlerDuration.WithLabelValues(name).Observe(timer.stop(stage, time.Since(startTime)).Seconds())
+
+	if err == nil {
+		return nil
+	}
+
+	var counted handlerError
+	if errors.As(err, &counted) {
+		return err
+	}
+
+	metrics.ChainHandlerErrors.WithLabelValues(name).Inc()
+
+	return handlerError{err: err}
+}
+
+func handlerName(h handler.CdStageHandler) string {
+	t := reflect.TypeOf(h)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
@@ -0,0 +1,82 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/handler"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/metrics"
+)
+
+type metricsTestHandler struct {
+	next handler.CdStageHandler
+	err  error
+}
+
+func (h metricsTestHandler) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	if h.err != nil {
+		return h.err
+	}
+
+	return nextServeOrNil(ctx, h.next, stage)
+}
+
+type metricsTestFailingHandler struct {
+	err error
+}
+
+func (h metricsTestFailingHandler) ServeRequest(_ context.Context, _ *cdPipeApi.Stage) error {
+	return h.err
+}
+
+func TestInstrumentedChain_ServeRequest(t *testing.T) {
+	t.Parallel()
+
+	wantErr := errors.New("failed")
+
+	ch := instrumentedChain{
+		next: metricsTestHandler{
+			next: metricsTestFailingHandler{err: wantErr},
+		},
+	}
+
+	failingErrors := testutil.ToFloat64(metrics.ChainHandlerErrors.WithLabelValues("metricsTestFailingHandler"))
+	handlerErrors := testutil.ToFloat64(metrics.ChainHandlerErrors.WithLabelValues("metricsTestHandler"))
+
+	err := ch.ServeRequest(context.Background(), &cdPipeApi.Stage{})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, wantErr)
+
+	assert.Equal(t, failingErrors+1, testutil.ToFloat64(metrics.ChainHandlerErrors.WithLabelValues("metricsTestFailingHandler")))
+	assert.Equal(t, handlerErrors, testutil.ToFloat64(metrics.ChainHandlerErrors.WithLabelValues("metricsTestHandler")))
+}
+
+func TestHandlerTimer(t *testing.T) {
+	t.Parallel()
+
+	tm := &handlerTimer{nested: make(map[*cdPipeApi.Stage][]time.Duration)}
+	stage := &cdPipeApi.Stage{}
+
+	tm.start(stage)
+	tm.start(stage)
+	tm.start(stage)
+
+	assert.Equal(t, 3*time.Second, tm.stop(stage, 3*time.Second))
+	assert.Equal(t, 2*time.Second, tm.stop(stage, 5*time.Second))
+	assert.Equal(t, time.Second, tm.stop(stage, 6*time.Second))
+	assert.Empty(t, tm.nested)
+}
+
+func TestHandlerName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "PutNamespace", handlerName(PutNamespace{}))
+	assert.Equal(t, "PutNamespace", handlerName(&PutNamespace{}))
+}
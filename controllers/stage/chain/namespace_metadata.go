@@ -0,0 +1,122 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+)
+
+// namespaceMetadata contains labels and annotations which should be set on the stage target namespace.
+type namespaceMetadata struct {
+	labels      map[string]string
+	annotations map[string]string
+}
+
+// getNamespaceMetadata returns the namespace labels and annotations configured in the CDPipeline and the stage.
+// Stage values override the CDPipeline values with the same keys.
+// If the CDPipeline doesn't exist, only the stage values are used.
+func getNamespaceMetadata(ctx context.Context, c client.Client, stage *cdPipeApi.Stage) (*namespaceMetadata, error) {
+	metadata := &namespaceMetadata{}
+
+	pipeline := &cdPipeApi.CDPipeline{}
+
+	err := c.Get(ctx, client.ObjectKey{
+		Namespace: stage.Namespace,
+		Name:      stage.Spec.CdPipeline,
+	}, pipeline)
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get CDPipeline %s: %w", stage.Spec.CdPipeline, err)
+	}
+
+	if err == nil {
+		metadata.labels = mergeMaps(metadata.labels, pipeline.Spec.NamespaceLabels)
+		metadata.annotations = mergeMaps(metadata.annotations, pipeline.Spec.NamespaceAnnotations)
+	}
+
+	metadata.labels = mergeMaps(metadata.labels, stage.Spec.NamespaceLabels)
+	metadata.annotations = mergeMaps(metadata.annotations, stage.Spec.NamespaceAnnotations)
+
+	return metadata, nil
+}
+
+// skip reports whether the namespace doesn't have to be configured,
+// i.e. no labels and annotations are configured and none of them have been set before.
+func (m *namespaceMetadata) skip(stage *cdPipeApi.Stage) bool {
+	return len(m.labels) == 0 && len(m.annotations) == 0 &&
+		len(stage.Status.NamespaceLabels) == 0 && len(stage.Status.NamespaceAnnotations) == 0
+}
+
+// apply sets the labels and annotations on the object and reports whether the object has been changed.
+// The labels and annotations set before, but not configured anymore, are removed.
+// Others that are not configured are left untouched.
+func (m *namespaceMetadata) apply(obj metaV1.Object, stage *cdPipeApi.Stage) bool {
+	labels := mergeMaps(removeKeys(copyMap(obj.GetLabels()), stage.Status.NamespaceLabels, m.labels), m.labels)
+	annotations := mergeMaps(removeKeys(copyMap(obj.GetAnnotations()), stage.Status.NamespaceAnnotations, m.annotations), m.annotations)
+
+	if reflect.DeepEqual(labels, obj.GetLabels()) && reflect.DeepEqual(annotations, obj.GetAnnotations()) {
+		return false
+	}
+
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+
+	return true
+}
+
+// record saves the keys of the configured labels and annotations in the stage status,
+// so they are removed from the namespace when they are removed from the spec.
+func (m *namespaceMetadata) record(stage *cdPipeApi.Stage) {
+	stage.Status.NamespaceLabels = sortedKeys(m.labels)
+	stage.Status.NamespaceAnnotations = sortedKeys(m.annotations)
+}
+
+// removeKeys removes the keys which are not configured anymore from the map.
+// The labels managed by the operator are never removed.
+func removeKeys(m map[string]string, keys []string, configured map[string]string) map[string]string {
+	for _, key := range keys {
+		if _, ok := configured[key]; ok || key == util.TenantLabelName || key == util.StageLabelName {
+			continue
+		}
+
+		delete(m, key)
+	}
+
+	return m
+}
+
+// sortedKeys returns the sorted keys of the map or nil if the map is empty.
+func sortedKeys(m map[string]string) []string {
+	if len(m) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+
+	return c
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -26,42 +27,61 @@ type PutCodebaseImageStream struct {
 
 const dockerRegistryName = "docker-registry"
 
-func (h PutCodebaseImageStream) ServeRequest(stage *cdPipeApi.Stage) error {
+func (h PutCodebaseImageStream) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
 	logger := h.log.WithValues("stage name", stage.Name)
 	logger.Info("start creating codebase image streams.")
 
-	pipe, err := util.GetCdPipeline(h.client, stage)
+	if err := h.putCodebaseImageStreams(ctx, stage); err != nil {
+		setConditionFailed(stage, cdPipeApi.ConditionImageStreamsReady, err)
+
+		return err
+	}
+
+	logger.Info("codebase image stream have been created.")
+	setConditionSucceeded(stage, cdPipeApi.ConditionImageStreamsReady, "CodebaseImageStreams have been created")
+
+	return nextServeOrNil(ctx, h.next, stage)
+}
+
+func (h PutCodebaseImageStream) putCodebaseImageStreams(ctx context.Context, stage *cdPipeApi.Stage) error {
+	pipe, err := util.GetCdPipeline(ctx, h.client, stage)
 	if err != nil {
 		return fmt.Errorf("failed to get %v cd pipeline: %w", stage.Spec.CdPipeline, err)
 	}
 
-	registryComponent, err := h.getDockerRegistryEdpComponent(stage.Namespace)
+	registryComponent, err := h.getDockerRegistryEdpComponent(ctx, stage.Namespace)
 	if err != nil {
 		return fmt.Errorf("failed to get %v EDP component: %w", dockerRegistryName, err)
 	}
 
+	cisNames := make([]string, 0, len(pipe.Spec.InputDockerStreams))
+
 	for _, ids := range pipe.Spec.InputDockerStreams {
-		stream, err := cluster.GetCodebaseImageStream(h.client, ids, stage.Namespace)
+		stream, err := cluster.GetCodebaseImageStream(ctx, h.client, ids, stage.Namespace)
 		if err != nil {
 			return fmt.Errorf("failed to get %v codebase image stream: %w", ids, err)
 		}
 
-		cisName := fmt.Sprintf("%v-%v-%v-verified", pipe.Name, stage.Spec.Name, stream.Spec.Codebase)
+		cisName := util.VerifiedImageStreamName(pipe.Name, stage.Spec.Name, stream.Spec.Codebase)
 		image := fmt.Sprintf("%v/%v/%v", registryComponent.Spec.Url, stage.Namespace, stream.Spec.Codebase)
 
-		if err := h.createCodebaseImageStreamIfNotExists(cisName, image, stream.Spec.Codebase, stage.Namespace); err != nil {
+		if err := h.createCodebaseImageStreamIfNotExists(ctx, stage, cisName, image, stream.Spec.Codebase); err != nil {
 			return fmt.Errorf("failed to create %v codebase image stream: %w", cisName, err)
 		}
+
+		cisNames = append(cisNames, cisName)
 	}
 
-	logger.Info("codebase image stream have been created.")
+	if err := h.pruneCodebaseImageStreams(ctx, stage, cisNames); err != nil {
+		return fmt.Errorf("failed to prune codebase image streams: %w", err)
+	}
 
-	return nextServeOrNil(h.next, stage)
+	return nil
 }
 
-func (h PutCodebaseImageStream) getDockerRegistryEdpComponent(namespace string) (*componentApi.EDPComponent, error) {
+func (h PutCodebaseImageStream) getDockerRegistryEdpComponent(ctx context.Context, namespace string) (*componentApi.EDPComponent, error) {
 	ec := &componentApi.EDPComponent{}
-	if err := h.client.Get(context.TODO(), types.NamespacedName{
+	if err := h.client.Get(ctx, types.NamespacedName{
 		Name:      dockerRegistryName,
 		Namespace: namespace,
 	}, ec); err != nil {
@@ -71,7 +91,14 @@ func (h PutCodebaseImageStream) getDockerRegistryEdpComponent(namespace string)
 	return ec, nil
 }
 
-func (h PutCodebaseImageStream) createCodebaseImageStreamIfNotExists(name, imageName, codebaseName, namespace string) error {
+// createCodebaseImageStreamIfNotExists creates the verified codebase image stream owned by the stage.
+// If the stream already exists, the stage ownership is set on it,
+// so the streams created before are removed with the stage as well.
+func (h PutCodebaseImageStream) createCodebaseImageStreamIfNotExists(
+	ctx context.Context,
+	stage *cdPipeApi.Stage,
+	name, imageName, codebaseName string,
+) error {
 	cis := &codebaseApi.CodebaseImageStream{
 		TypeMeta: metaV1.TypeMeta{
 			APIVersion: "v2.edp.epam.com/v1",
@@ -79,7 +106,11 @@ func (h PutCodebaseImageStream) createCodebaseImageStreamIfNotExists(name, image
 		},
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:      name,
-			Namespace: namespace,
+			Namespace: stage.Namespace,
+			Labels: map[string]string{
+				util.StageLabelName: stage.Name,
+			},
+			OwnerReferences: []metaV1.OwnerReference{stageOwnerReference(stage)},
 		},
 		Spec: codebaseApi.CodebaseImageStreamSpec{
 			Codebase:  codebaseName,
@@ -87,10 +118,11 @@ func (h PutCodebaseImageStream) createCodebaseImageStreamIfNotExists(name, image
 		},
 	}
 
-	if err := h.client.Create(context.TODO(), cis); err != nil {
+	if err := h.client.Create(ctx, cis); err != nil {
 		if k8sErrors.IsAlreadyExists(err) {
 			h.log.Info("codebase image stream already exists. skip creating...", "name", cis.Name)
-			return nil
+
+			return h.setCodebaseImageStreamOwner(ctx, stage, name)
 		}
 
 		return fmt.Errorf("failed to create codebase stream: %w", err)
@@ -100,3 +132,82 @@ func (h PutCodebaseImageStream) createCodebaseImageStreamIfNotExists(name, image
 
 	return nil
 }
+
+// setCodebaseImageStreamOwner sets the stage label and owner reference on the existing codebase image stream.
+func (h PutCodebaseImageStream) setCodebaseImageStreamOwner(ctx context.Context, stage *cdPipeApi.Stage, name string) error {
+	cis, err := cluster.GetCodebaseImageStream(ctx, h.client, name, stage.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get codebase image stream: %w", err)
+	}
+
+	if cis.Labels[util.StageLabelName] == stage.Name && hasOwnerReference(cis, stage) {
+		return nil
+	}
+
+	patch := client.MergeFrom(cis.DeepCopy())
+
+	if cis.Labels == nil {
+		cis.Labels = make(map[string]string, 1)
+	}
+
+	cis.Labels[util.StageLabelName] = stage.Name
+
+	if !hasOwnerReference(cis, stage) {
+		cis.OwnerReferences = append(cis.OwnerReferences, stageOwnerReference(stage))
+	}
+
+	if err = h.client.Patch(ctx, cis, patch); err != nil {
+		return fmt.Errorf("failed to set stage owner: %w", err)
+	}
+
+	h.log.Info("stage owner has been set on codebase image stream", "name", name)
+
+	return nil
+}
+
+// pruneCodebaseImageStreams deletes the stage verified codebase image streams
+// which are not in the keep list, e.g. for applications removed from the CDPipeline.
+func (h PutCodebaseImageStream) pruneCodebaseImageStreams(ctx context.Context, stage *cdPipeApi.Stage, keep []string) error {
+	streams := &codebaseApi.CodebaseImageStreamList{}
+	if err := h.client.List(
+		ctx,
+		streams,
+		client.InNamespace(stage.Namespace),
+		client.MatchingLabels{util.StageLabelName: stage.Name},
+	); err != nil {
+		return fmt.Errorf("failed to list codebase image streams: %w", err)
+	}
+
+	for i := range streams.Items {
+		if slices.Contains(keep, streams.Items[i].Name) {
+			continue
+		}
+
+		if err := h.client.Delete(ctx, &streams.Items[i]); err != nil && !k8sErrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete %s codebase image stream: %w", streams.Items[i].Name, err)
+		}
+
+		h.log.Info("codebase image stream has been deleted", "name", streams.Items[i].Name)
+	}
+
+	return nil
+}
+
+func stageOwnerReference(stage *cdPipeApi.Stage) metaV1.OwnerReference {
+	return metaV1.OwnerReference{
+		APIVersion: cdPipeApi.GroupVersion.String(),
+		Kind:       "Stage",
+		Name:       stage.Name,
+		UID:        stage.UID,
+	}
+}
+
+func hasOwnerReference(obj metaV1.Object, stage *cdPipeApi.Stage) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == "Stage" && ref.Name == stage.Name && ref.UID == stage.UID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -8,7 +8,10 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/apps/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -17,6 +20,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
 	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
 	k8sMockClient "github.com/epam/edp-common/pkg/mock/controller-runtime/client"
 	componentApi "github.com/epam/edp-component-operator/api/v1"
@@ -71,7 +75,7 @@ func TestPutCodebaseImageStream_ShouldCreateCis(t *testing.T) {
 
 	scheme := runtime.NewScheme()
 	scheme.AddKnownTypes(v1.SchemeGroupVersion, cdp, s, ec)
-	scheme.AddKnownTypes(schema.GroupVersion{Group: "v2.edp.epam.com", Version: "v1"}, cis)
+	scheme.AddKnownTypes(schema.GroupVersion{Group: "v2.edp.epam.com", Version: "v1"}, cis, &codebaseApi.CodebaseImageStreamList{})
 	c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cdp, s, ec, cis).Build()
 
 	cisChain := PutCodebaseImageStream{
@@ -79,7 +83,7 @@ func TestPutCodebaseImageStream_ShouldCreateCis(t *testing.T) {
 		log:    logr.Discard(),
 	}
 
-	err := cisChain.ServeRequest(s)
+	err := cisChain.ServeRequest(context.Background(), s)
 	assert.NoError(t, err)
 
 	cisResp := &codebaseApi.CodebaseImageStream{}
@@ -91,6 +95,9 @@ func TestPutCodebaseImageStream_ShouldCreateCis(t *testing.T) {
 		cisResp)
 	assert.NoError(t, err)
 	assert.Equal(t, cisResp.Spec.ImageName, "stub-url/stub-namespace/cb-name")
+	assert.Equal(t, s.Name, cisResp.Labels[util.StageLabelName])
+	assert.Equal(t, []metaV1.OwnerReference{stageOwnerReference(s)}, cisResp.OwnerReferences)
+	assert.True(t, meta.IsStatusConditionTrue(s.Status.Conditions, cdPipeApi.ConditionImageStreamsReady))
 }
 
 func TestPutCodebaseImageStream_ShouldNotFindCDPipeline(t *testing.T) {
@@ -126,12 +133,14 @@ func TestPutCodebaseImageStream_ShouldNotFindCDPipeline(t *testing.T) {
 		log:    logr.Discard(),
 	}
 
-	err := cisChain.ServeRequest(s)
+	err := cisChain.ServeRequest(context.Background(), s)
 	assert.Error(t, err)
 
 	if !strings.Contains(err.Error(), "non-existing-pipeline") {
 		t.Fatalf("wrong error returned: %s", err.Error())
 	}
+
+	assert.True(t, meta.IsStatusConditionFalse(s.Status.Conditions, cdPipeApi.ConditionImageStreamsReady))
 }
 
 func TestPutCodebaseImageStream_ShouldNotFindEDPComponent(t *testing.T) {
@@ -167,7 +176,7 @@ func TestPutCodebaseImageStream_ShouldNotFindEDPComponent(t *testing.T) {
 		log:    logr.Discard(),
 	}
 
-	err := cisChain.ServeRequest(s)
+	err := cisChain.ServeRequest(context.Background(), s)
 	assert.Error(t, err)
 
 	if !strings.Contains(err.Error(), "failed to get docker-registry EDP component") {
@@ -232,7 +241,7 @@ func TestPutCodebaseImageStream_ShouldNotFindCbis(t *testing.T) {
 		log:    logr.Discard(),
 	}
 
-	err := cisChain.ServeRequest(s)
+	err := cisChain.ServeRequest(context.Background(), s)
 	assert.Error(t, err)
 
 	if !strings.Contains(err.Error(), "failed to get cbis-name codebase image stream") {
@@ -299,7 +308,7 @@ func TestPutCodebaseImageStream_ShouldNotFailWithExistingCbis(t *testing.T) {
 
 	scheme := runtime.NewScheme()
 	scheme.AddKnownTypes(v1.SchemeGroupVersion, cdp, s, ec)
-	scheme.AddKnownTypes(schema.GroupVersion{Group: "v2.edp.epam.com", Version: "v1"}, cis, exsitingCis)
+	scheme.AddKnownTypes(schema.GroupVersion{Group: "v2.edp.epam.com", Version: "v1"}, cis, exsitingCis, &codebaseApi.CodebaseImageStreamList{})
 	c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cdp, s, ec, cis, exsitingCis).Build()
 
 	cisChain := PutCodebaseImageStream{
@@ -307,7 +316,7 @@ func TestPutCodebaseImageStream_ShouldNotFailWithExistingCbis(t *testing.T) {
 		log:    logr.Discard(),
 	}
 
-	err := cisChain.ServeRequest(s)
+	err := cisChain.ServeRequest(context.Background(), s)
 	assert.NoError(t, err)
 
 	cisResp := &codebaseApi.CodebaseImageStream{}
@@ -318,6 +327,93 @@ func TestPutCodebaseImageStream_ShouldNotFailWithExistingCbis(t *testing.T) {
 		},
 		cisResp)
 	assert.NoError(t, err)
+	assert.Equal(t, s.Name, cisResp.Labels[util.StageLabelName])
+	assert.True(t, hasOwnerReference(cisResp, s))
+}
+
+func TestPutCodebaseImageStream_ShouldPruneRemovedApplications(t *testing.T) {
+	const ns = "stub-namespace"
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+	require.NoError(t, codebaseApi.AddToScheme(scheme))
+	require.NoError(t, componentApi.AddToScheme(scheme))
+
+	s := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "cdp-name-stage-name",
+			Namespace: ns,
+		},
+		Spec: cdPipeApi.StageSpec{
+			Name:       "stage-name",
+			CdPipeline: "cdp-name",
+		},
+	}
+
+	verifiedCis := func(name string, labels map[string]string) *codebaseApi.CodebaseImageStream {
+		return &codebaseApi.CodebaseImageStream{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+				Labels:    labels,
+			},
+		}
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&cdPipeApi.CDPipeline{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      "cdp-name",
+				Namespace: ns,
+			},
+			Spec: cdPipeApi.CDPipelineSpec{
+				InputDockerStreams: []string{"cb-name-main"},
+			},
+		},
+		&componentApi.EDPComponent{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      dockerRegistryName,
+				Namespace: ns,
+			},
+			Spec: componentApi.EDPComponentSpec{
+				Url: "stub-url",
+			},
+		},
+		&codebaseApi.CodebaseImageStream{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      "cb-name-main",
+				Namespace: ns,
+			},
+			Spec: codebaseApi.CodebaseImageStreamSpec{
+				Codebase: "cb-name",
+			},
+		},
+		verifiedCis("cdp-name-stage-name-removed-verified", map[string]string{util.StageLabelName: s.Name}),
+		verifiedCis("cdp-name-qa-removed-verified", map[string]string{util.StageLabelName: "cdp-name-qa"}),
+	).Build()
+
+	cisChain := PutCodebaseImageStream{
+		client: c,
+		log:    logr.Discard(),
+	}
+
+	require.NoError(t, cisChain.ServeRequest(context.Background(), s))
+
+	err := c.Get(context.Background(), types.NamespacedName{
+		Name:      "cdp-name-stage-name-removed-verified",
+		Namespace: ns,
+	}, &codebaseApi.CodebaseImageStream{})
+	assert.True(t, k8sErrors.IsNotFound(err))
+
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{
+		Name:      "cdp-name-stage-name-cb-name-verified",
+		Namespace: ns,
+	}, &codebaseApi.CodebaseImageStream{}))
+
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{
+		Name:      "cdp-name-qa-removed-verified",
+		Namespace: ns,
+	}, &codebaseApi.CodebaseImageStream{}))
 }
 
 func TestPutCodebaseImageStream_ShouldFailCreatingCbis(t *testing.T) {
@@ -377,6 +473,10 @@ func TestPutCodebaseImageStream_ShouldFailCreatingCbis(t *testing.T) {
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:      "cdp-name-stage-name-cb-name-verified",
 			Namespace: "stub-namespace",
+			Labels: map[string]string{
+				util.StageLabelName: "stub-stage-name",
+			},
+			OwnerReferences: []metaV1.OwnerReference{stageOwnerReference(s)},
 		},
 		Spec: codebaseApi.CodebaseImageStreamSpec{
 			Codebase:  "cb-name",
@@ -415,7 +515,7 @@ func TestPutCodebaseImageStream_ShouldFailCreatingCbis(t *testing.T) {
 		log:    logr.Discard(),
 	}
 
-	err := cisChain.ServeRequest(s)
+	err := cisChain.ServeRequest(context.Background(), s)
 	assert.Error(t, err)
 
 	if !errors.Is(err, mockErr) {
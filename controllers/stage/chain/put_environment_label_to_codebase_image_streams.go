@@ -3,10 +3,13 @@ package chain
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
@@ -18,65 +21,98 @@ import (
 )
 
 type PutEnvironmentLabelToCodebaseImageStreams struct {
-	next   handler.CdStageHandler
-	client client.Client
-	log    logr.Logger
+	next     handler.CdStageHandler
+	client   client.Client
+	log      logr.Logger
+	recorder record.EventRecorder
 }
 
 // nolint
-func (h PutEnvironmentLabelToCodebaseImageStreams) ServeRequest(stage *cdPipeApi.Stage) error {
+func (h PutEnvironmentLabelToCodebaseImageStreams) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
 	logger := h.log.WithValues("stage name", stage.Name)
 	logger.Info("start creating environment labels in codebase image stream resources.")
 
-	pipe, err := util.GetCdPipeline(h.client, stage)
+	streams, err := h.putEnvironmentLabels(ctx, stage)
 	if err != nil {
-		return fmt.Errorf("couldn't get %s cd pipeline: %w", stage.Spec.CdPipeline, err)
+		setConditionFailed(stage, cdPipeApi.ConditionImageStreamsReady, err)
+
+		return err
+	}
+
+	logger.Info("environment labels have been added to codebase image stream resources.")
+	setConditionSucceeded(stage, cdPipeApi.ConditionImageStreamsReady, "Environment labels have been added to CodebaseImageStreams")
+	h.recorder.Eventf(stage, corev1.EventTypeNormal, cdPipeApi.EventReasonImageStreamLabelsMoved,
+		"Environment label %s has been set on CodebaseImageStreams: %s",
+		createLabelName(stage.Spec.CdPipeline, stage.Spec.Name), strings.Join(streams, ", "))
+
+	return nextServeOrNil(ctx, h.next, stage)
+}
+
+// putEnvironmentLabels sets the stage environment label on the CodebaseImageStreams and returns their names.
+func (h PutEnvironmentLabelToCodebaseImageStreams) putEnvironmentLabels(ctx context.Context, stage *cdPipeApi.Stage) ([]string, error) {
+	pipe, err := util.GetCdPipeline(ctx, h.client, stage)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get %s cd pipeline: %w", stage.Spec.CdPipeline, err)
 	}
 
 	if len(pipe.Spec.InputDockerStreams) == 0 {
-		return fmt.Errorf("pipeline %s doesn't contain codebase image streams", pipe.Name)
+		return nil, fmt.Errorf("pipeline %s doesn't contain codebase image streams", pipe.Name)
 	}
 
+	labeled := make([]string, 0, len(pipe.Spec.InputDockerStreams))
+
 	for _, name := range pipe.Spec.InputDockerStreams {
-		stream, err := cluster.GetCodebaseImageStream(h.client, name, stage.Namespace)
+		stream, err := cluster.GetCodebaseImageStream(ctx, h.client, name, stage.Namespace)
 		if err != nil {
-			return fmt.Errorf("couldn't get %s codebase image stream: %w", name, err)
+			return nil, fmt.Errorf("couldn't get %s codebase image stream: %w", name, err)
 		}
 
 		if stage.IsFirst() || !slices.Contains(pipe.Spec.ApplicationsToPromote, stream.Spec.Codebase) {
-			if updErr := h.updateLabel(stream, pipe.Name, stage.Spec.Name); updErr != nil {
-				return updErr
+			if updErr := h.updateLabel(ctx, stream, pipe.Name, stage.Spec.Name); updErr != nil {
+				return nil, updErr
 			}
 
+			labeled = append(labeled, stream.Name)
+
+			continue
+		}
+
+		// Verified streams of the previous stage are labeled by the stage controller
+		// once the new tags satisfy the promotion policy.
+		if stage.HasAutoPromotion() {
 			continue
 		}
 
-		previousStageName, err := util.FindPreviousStageName(context.TODO(), h.client, stage)
+		previousStageName, err := util.FindPreviousStageName(ctx, h.client, stage)
 		if err != nil {
-			return fmt.Errorf("failed to previous stage name: %w", err)
+			return nil, fmt.Errorf("failed to previous stage name: %w", err)
 		}
 
 		cisName := createCisName(pipe.Name, previousStageName, stream.Spec.Codebase)
 
-		verifiedStream, err := cluster.GetCodebaseImageStream(h.client, cisName, stage.Namespace)
+		verifiedStream, err := cluster.GetCodebaseImageStream(ctx, h.client, cisName, stage.Namespace)
 		if err != nil {
-			return edpError.CISNotFoundError(fmt.Sprintf("couldn't get %s codebase image stream", name))
+			return nil, edpError.CISNotFoundError(fmt.Sprintf("couldn't get %s codebase image stream", name))
 		}
 
-		if err := h.updateLabel(verifiedStream, pipe.Name, stage.Spec.Name); err != nil {
-			return err
+		if err := h.updateLabel(ctx, verifiedStream, pipe.Name, stage.Spec.Name); err != nil {
+			return nil, err
 		}
-	}
 
-	logger.Info("environment labels have been added to codebase image stream resources.")
+		labeled = append(labeled, verifiedStream.Name)
+	}
 
-	return nextServeOrNil(h.next, stage)
+	return labeled, nil
 }
 
-func (h PutEnvironmentLabelToCodebaseImageStreams) updateLabel(cis *codebaseApi.CodebaseImageStream, pipeName, stageName string) error {
+func (h PutEnvironmentLabelToCodebaseImageStreams) updateLabel(
+	ctx context.Context,
+	cis *codebaseApi.CodebaseImageStream,
+	pipeName, stageName string,
+) error {
 	setLabel(&cis.ObjectMeta, pipeName, stageName)
 
-	if err := h.client.Update(context.Background(), cis); err != nil {
+	if err := h.client.Update(ctx, cis); err != nil {
 		return fmt.Errorf("couldn't update %s codebase image stream: %w", cis.Name, err)
 	}
 
@@ -95,9 +131,9 @@ func setLabel(meta *metaV1.ObjectMeta, pipelineName, stageName string) {
 }
 
 func createLabelName(pipeName, stageName string) string {
-	return fmt.Sprintf("%s/%s", pipeName, stageName)
+	return util.EnvironmentLabelName(pipeName, stageName)
 }
 
 func createCisName(pipeName, previousStageName, codebase string) string {
-	return fmt.Sprintf("%s-%s-%s-verified", pipeName, previousStageName, codebase)
+	return util.VerifiedImageStreamName(pipeName, previousStageName, codebase)
 }
@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -10,6 +11,7 @@ import (
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
@@ -74,14 +76,15 @@ func TestPutEnvironmentLabelToCodebaseImageStreams_ServeRequest_Success(t *testi
 	}
 
 	putEnvLabel := PutEnvironmentLabelToCodebaseImageStreams{
-		client: fake.NewClientBuilder().WithScheme(schemeInit(t)).WithObjects(&stage, &cdPipeline, &image).Build(),
-		log:    logr.Discard(),
+		client:   fake.NewClientBuilder().WithScheme(schemeInit(t)).WithObjects(&stage, &cdPipeline, &image).Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	err := putEnvLabel.ServeRequest(&stage)
+	err := putEnvLabel.ServeRequest(context.Background(), &stage)
 	assert.NoError(t, err)
 
-	imageStream, err := cluster.GetCodebaseImageStream(putEnvLabel.client, dockerImageName, namespace)
+	imageStream, err := cluster.GetCodebaseImageStream(context.Background(), putEnvLabel.client, dockerImageName, namespace)
 	assert.NoError(t, err)
 
 	_, ok := imageStream.Labels[createLabelName(cdPipeline.Name, stage.Name)]
@@ -129,14 +132,15 @@ func TestPutEnvironmentLabelToCodebaseImageStreams_ServeRequest_PreviousStageIma
 	}
 
 	putEnvLabel := PutEnvironmentLabelToCodebaseImageStreams{
-		client: fake.NewClientBuilder().WithScheme(schemeInit(t)).WithObjects(&stage, &prevStage, &cdPipeline, &image, &previousImage).Build(),
-		log:    logr.Discard(),
+		client:   fake.NewClientBuilder().WithScheme(schemeInit(t)).WithObjects(&stage, &prevStage, &cdPipeline, &image, &previousImage).Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	err := putEnvLabel.ServeRequest(&stage)
+	err := putEnvLabel.ServeRequest(context.Background(), &stage)
 	assert.NoError(t, err)
 
-	imageStream, err := cluster.GetCodebaseImageStream(putEnvLabel.client, cisName, namespace)
+	imageStream, err := cluster.GetCodebaseImageStream(context.Background(), putEnvLabel.client, cisName, namespace)
 	assert.NoError(t, err)
 
 	_, ok := imageStream.Labels[createLabelName(cdPipeline.Name, stage.Name)]
@@ -147,11 +151,12 @@ func TestPutEnvironmentLabelToCodebaseImageStreams_ServeRequest_CantGetCdPipelin
 	stage := createStage(t, 0, cdPipeline)
 
 	putEnvLabel := PutEnvironmentLabelToCodebaseImageStreams{
-		client: fake.NewClientBuilder().WithScheme(schemeInit(t)).WithObjects(&stage).Build(),
-		log:    logr.Discard(),
+		client:   fake.NewClientBuilder().WithScheme(schemeInit(t)).WithObjects(&stage).Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	err := putEnvLabel.ServeRequest(&stage)
+	err := putEnvLabel.ServeRequest(context.Background(), &stage)
 	assert.True(t, k8sErrors.IsNotFound(err))
 }
 
@@ -171,11 +176,12 @@ func TestPutEnvironmentLabelToCodebaseImageStreams_ServeRequest_EmptyInputDocker
 	}
 
 	putEnvLabel := PutEnvironmentLabelToCodebaseImageStreams{
-		client: fake.NewClientBuilder().WithScheme(schemeInit(t)).WithObjects(&stage, &cdPipeline).Build(),
-		log:    logr.Discard(),
+		client:   fake.NewClientBuilder().WithScheme(schemeInit(t)).WithObjects(&stage, &cdPipeline).Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	err := putEnvLabel.ServeRequest(&stage)
+	err := putEnvLabel.ServeRequest(context.Background(), &stage)
 	assert.Equal(t, fmt.Errorf("pipeline %s doesn't contain codebase image streams", cdPipeline.Name), err)
 }
 
@@ -195,11 +201,12 @@ func TestPutEnvironmentLabelToCodebaseImageStreams_ServeRequest_CantGetImage(t *
 	}
 
 	putEnvLabel := PutEnvironmentLabelToCodebaseImageStreams{
-		client: fake.NewClientBuilder().WithScheme(schemeInit(t)).WithObjects(&stage, &cdPipeline).Build(),
-		log:    logr.Discard(),
+		client:   fake.NewClientBuilder().WithScheme(schemeInit(t)).WithObjects(&stage, &cdPipeline).Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	err := putEnvLabel.ServeRequest(&stage)
+	err := putEnvLabel.ServeRequest(context.Background(), &stage)
 	assert.True(t, k8sErrors.IsNotFound(err))
 }
 
@@ -235,10 +242,48 @@ func TestPutEnvironmentLabelToCodebaseImageStreams_ServeRequest_CantGetPreviousS
 	}
 
 	putEnvLabel := PutEnvironmentLabelToCodebaseImageStreams{
-		client: fake.NewClientBuilder().WithScheme(schemeInit(t)).WithObjects(&stage, &prevStage, &cdPipeline, &image).Build(),
-		log:    logr.Discard(),
+		client:   fake.NewClientBuilder().WithScheme(schemeInit(t)).WithObjects(&stage, &prevStage, &cdPipeline, &image).Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	err := putEnvLabel.ServeRequest(&stage)
+	err := putEnvLabel.ServeRequest(context.Background(), &stage)
 	assert.Equal(t, edpErr.CISNotFoundError(fmt.Sprintf("couldn't get %v codebase image stream", dockerImageName)), err)
 }
+
+func TestPutEnvironmentLabelToCodebaseImageStreams_ServeRequest_SkipsAutoPromotedImages(t *testing.T) {
+	stage := createStage(t, 1, cdPipeline)
+	stage.Spec.PromotionPolicy = &cdPipeApi.PromotionPolicy{Auto: true}
+
+	cdPipeline := cdPipeApi.CDPipeline{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      cdPipeline,
+			Namespace: namespace,
+		},
+		Spec: cdPipeApi.CDPipelineSpec{
+			InputDockerStreams:    []string{dockerImageName},
+			Applications:          []string{codebase},
+			ApplicationsToPromote: []string{codebase},
+			Name:                  name,
+		},
+	}
+
+	image := codebaseApi.CodebaseImageStream{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      dockerImageName,
+			Namespace: namespace,
+		},
+		Spec: codebaseApi.CodebaseImageStreamSpec{
+			Codebase: codebase,
+		},
+	}
+
+	putEnvLabel := PutEnvironmentLabelToCodebaseImageStreams{
+		client:   fake.NewClientBuilder().WithScheme(schemeInit(t)).WithObjects(&stage, &cdPipeline, &image).Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	err := putEnvLabel.ServeRequest(context.Background(), &stage)
+	assert.NoError(t, err)
+}
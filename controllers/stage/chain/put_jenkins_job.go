@@ -7,9 +7,11 @@ import (
 	"strings"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -21,9 +23,10 @@ import (
 )
 
 type PutJenkinsJob struct {
-	next   handler.CdStageHandler
-	client client.Client
-	log    logr.Logger
+	next     handler.CdStageHandler
+	client   client.Client
+	log      logr.Logger
+	recorder record.EventRecorder
 }
 
 type qualityGate struct {
@@ -39,27 +42,38 @@ const (
 	jenkinsJobKind           = "JenkinsJob"
 )
 
-func (h PutJenkinsJob) ServeRequest(stage *cdPipeApi.Stage) error {
+func (h PutJenkinsJob) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
 	logger := h.log.WithValues("stage name", stage.Name)
 	logger.Info("start creating jenkins job cr.")
 
-	if err := h.tryToUpdateJenkinsJobConfig(stage); err != nil {
+	if err := h.putJenkinsJob(ctx, stage); err != nil {
+		setConditionFailed(stage, cdPipeApi.ConditionJenkinsJobReady, err)
+
+		return err
+	}
+
+	logger.Info("jenkins job cr has been created")
+	setConditionSucceeded(stage, cdPipeApi.ConditionJenkinsJobReady, fmt.Sprintf("JenkinsJob %s has been created", stage.Name))
+
+	return nextServeOrNil(ctx, h.next, stage)
+}
+
+func (h PutJenkinsJob) putJenkinsJob(ctx context.Context, stage *cdPipeApi.Stage) error {
+	if err := h.tryToUpdateJenkinsJobConfig(ctx, stage); err != nil {
 		return fmt.Errorf("failed to update %v JenkinsJob CR config: %w", stage.Name, err)
 	}
 
-	if err := h.tryToCreateJenkinsJob(stage); err != nil {
+	if err := h.tryToCreateJenkinsJob(ctx, stage); err != nil {
 		return fmt.Errorf("failed to create %v JenkinsJob CR: %w", stage.Name, err)
 	}
 
-	logger.Info("jenkins job cr has been created")
-
-	return nextServeOrNil(h.next, stage)
+	return nil
 }
 
-func (h PutJenkinsJob) tryToCreateJenkinsJob(stage *cdPipeApi.Stage) error {
+func (h PutJenkinsJob) tryToCreateJenkinsJob(ctx context.Context, stage *cdPipeApi.Stage) error {
 	h.log.Info("start creating JenkinsJob CR", crNameLogKey, stage.Name)
 
-	jc, err := h.createJenkinsJobConfig(stage)
+	jc, err := h.createJenkinsJobConfig(ctx, stage)
 	if err != nil {
 		return err
 	}
@@ -86,7 +100,7 @@ func (h PutJenkinsJob) tryToCreateJenkinsJob(stage *cdPipeApi.Stage) error {
 			Action: cdPipeApi.AcceptJenkinsJob,
 		},
 	}
-	if err = h.client.Create(context.TODO(), jj); err != nil {
+	if err = h.client.Create(ctx, jj); err != nil {
 		if k8sErrors.IsAlreadyExists(err) {
 			h.log.Info("jenkins job already exists. skip creating...", crNameLogKey, stage.Name)
 			return nil
@@ -96,12 +110,13 @@ func (h PutJenkinsJob) tryToCreateJenkinsJob(stage *cdPipeApi.Stage) error {
 	}
 
 	h.log.Info("JenkinsJob has been created", crNameLogKey, stage.Name)
+	h.recorder.Eventf(stage, corev1.EventTypeNormal, cdPipeApi.EventReasonJenkinsJobCreated, "JenkinsJob %s has been created", jj.Name)
 
 	return nil
 }
 
-func (h PutJenkinsJob) tryToUpdateJenkinsJobConfig(stage *cdPipeApi.Stage) error {
-	jenkinsJob, err := h.getJenkinsJob(stage.Name, stage.Namespace)
+func (h PutJenkinsJob) tryToUpdateJenkinsJobConfig(ctx context.Context, stage *cdPipeApi.Stage) error {
+	jenkinsJob, err := h.getJenkinsJob(ctx, stage.Name, stage.Namespace)
 	if k8sErrors.IsNotFound(err) {
 		h.log.Info("jenkins job does not exists. skip updating...", crNameLogKey, stage.Name)
 		return nil
@@ -111,28 +126,34 @@ func (h PutJenkinsJob) tryToUpdateJenkinsJobConfig(stage *cdPipeApi.Stage) error
 		return err
 	}
 
-	jc, err := h.createJenkinsJobConfig(stage)
+	jc, err := h.createJenkinsJobConfig(ctx, stage)
 	if err != nil {
 		return err
 	}
 
+	if jenkinsJob.Spec.Job.Config == string(jc) {
+		h.log.Info("JenkinsJob config is up to date. skip updating...", crNameLogKey, stage.Name)
+		return nil
+	}
+
 	jenkinsJob.Spec.Job.Config = string(jc)
-	if err = h.client.Update(context.TODO(), jenkinsJob); err != nil {
+	if err = h.client.Update(ctx, jenkinsJob); err != nil {
 		return fmt.Errorf("failed to  update jenkins job config: %w", err)
 	}
 
 	h.log.Info("JenkinsJob config has been updated...", crNameLogKey, stage.Name)
+	h.recorder.Eventf(stage, corev1.EventTypeNormal, cdPipeApi.EventReasonJenkinsJobUpdated, "JenkinsJob %s config has been updated", jenkinsJob.Name)
 
 	return nil
 }
 
-func (h PutJenkinsJob) createJenkinsJobConfig(stage *cdPipeApi.Stage) ([]byte, error) {
+func (h PutJenkinsJob) createJenkinsJobConfig(ctx context.Context, stage *cdPipeApi.Stage) ([]byte, error) {
 	qgStages, err := getQualityGateStages(stage.Spec.QualityGates)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse quality gate stages: %w", err)
 	}
 
-	dt, err := h.getDeploymentType(stage)
+	dt, err := h.getDeploymentType(ctx, stage)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get deploymentType value: %w", err)
 	}
@@ -150,7 +171,7 @@ func (h PutJenkinsJob) createJenkinsJobConfig(stage *cdPipeApi.Stage) ([]byte, e
 	if stage.Spec.Source.Type == "library" {
 		var library map[string]string
 
-		library, err = h.setLibraryParams(stage)
+		library, err = h.setLibraryParams(ctx, stage)
 		if err == nil {
 			jpm["LIBRARY_URL"] = library["url"]
 			jpm["LIBRARY_BRANCH"] = library["branch"]
@@ -169,8 +190,8 @@ func (h PutJenkinsJob) createJenkinsJobConfig(stage *cdPipeApi.Stage) ([]byte, e
 	return jc, nil
 }
 
-func (h PutJenkinsJob) getDeploymentType(stage *cdPipeApi.Stage) (*string, error) {
-	p, err := util.GetCdPipeline(h.client, stage)
+func (h PutJenkinsJob) getDeploymentType(ctx context.Context, stage *cdPipeApi.Stage) (*string, error) {
+	p, err := util.GetCdPipeline(ctx, h.client, stage)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pipeline: %w", err)
 	}
@@ -255,15 +276,15 @@ func handleManualStage(qg cdPipeApi.QualityGate, result *[]interface{}) {
 	})
 }
 
-func (h PutJenkinsJob) setLibraryParams(stage *cdPipeApi.Stage) (map[string]string, error) {
-	cb, err := h.getLibraryParams(stage.Spec.Source.Library.Name, stage.Namespace)
+func (h PutJenkinsJob) setLibraryParams(ctx context.Context, stage *cdPipeApi.Stage) (map[string]string, error) {
+	cb, err := h.getLibraryParams(ctx, stage.Spec.Source.Library.Name, stage.Namespace)
 	if err != nil {
 		h.log.Error(err, "couldn't retrieve parameters for pipeline's library, default source type will be used",
 			"Library name", stage.Spec.Source.Library.Name)
 		return nil, err
 	}
 
-	gs, err := h.getGitServerParams(cb.Spec.GitServer, stage.Namespace)
+	gs, err := h.getGitServerParams(ctx, cb.Spec.GitServer, stage.Namespace)
 	if err != nil {
 		h.log.Error(err, "couldn't retrieve parameters for git server, default source type will be used",
 			"Git server", cb.Spec.GitServer)
@@ -279,9 +300,9 @@ func (h PutJenkinsJob) setLibraryParams(stage *cdPipeApi.Stage) (map[string]stri
 	}, nil
 }
 
-func (h PutJenkinsJob) getLibraryParams(name, ns string) (*codebaseApi.Codebase, error) {
+func (h PutJenkinsJob) getLibraryParams(ctx context.Context, name, ns string) (*codebaseApi.Codebase, error) {
 	i := &codebaseApi.Codebase{}
-	if err := h.client.Get(context.TODO(), types.NamespacedName{
+	if err := h.client.Get(ctx, types.NamespacedName{
 		Namespace: ns,
 		Name:      name,
 	}, i); err != nil {
@@ -291,9 +312,9 @@ func (h PutJenkinsJob) getLibraryParams(name, ns string) (*codebaseApi.Codebase,
 	return i, nil
 }
 
-func (h PutJenkinsJob) getGitServerParams(name, ns string) (*codebaseApi.GitServer, error) {
+func (h PutJenkinsJob) getGitServerParams(ctx context.Context, name, ns string) (*codebaseApi.GitServer, error) {
 	i := &codebaseApi.GitServer{}
-	if err := h.client.Get(context.TODO(), types.NamespacedName{
+	if err := h.client.Get(ctx, types.NamespacedName{
 		Namespace: ns,
 		Name:      name,
 	}, i); err != nil {
@@ -319,9 +340,9 @@ func getAutoDeployStatus(tt string) string {
 	return "false"
 }
 
-func (h PutJenkinsJob) getJenkinsJob(name, namespace string) (*jenkinsApi.JenkinsJob, error) {
+func (h PutJenkinsJob) getJenkinsJob(ctx context.Context, name, namespace string) (*jenkinsApi.JenkinsJob, error) {
 	jj := &jenkinsApi.JenkinsJob{}
-	if err := h.client.Get(context.TODO(), types.NamespacedName{
+	if err := h.client.Get(ctx, types.NamespacedName{
 		Name:      name,
 		Namespace: namespace,
 	}, jj); err != nil {
@@ -1,13 +1,16 @@
 package chain
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -215,11 +218,12 @@ func TestCreateJenkinsJobConfig_Success(t *testing.T) {
 	}
 
 	putJenkinsJob := PutJenkinsJob{
-		client: fake.NewClientBuilder().WithScheme(putJenkinsJobSchemeInit(t)).WithObjects(cdPipeline, stage).Build(),
-		log:    logr.Discard(),
+		client:   fake.NewClientBuilder().WithScheme(putJenkinsJobSchemeInit(t)).WithObjects(cdPipeline, stage).Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	resultJson, err := putJenkinsJob.createJenkinsJobConfig(stage)
+	resultJson, err := putJenkinsJob.createJenkinsJobConfig(context.Background(), stage)
 	assert.NoError(t, err)
 
 	result := make(map[string]string)
@@ -235,11 +239,12 @@ func TestCreateJenkinsJobConfig_WithLibraryParams(t *testing.T) {
 	gitServer := putJenkinsJobCreateGitServer(t)
 
 	putJenkinsJob := PutJenkinsJob{
-		client: fake.NewClientBuilder().WithScheme(putJenkinsJobSchemeInit(t)).WithObjects(cdPipeline, stage, codeBase, gitServer).Build(),
-		log:    logr.Discard(),
+		client:   fake.NewClientBuilder().WithScheme(putJenkinsJobSchemeInit(t)).WithObjects(cdPipeline, stage, codeBase, gitServer).Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	resultJson, err := putJenkinsJob.createJenkinsJobConfig(stage)
+	resultJson, err := putJenkinsJob.createJenkinsJobConfig(context.Background(), stage)
 	assert.NoError(t, err)
 
 	result := make(map[string]string)
@@ -262,14 +267,15 @@ func TestTryToUpdateJenkinsJob_Success(t *testing.T) {
 	}
 
 	putJenkinsJob := PutJenkinsJob{
-		client: fake.NewClientBuilder().WithScheme(putJenkinsJobSchemeInit(t)).WithObjects(cdPipeline, stage, codeBase, gitServer, &jenkinsJob).Build(),
-		log:    logr.Discard(),
+		client:   fake.NewClientBuilder().WithScheme(putJenkinsJobSchemeInit(t)).WithObjects(cdPipeline, stage, codeBase, gitServer, &jenkinsJob).Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	err := putJenkinsJob.tryToUpdateJenkinsJobConfig(stage)
+	err := putJenkinsJob.tryToUpdateJenkinsJobConfig(context.Background(), stage)
 	assert.NoError(t, err)
 
-	jenkinsJobAfterUpdate, err := putJenkinsJob.getJenkinsJob(name, namespace)
+	jenkinsJobAfterUpdate, err := putJenkinsJob.getJenkinsJob(context.Background(), name, namespace)
 	assert.NoError(t, err)
 	assert.NotNil(t, jenkinsJobAfterUpdate.Spec.Job.Config)
 	assert.NotEmpty(t, jenkinsJobAfterUpdate.Spec.Job.Config)
@@ -286,11 +292,12 @@ func TestTryToUpdateJenkinsJob_NotFound(t *testing.T) {
 	jenkinsJob := jenkinsApi.JenkinsJob{}
 
 	putJenkinsJob := PutJenkinsJob{
-		client: fake.NewClientBuilder().WithScheme(putJenkinsJobSchemeInit(t)).WithObjects(stage, &jenkinsJob).Build(),
-		log:    logr.Discard(),
+		client:   fake.NewClientBuilder().WithScheme(putJenkinsJobSchemeInit(t)).WithObjects(stage, &jenkinsJob).Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	err := putJenkinsJob.tryToUpdateJenkinsJobConfig(stage)
+	err := putJenkinsJob.tryToUpdateJenkinsJobConfig(context.Background(), stage)
 	assert.Nil(t, err)
 }
 
@@ -305,14 +312,15 @@ func TestTryToCreateJenkinsJobConfig_Success(t *testing.T) {
 			WithScheme(putJenkinsJobSchemeInit(t)).
 			WithObjects(cdPipeline, stage, codeBase, gitServer).
 			Build(),
-		log: logr.Discard(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	if err := putJenkinsJob.tryToCreateJenkinsJob(stage); err != nil {
+	if err := putJenkinsJob.tryToCreateJenkinsJob(context.Background(), stage); err != nil {
 		t.Fatal(err)
 	}
 
-	jenkinsJobAfterUpdate, err := putJenkinsJob.getJenkinsJob(name, namespace)
+	jenkinsJobAfterUpdate, err := putJenkinsJob.getJenkinsJob(context.Background(), name, namespace)
 	assert.NoError(t, err)
 	assert.NotNil(t, jenkinsJobAfterUpdate.Spec.Job.Config)
 	assert.NotEmpty(t, jenkinsJobAfterUpdate.Spec.Job.Config)
@@ -325,15 +333,17 @@ func TestPutJenkinsJob_ServeRequest_Success(t *testing.T) {
 	gitServer := putJenkinsJobCreateGitServer(t)
 
 	putJenkinsJob := PutJenkinsJob{
-		client: fake.NewClientBuilder().WithScheme(putJenkinsJobSchemeInit(t)).WithObjects(cdPipeline, stage, codeBase, gitServer).Build(),
-		log:    logr.Discard(),
+		client:   fake.NewClientBuilder().WithScheme(putJenkinsJobSchemeInit(t)).WithObjects(cdPipeline, stage, codeBase, gitServer).Build(),
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
-	if err := putJenkinsJob.ServeRequest(stage); err != nil {
+	if err := putJenkinsJob.ServeRequest(context.Background(), stage); err != nil {
 		t.Fatal(err)
 	}
 
-	jenkinsJobAfterUpdate, err := putJenkinsJob.getJenkinsJob(name, namespace)
+	jenkinsJobAfterUpdate, err := putJenkinsJob.getJenkinsJob(context.Background(), name, namespace)
 	assert.NoError(t, err)
 	assert.NotNil(t, jenkinsJobAfterUpdate.Spec.Job.Config)
 	assert.NotEmpty(t, jenkinsJobAfterUpdate.Spec.Job.Config)
+	assert.True(t, meta.IsStatusConditionTrue(stage.Status.Conditions, cdPipeApi.ConditionJenkinsJobReady))
 }
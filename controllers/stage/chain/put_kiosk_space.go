@@ -5,41 +5,51 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
-	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
 	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/handler"
 	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/kiosk"
-	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/consts"
 )
 
 type PutKioskSpace struct {
-	next   handler.CdStageHandler
-	space  kiosk.SpaceManager
-	client client.Client
-	log    logr.Logger
+	next  handler.CdStageHandler
+	space kiosk.SpaceManager
+	// pipelineClient is used to get the stage CDPipeline from the operator cluster.
+	pipelineClient client.Client
+	log            logr.Logger
+	recorder       record.EventRecorder
 }
 
-func (h PutKioskSpace) ServeRequest(stage *cdPipeApi.Stage) error {
-	name := util.GenerateNamespaceName(stage)
+func (h PutKioskSpace) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	name := util.GetTargetNamespace(stage)
 	h.log.Info("try to create namespace", "name", name)
 
-	if err := h.createSpace(name, stage.Namespace); err != nil {
-		if setErr := h.setFailedStatus(context.Background(), stage, err); setErr != nil {
-			return fmt.Errorf("failed to update stage %s status: %w", stage.Name, err)
-		}
+	if err := h.createSpace(ctx, name, stage); err != nil {
+		err = fmt.Errorf("failed to create %s loft kiosk space cr: %w", name, err)
+		setConditionFailed(stage, cdPipeApi.ConditionNamespaceReady, err)
+
+		return err
+	}
+
+	if err := h.putMetadata(ctx, name, stage); err != nil {
+		err = fmt.Errorf("failed to set %s kiosk space labels and annotations: %w", name, err)
+		setConditionFailed(stage, cdPipeApi.ConditionNamespaceReady, err)
 
-		return fmt.Errorf("failed to create %s lofk kiosk space cr: %w", name, err)
+		return err
 	}
 
-	return nextServeOrNil(h.next, stage)
+	setConditionSucceeded(stage, cdPipeApi.ConditionNamespaceReady, fmt.Sprintf("Kiosk space %s is ready", name))
+
+	return nextServeOrNil(ctx, h.next, stage)
 }
 
-func (h PutKioskSpace) createSpace(name, account string) error {
-	exists, err := h.spaceExists(name)
+func (h PutKioskSpace) createSpace(ctx context.Context, name string, stage *cdPipeApi.Stage) error {
+	exists, err := h.spaceExists(ctx, name)
 	if err != nil {
 		return err
 	}
@@ -49,51 +59,63 @@ func (h PutKioskSpace) createSpace(name, account string) error {
 		return nil
 	}
 
-	err = h.space.Create(name, account)
+	err = h.space.Create(ctx, name, stage.Namespace, map[string]string{util.StageLabelName: stage.Name})
 	if err != nil {
 		return fmt.Errorf("failed to create kiosk space: %w", err)
 	}
 
+	h.recorder.Eventf(stage, corev1.EventTypeNormal, cdPipeApi.EventReasonNamespaceCreated, "Kiosk space %s has been created", name)
+
 	return nil
 }
 
-func (h PutKioskSpace) spaceExists(name string) (bool, error) {
-	h.log.Info("checking existence of space cr", "name", name)
-
-	_, err := h.space.Get(name)
+// putMetadata sets the configured labels and annotations on the kiosk space and removes the ones removed from the spec.
+// It is done for the existing spaces as well, so the space is kept in sync with the stage.
+func (h PutKioskSpace) putMetadata(ctx context.Context, name string, stage *cdPipeApi.Stage) error {
+	metadata, err := getNamespaceMetadata(ctx, h.pipelineClient, stage)
 	if err != nil {
-		if k8sErrors.IsNotFound(err) {
-			return false, nil
-		}
+		return err
+	}
 
-		return false, fmt.Errorf("failed to get space: %w", err)
+	if metadata.skip(stage) {
+		return nil
 	}
 
-	return true, nil
-}
+	space, err := h.space.Get(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to get kiosk space: %w", err)
+	}
 
-func (h PutKioskSpace) setFailedStatus(ctx context.Context, stage *cdPipeApi.Stage, err error) error {
-	updateStatus := func(ctx context.Context, stage *cdPipeApi.Stage) error {
-		if err = h.client.Status().Update(ctx, stage); err != nil {
-			if err = h.client.Update(ctx, stage); err != nil {
-				return fmt.Errorf("failed to update kiosk space status: %w", err)
-			}
-		}
+	if err = checkNamespaceCanBeConfigured(space, stage); err != nil {
+		return err
+	}
 
-		h.log.Info("stage status has been updated.", "name", stage.Name)
+	if !metadata.apply(space, stage) {
+		metadata.record(stage)
 
 		return nil
 	}
 
-	stage.Status = cdPipeApi.StageStatus{
-		Status:          consts.FailedStatus,
-		Available:       false,
-		LastTimeUpdated: metaV1.Now(),
-		Username:        stage.Status.Username,
-		Result:          cdPipeApi.Error,
-		DetailedMessage: err.Error(),
-		Value:           consts.FailedStatus,
+	if err = h.space.Update(ctx, space); err != nil {
+		return fmt.Errorf("failed to update kiosk space: %w", err)
 	}
 
-	return updateStatus(ctx, stage)
+	metadata.record(stage)
+
+	return nil
+}
+
+func (h PutKioskSpace) spaceExists(ctx context.Context, name string) (bool, error) {
+	h.log.Info("checking existence of space cr", "name", name)
+
+	_, err := h.space.Get(ctx, name)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get space: %w", err)
+	}
+
+	return true, nil
 }
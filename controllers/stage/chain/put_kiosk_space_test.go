@@ -2,20 +2,23 @@ package chain
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/go-logr/logr"
-	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
 	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/kiosk"
-	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/consts"
 )
 
 const (
@@ -26,7 +29,7 @@ func kioskSpaceScheme(t *testing.T) *runtime.Scheme {
 	t.Helper()
 
 	scheme := runtime.NewScheme()
-	scheme.AddKnownTypes(v1.SchemeGroupVersion, &cdPipeApi.Stage{})
+	scheme.AddKnownTypes(v1.SchemeGroupVersion, &cdPipeApi.Stage{}, &cdPipeApi.CDPipeline{})
 
 	return scheme
 }
@@ -37,12 +40,12 @@ func TestSpaceExist_NotFound(t *testing.T) {
 	space := kiosk.InitSpace(client)
 
 	putKioskSpace := PutKioskSpace{
-		space:  space,
-		client: client,
-		log:    logr.Discard(),
+		space:    space,
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	exists, err := putKioskSpace.spaceExists(name)
+	exists, err := putKioskSpace.spaceExists(context.Background(), name)
 	assert.NoError(t, err)
 	assert.False(t, exists)
 }
@@ -62,12 +65,12 @@ func TestSpaceExist_Success(t *testing.T) {
 	spaceManager := kiosk.InitSpace(client)
 
 	putKioskSpace := PutKioskSpace{
-		space:  spaceManager,
-		client: client,
-		log:    logr.Discard(),
+		space:    spaceManager,
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	exists, err := putKioskSpace.spaceExists(name)
+	exists, err := putKioskSpace.spaceExists(context.Background(), name)
 	assert.NoError(t, err)
 	assert.True(t, exists)
 }
@@ -77,18 +80,21 @@ func TestCreateSpace_Success(t *testing.T) {
 
 	spaceManager := kiosk.InitSpace(client)
 
+	recorder := record.NewFakeRecorder(1)
+
 	putKioskSpace := PutKioskSpace{
-		space:  spaceManager,
-		client: client,
-		log:    logr.Discard(),
+		space:    spaceManager,
+		log:      logr.Discard(),
+		recorder: recorder,
 	}
 
-	err := putKioskSpace.createSpace(name, account)
+	err := putKioskSpace.createSpace(context.Background(), name, &cdPipeApi.Stage{ObjectMeta: metaV1.ObjectMeta{Namespace: account}})
 	assert.NoError(t, err)
 
-	space, err := spaceManager.Get(name)
+	space, err := spaceManager.Get(context.Background(), name)
 	assert.NoError(t, err)
 	assert.Equal(t, name, space.GetName())
+	assert.Contains(t, <-recorder.Events, cdPipeApi.EventReasonNamespaceCreated)
 }
 
 func TestCreateSpace_AlreadyExists(t *testing.T) {
@@ -106,34 +112,44 @@ func TestCreateSpace_AlreadyExists(t *testing.T) {
 	spaceManager := kiosk.InitSpace(client)
 
 	putKioskSpace := PutKioskSpace{
-		space:  spaceManager,
-		client: client,
-		log:    logr.Discard(),
+		space:    spaceManager,
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	_, err := spaceManager.Get(name)
+	_, err := spaceManager.Get(context.Background(), name)
 	assert.NoError(t, err)
 
-	err = putKioskSpace.createSpace(name, account)
+	err = putKioskSpace.createSpace(context.Background(), name, &cdPipeApi.Stage{ObjectMeta: metaV1.ObjectMeta{Namespace: account}})
 	assert.NoError(t, err)
 }
 
-func TestSetFailedStatus_Success(t *testing.T) {
-	stage := emptyStageInit(t)
-
-	client := fake.NewClientBuilder().WithScheme(kioskSpaceScheme(t)).WithObjects(stage).Build()
+// failingSpaceManager is a kiosk.SpaceManager which fails to get spaces.
+type failingSpaceManager struct {
+	kiosk.SpaceManager
+}
 
-	spaceManager := kiosk.InitSpace(client)
+func (failingSpaceManager) Get(context.Context, string) (*unstructured.Unstructured, error) {
+	return nil, errors.New("connection refused")
+}
 
+func TestPutKioskSpace_ServeRequest_KeepsStatus(t *testing.T) {
 	putKioskSpace := PutKioskSpace{
-		space:  spaceManager,
-		client: client,
-		log:    logr.Discard(),
+		space:    failingSpaceManager{},
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
-	err := putKioskSpace.setFailedStatus(context.Background(), stage, errors.New(""))
-	assert.NoError(t, err)
-	assert.Equal(t, consts.FailedStatus, stage.Status.Status)
+	stage := emptyStageInit(t)
+	stage.Status.Applications = []cdPipeApi.ApplicationVersion{{Name: "app"}}
+	stage.Status.ChainSteps = []string{"PutKioskSpace"}
+
+	err := putKioskSpace.ServeRequest(context.Background(), stage)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create")
+	assert.Equal(t, []cdPipeApi.ApplicationVersion{{Name: "app"}}, stage.Status.Applications)
+	assert.Equal(t, []string{"PutKioskSpace"}, stage.Status.ChainSteps)
+	assert.True(t, meta.IsStatusConditionFalse(stage.Status.Conditions, cdPipeApi.ConditionNamespaceReady))
 }
 
 func TestPutKioskSpace_ServeRequest_Success(t *testing.T) {
@@ -142,19 +158,68 @@ func TestPutKioskSpace_ServeRequest_Success(t *testing.T) {
 	spaceManager := kiosk.InitSpace(client)
 
 	putKioskSpace := PutKioskSpace{
-		space:  spaceManager,
-		client: client,
-		log:    logr.Discard(),
+		space:          spaceManager,
+		pipelineClient: client,
+		log:            logr.Discard(),
+		recorder:       record.NewFakeRecorder(10),
 	}
 
 	stage := emptyStageInit(t)
 
-	err := putKioskSpace.ServeRequest(stage)
+	err := putKioskSpace.ServeRequest(context.Background(), stage)
 	assert.NoError(t, err)
 
 	name = util.GenerateNamespaceName(stage)
 
-	space, err := spaceManager.Get(name)
+	space, err := spaceManager.Get(context.Background(), name)
 	assert.NoError(t, err)
 	assert.Equal(t, name, space.GetName())
 }
+
+func TestPutKioskSpace_ServeRequest_UpdatesMetadata(t *testing.T) {
+	stage := emptyStageInit(t)
+	stage.Spec.CdPipeline = "pipeline"
+	stage.Spec.NamespaceLabels = map[string]string{"istio-injection": "enabled"}
+
+	pipeline := &cdPipeApi.CDPipeline{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "pipeline",
+			Namespace: stage.Namespace,
+		},
+		Spec: cdPipeApi.CDPipelineSpec{
+			NamespaceAnnotations: map[string]string{"cost-center": "42"},
+		},
+	}
+
+	space := &unstructured.Unstructured{}
+	space.Object = map[string]interface{}{
+		"kind":       "Space",
+		"apiVersion": "tenancy.kiosk.sh/v1alpha1",
+		"metadata": map[string]interface{}{
+			"name": util.GenerateNamespaceName(stage),
+			"labels": map[string]interface{}{
+				util.TenantLabelName: stage.Namespace,
+			},
+		},
+	}
+
+	client := fake.NewClientBuilder().WithScheme(kioskSpaceScheme(t)).WithObjects(space, pipeline).Build()
+
+	spaceManager := kiosk.InitSpace(client)
+
+	putKioskSpace := PutKioskSpace{
+		space:          spaceManager,
+		pipelineClient: client,
+		log:            logr.Discard(),
+		recorder:       record.NewFakeRecorder(10),
+	}
+
+	err := putKioskSpace.ServeRequest(context.Background(), stage)
+	assert.NoError(t, err)
+
+	updated, err := spaceManager.Get(context.Background(), util.GenerateNamespaceName(stage))
+	assert.NoError(t, err)
+	assert.Equal(t, "enabled", updated.GetLabels()["istio-injection"])
+	assert.Equal(t, stage.Namespace, updated.GetLabels()[util.TenantLabelName])
+	assert.Equal(t, "42", updated.GetAnnotations()["cost-center"])
+}
@@ -9,6 +9,7 @@ import (
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
@@ -19,24 +20,40 @@ import (
 type PutNamespace struct {
 	next   handler.CdStageHandler
 	client client.Client
-	log    logr.Logger
+	// pipelineClient is used to get the stage CDPipeline from the operator cluster.
+	pipelineClient client.Client
+	log            logr.Logger
+	recorder       record.EventRecorder
 }
 
-func (h PutNamespace) ServeRequest(stage *cdPipeApi.Stage) error {
-	name := util.GenerateNamespaceName(stage)
+func (h PutNamespace) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	name := util.GetTargetNamespace(stage)
 	h.log.Info("try to put namespace", crNameLogKey, name)
 
-	if err := h.createNamespace(stage.Namespace, stage.Name); err != nil {
-		return fmt.Errorf("failed to create %s namespace: %w", name, err)
+	if err := h.createNamespace(ctx, name, stage); err != nil {
+		err = fmt.Errorf("failed to create %s namespace: %w", name, err)
+		setConditionFailed(stage, cdPipeApi.ConditionNamespaceReady, err)
+
+		return err
 	}
 
-	return nextServeOrNil(h.next, stage)
-}
+	if err := h.putMetadata(ctx, name, stage); err != nil {
+		err = fmt.Errorf("failed to set %s namespace labels and annotations: %w", name, err)
+		setConditionFailed(stage, cdPipeApi.ConditionNamespaceReady, err)
+
+		return err
+	}
 
-func (h PutNamespace) createNamespace(sourceNs, stageName string) error {
-	name := fmt.Sprintf("%v-%v", sourceNs, stageName)
+	setConditionSucceeded(stage, cdPipeApi.ConditionNamespaceReady, fmt.Sprintf("Namespace %s is ready", name))
+
+	return nextServeOrNil(ctx, h.next, stage)
+}
 
-	exists, err := h.namespaceExists(name)
+// createNamespace creates the stage target namespace.
+// Existing namespace is used as is, so stages can share the namespace created in advance,
+// but it is configured only if it is listed in the operator shared namespaces.
+func (h PutNamespace) createNamespace(ctx context.Context, name string, stage *cdPipeApi.Stage) error {
+	exists, err := h.namespaceExists(ctx, name)
 	if err != nil {
 		return err
 	}
@@ -46,13 +63,13 @@ func (h PutNamespace) createNamespace(sourceNs, stageName string) error {
 		return nil
 	}
 
-	return h.create(sourceNs, stageName)
+	return h.create(ctx, name, stage)
 }
 
-func (h PutNamespace) namespaceExists(name string) (bool, error) {
+func (h PutNamespace) namespaceExists(ctx context.Context, name string) (bool, error) {
 	h.log.Info("checking existence of namespace", crNameLogKey, name)
 
-	if err := h.client.Get(context.TODO(), types.NamespacedName{
+	if err := h.client.Get(ctx, types.NamespacedName{
 		Name: name,
 	}, &v1.Namespace{}); err != nil {
 		if k8sErrors.IsNotFound(err) {
@@ -65,9 +82,7 @@ func (h PutNamespace) namespaceExists(name string) (bool, error) {
 	return true, nil
 }
 
-func (h PutNamespace) create(sourceNs, stageName string) error {
-	name := fmt.Sprintf("%v-%v", sourceNs, stageName)
-
+func (h PutNamespace) create(ctx context.Context, name string, stage *cdPipeApi.Stage) error {
 	logger := h.log.WithValues(crNameLogKey, name)
 	logger.Info("creating namespace")
 
@@ -75,15 +90,55 @@ func (h PutNamespace) create(sourceNs, stageName string) error {
 		ObjectMeta: metaV1.ObjectMeta{
 			Name: name,
 			Labels: map[string]string{
-				util.TenantLabelName: sourceNs,
+				util.TenantLabelName: stage.Namespace,
+				util.StageLabelName:  stage.Name,
 			},
 		},
 	}
-	if err := h.client.Create(context.TODO(), ns); err != nil {
+	if err := h.client.Create(ctx, ns); err != nil {
 		return fmt.Errorf("failed to create namespace: %w", err)
 	}
 
 	logger.Info("namespace is created")
+	h.recorder.Eventf(stage, v1.EventTypeNormal, cdPipeApi.EventReasonNamespaceCreated, "Namespace %s has been created", name)
+
+	return nil
+}
+
+// putMetadata sets the configured labels and annotations on the namespace and removes the ones removed from the spec.
+// It is done for the existing namespaces as well, so the namespace is kept in sync with the stage.
+func (h PutNamespace) putMetadata(ctx context.Context, name string, stage *cdPipeApi.Stage) error {
+	metadata, err := getNamespaceMetadata(ctx, h.pipelineClient, stage)
+	if err != nil {
+		return err
+	}
+
+	if metadata.skip(stage) {
+		return nil
+	}
+
+	ns := &v1.Namespace{}
+	if err = h.client.Get(ctx, types.NamespacedName{Name: name}, ns); err != nil {
+		return fmt.Errorf("failed to get namespace: %w", err)
+	}
+
+	if err = checkNamespaceCanBeConfigured(ns, stage); err != nil {
+		return err
+	}
+
+	if !metadata.apply(ns, stage) {
+		metadata.record(stage)
+
+		return nil
+	}
+
+	if err = h.client.Update(ctx, ns); err != nil {
+		return fmt.Errorf("failed to update namespace: %w", err)
+	}
+
+	h.log.Info("namespace labels and annotations have been updated", crNameLogKey, name)
+
+	metadata.record(stage)
 
 	return nil
 }
@@ -7,12 +7,19 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/platform"
 )
 
 var (
@@ -20,10 +27,22 @@ var (
 	namespace = "stub_ns"
 )
 
+func newPipelineClient(t *testing.T, objects ...client.Object) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
 func TestPutNamespace_CreateNs(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
 	ch := PutNamespace{
-		client: fake.NewClientBuilder().Build(),
-		log:    logr.Discard(),
+		client:         fake.NewClientBuilder().Build(),
+		pipelineClient: newPipelineClient(t),
+		log:            logr.Discard(),
+		recorder:       recorder,
 	}
 	s := &cdPipeApi.Stage{
 		ObjectMeta: metaV1.ObjectMeta{
@@ -31,7 +50,7 @@ func TestPutNamespace_CreateNs(t *testing.T) {
 			Namespace: namespace,
 		},
 	}
-	err := ch.ServeRequest(s)
+	err := ch.ServeRequest(context.Background(), s)
 	assert.NoError(t, err)
 
 	ns := &v1.Namespace{}
@@ -40,6 +59,9 @@ func TestPutNamespace_CreateNs(t *testing.T) {
 		Name: n,
 	}, ns)
 	assert.NoError(t, err)
+	assert.True(t, meta.IsStatusConditionTrue(s.Status.Conditions, cdPipeApi.ConditionNamespaceReady))
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, cdPipeApi.EventReasonNamespaceCreated)
 }
 
 func TestPutNamespace_NSExists(t *testing.T) {
@@ -50,8 +72,10 @@ func TestPutNamespace_NSExists(t *testing.T) {
 	}
 
 	ch := PutNamespace{
-		client: fake.NewClientBuilder().WithRuntimeObjects(ns).Build(),
-		log:    logr.Discard(),
+		client:         fake.NewClientBuilder().WithRuntimeObjects(ns).Build(),
+		pipelineClient: newPipelineClient(t),
+		log:            logr.Discard(),
+		recorder:       record.NewFakeRecorder(10),
 	}
 	s := &cdPipeApi.Stage{
 		ObjectMeta: metaV1.ObjectMeta{
@@ -59,6 +83,185 @@ func TestPutNamespace_NSExists(t *testing.T) {
 			Namespace: namespace,
 		},
 	}
-	err := ch.ServeRequest(s)
+	err := ch.ServeRequest(context.Background(), s)
+	assert.NoError(t, err)
+}
+
+func TestPutNamespace_CustomNs(t *testing.T) {
+	ch := PutNamespace{
+		client:         fake.NewClientBuilder().Build(),
+		pipelineClient: newPipelineClient(t),
+		log:            logr.Discard(),
+		recorder:       record.NewFakeRecorder(10),
+	}
+	s := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: cdPipeApi.StageSpec{
+			Namespace: "team-dev",
+		},
+	}
+	err := ch.ServeRequest(context.Background(), s)
+	assert.NoError(t, err)
+
+	ns := &v1.Namespace{}
+	err = ch.client.Get(context.TODO(), types.NamespacedName{
+		Name: "team-dev",
+	}, ns)
 	assert.NoError(t, err)
+	assert.Equal(t, namespace, ns.Labels[util.TenantLabelName])
+	assert.Equal(t, name, ns.Labels[util.StageLabelName])
+}
+
+func TestPutNamespace_Metadata(t *testing.T) {
+	t.Setenv(platform.SharedNamespaceEnv, "team-dev")
+
+	ns := &v1.Namespace{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "team-dev",
+			Labels: map[string]string{
+				"owner":                              "team",
+				"pod-security.kubernetes.io/enforce": "privileged",
+			},
+		},
+	}
+
+	pipeline := &cdPipeApi.CDPipeline{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "pipeline",
+			Namespace: namespace,
+		},
+		Spec: cdPipeApi.CDPipelineSpec{
+			NamespaceLabels: map[string]string{
+				"pod-security.kubernetes.io/enforce": "baseline",
+				"istio-injection":                    "enabled",
+			},
+			NamespaceAnnotations: map[string]string{
+				"cost-center": "42",
+			},
+		},
+	}
+
+	ch := PutNamespace{
+		client:         fake.NewClientBuilder().WithRuntimeObjects(ns).Build(),
+		pipelineClient: newPipelineClient(t, pipeline),
+		log:            logr.Discard(),
+		recorder:       record.NewFakeRecorder(10),
+	}
+	s := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: cdPipeApi.StageSpec{
+			CdPipeline: "pipeline",
+			Namespace:  "team-dev",
+			NamespaceLabels: map[string]string{
+				"pod-security.kubernetes.io/enforce": "restricted",
+			},
+		},
+	}
+	require.NoError(t, ch.ServeRequest(context.Background(), s))
+
+	got := &v1.Namespace{}
+	require.NoError(t, ch.client.Get(context.TODO(), types.NamespacedName{
+		Name: "team-dev",
+	}, got))
+	assert.Equal(t, "restricted", got.Labels["pod-security.kubernetes.io/enforce"])
+	assert.Equal(t, "enabled", got.Labels["istio-injection"])
+	assert.Equal(t, "team", got.Labels["owner"])
+	assert.Equal(t, "42", got.Annotations["cost-center"])
+	assert.NotContains(t, got.Labels, util.StageLabelName)
+	assert.Equal(t, []string{"istio-injection", "pod-security.kubernetes.io/enforce"}, s.Status.NamespaceLabels)
+	assert.Equal(t, []string{"cost-center"}, s.Status.NamespaceAnnotations)
+}
+
+func TestPutNamespace_MetadataRemoval(t *testing.T) {
+	t.Setenv(platform.SharedNamespaceEnv, "team-dev")
+
+	ns := &v1.Namespace{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "team-dev",
+			Labels: map[string]string{
+				"owner":                              "team",
+				"istio-injection":                    "enabled",
+				"pod-security.kubernetes.io/enforce": "restricted",
+			},
+			Annotations: map[string]string{
+				"cost-center": "42",
+			},
+		},
+	}
+
+	ch := PutNamespace{
+		client:         fake.NewClientBuilder().WithRuntimeObjects(ns).Build(),
+		pipelineClient: newPipelineClient(t),
+		log:            logr.Discard(),
+		recorder:       record.NewFakeRecorder(10),
+	}
+	s := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: cdPipeApi.StageSpec{
+			CdPipeline: "pipeline",
+			Namespace:  "team-dev",
+			NamespaceLabels: map[string]string{
+				"istio-injection": "enabled",
+			},
+		},
+		Status: cdPipeApi.StageStatus{
+			NamespaceLabels:      []string{"istio-injection", "pod-security.kubernetes.io/enforce"},
+			NamespaceAnnotations: []string{"cost-center"},
+		},
+	}
+	require.NoError(t, ch.ServeRequest(context.Background(), s))
+
+	got := &v1.Namespace{}
+	require.NoError(t, ch.client.Get(context.TODO(), types.NamespacedName{
+		Name: "team-dev",
+	}, got))
+	assert.Equal(t, map[string]string{"owner": "team", "istio-injection": "enabled"}, got.Labels)
+	assert.NotContains(t, got.Annotations, "cost-center")
+	assert.Equal(t, []string{"istio-injection"}, s.Status.NamespaceLabels)
+	assert.Empty(t, s.Status.NamespaceAnnotations)
+}
+
+func TestPutNamespace_MetadataOfNotOwnedNamespace(t *testing.T) {
+	ns := &v1.Namespace{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "kube-system",
+		},
+	}
+
+	ch := PutNamespace{
+		client:         fake.NewClientBuilder().WithRuntimeObjects(ns).Build(),
+		pipelineClient: newPipelineClient(t),
+		log:            logr.Discard(),
+		recorder:       record.NewFakeRecorder(10),
+	}
+	s := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: cdPipeApi.StageSpec{
+			CdPipeline: "pipeline",
+			Namespace:  "kube-system",
+			NamespaceLabels: map[string]string{
+				"pod-security.kubernetes.io/enforce": "privileged",
+			},
+		},
+	}
+
+	err := ch.ServeRequest(context.Background(), s)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "namespace kube-system wasn't created for the stage")
+
+	got := &v1.Namespace{}
+	require.NoError(t, ch.client.Get(context.TODO(), types.NamespacedName{Name: "kube-system"}, got))
+	assert.NotContains(t, got.Labels, "pod-security.kubernetes.io/enforce")
 }
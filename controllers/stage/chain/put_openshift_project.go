@@ -6,8 +6,10 @@ import (
 
 	"github.com/go-logr/logr"
 	projectApi "github.com/openshift/api/project/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
@@ -19,12 +21,15 @@ import (
 type PutOpenshiftProject struct {
 	next   handler.CdStageHandler
 	client client.Client
-	log    logr.Logger
+	// pipelineClient is used to get the stage CDPipeline from the operator cluster.
+	pipelineClient client.Client
+	log            logr.Logger
+	recorder       record.EventRecorder
 }
 
 // ServeRequest creates a project for a stage.
-func (c PutOpenshiftProject) ServeRequest(stage *cdPipeApi.Stage) error {
-	projectName := util.GenerateNamespaceName(stage)
+func (c PutOpenshiftProject) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	projectName := util.GetTargetNamespace(stage)
 	logger := c.log.WithValues(crNameLogKey, projectName)
 
 	logger.Info("Try to create project")
@@ -34,21 +39,71 @@ func (c PutOpenshiftProject) ServeRequest(stage *cdPipeApi.Stage) error {
 			Name: projectName,
 			Labels: map[string]string{
 				util.TenantLabelName: stage.Namespace,
+				util.StageLabelName:  stage.Name,
 			},
 		},
 	}
 
-	if err := c.client.Create(context.TODO(), project); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			logger.Info("Project already exists")
+	if err := c.client.Create(ctx, project); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			err = fmt.Errorf("failed to create project: %w", err)
+			setConditionFailed(stage, cdPipeApi.ConditionNamespaceReady, err)
 
-			return nil
+			return err
 		}
 
-		return fmt.Errorf("failed to create project: %w", err)
+		logger.Info("Project already exists")
+	} else {
+		logger.Info("Project has been created")
+		c.recorder.Eventf(stage, corev1.EventTypeNormal, cdPipeApi.EventReasonNamespaceCreated, "Project %s has been created", projectName)
 	}
 
-	logger.Info("Project has been created")
+	if err := c.putMetadata(ctx, projectName, stage); err != nil {
+		err = fmt.Errorf("failed to set %s project labels and annotations: %w", projectName, err)
+		setConditionFailed(stage, cdPipeApi.ConditionNamespaceReady, err)
 
-	return nextServeOrNil(c.next, stage)
+		return err
+	}
+
+	setConditionSucceeded(stage, cdPipeApi.ConditionNamespaceReady, fmt.Sprintf("Project %s is ready", projectName))
+
+	return nextServeOrNil(ctx, c.next, stage)
+}
+
+// putMetadata sets the configured labels and annotations on the project and removes the ones removed from the spec.
+// It is done for the existing projects as well, so the project is kept in sync with the stage.
+func (c PutOpenshiftProject) putMetadata(ctx context.Context, name string, stage *cdPipeApi.Stage) error {
+	metadata, err := getNamespaceMetadata(ctx, c.pipelineClient, stage)
+	if err != nil {
+		return err
+	}
+
+	if metadata.skip(stage) {
+		return nil
+	}
+
+	project := &projectApi.Project{}
+	if err = c.client.Get(ctx, client.ObjectKey{Name: name}, project); err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+
+	if err = checkNamespaceCanBeConfigured(project, stage); err != nil {
+		return err
+	}
+
+	if !metadata.apply(project, stage) {
+		metadata.record(stage)
+
+		return nil
+	}
+
+	if err = c.client.Update(ctx, project); err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+
+	c.log.Info("Project labels and annotations have been updated", crNameLogKey, name)
+
+	metadata.record(stage)
+
+	return nil
 }
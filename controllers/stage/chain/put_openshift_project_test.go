@@ -10,6 +10,7 @@ import (
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -23,6 +24,7 @@ func TestPutOpenshiftProject_ServeRequest(t *testing.T) {
 	scheme := runtime.NewScheme()
 
 	require.NoError(t, projectApi.AddToScheme(scheme))
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
 
 	tests := []struct {
 		name       string
@@ -81,6 +83,107 @@ func TestPutOpenshiftProject_ServeRequest(t *testing.T) {
 				)
 			},
 		},
+		{
+			name: "project labels and annotations are updated",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Name:      "stage-1",
+					Namespace: "default",
+				},
+				Spec: cdPipeApi.StageSpec{
+					Namespace: "team-dev",
+					NamespaceLabels: map[string]string{
+						"istio-injection": "enabled",
+					},
+					NamespaceAnnotations: map[string]string{
+						"cost-center": "42",
+					},
+				},
+			},
+			objects: []client.Object{
+				&projectApi.ProjectRequest{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name: "team-dev",
+					},
+				},
+				&projectApi.Project{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name: "team-dev",
+						Labels: map[string]string{
+							util.TenantLabelName: "default",
+							util.StageLabelName:  "stage-1",
+						},
+					},
+				},
+			},
+			wantErr: require.NoError,
+			wantAssert: func(t *testing.T, c client.Client, s *cdPipeApi.Stage) {
+				project := &projectApi.Project{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "team-dev"}, project))
+				require.Equal(t, "enabled", project.Labels["istio-injection"])
+				require.Equal(t, "42", project.Annotations["cost-center"])
+			},
+		},
+		{
+			name: "labels and annotations are not set on the project of another stage",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Name:      "stage-1",
+					Namespace: "default",
+				},
+				Spec: cdPipeApi.StageSpec{
+					Namespace: "team-dev",
+					NamespaceLabels: map[string]string{
+						"istio-injection": "enabled",
+					},
+				},
+			},
+			objects: []client.Object{
+				&projectApi.ProjectRequest{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name: "team-dev",
+					},
+				},
+				&projectApi.Project{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name: "team-dev",
+						Labels: map[string]string{
+							util.TenantLabelName: "default",
+							util.StageLabelName:  "stage-2",
+						},
+					},
+				},
+			},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "wasn't created for the stage")
+			},
+			wantAssert: func(t *testing.T, c client.Client, s *cdPipeApi.Stage) {
+				project := &projectApi.Project{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "team-dev"}, project))
+				require.NotContains(t, project.Labels, "istio-injection")
+			},
+		},
+		{
+			name: "project doesn't exist when labels are configured",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Name:      "stage-1",
+					Namespace: "default",
+				},
+				Spec: cdPipeApi.StageSpec{
+					Namespace: "team-dev",
+					NamespaceLabels: map[string]string{
+						"istio-injection": "enabled",
+					},
+				},
+			},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "failed to get project")
+			},
+			wantAssert: func(t *testing.T, c client.Client, s *cdPipeApi.Stage) {},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -90,11 +193,13 @@ func TestPutOpenshiftProject_ServeRequest(t *testing.T) {
 			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
 
 			c := PutOpenshiftProject{
-				client: k8sClient,
-				log:    logr.Discard(),
+				client:         k8sClient,
+				pipelineClient: k8sClient,
+				log:            logr.Discard(),
+				recorder:       record.NewFakeRecorder(10),
 			}
 
-			err := c.ServeRequest(tt.stage)
+			err := c.ServeRequest(context.Background(), tt.stage)
 			tt.wantErr(t, err)
 			tt.wantAssert(t, k8sClient, tt.stage)
 		})
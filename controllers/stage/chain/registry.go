@@ -0,0 +1,187 @@
+package chain
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/handler"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/rbac"
+)
+
+// Names of the chain steps.
+const (
+	StepPutCodebaseImageStream      = "put-codebase-image-stream"
+	StepPutNamespace                = "put-namespace"
+	StepApplyNamespaceTemplate      = "apply-namespace-template"
+	StepConfigureJenkinsRbac        = "configure-jenkins-rbac"
+	StepConfigureRegistryViewerRbac = "configure-registry-viewer-rbac"
+	StepConfigureTenantAdminRbac    = "configure-tenant-admin-rbac"
+	StepPutJenkinsJob               = "put-jenkins-job"
+	StepDeleteEnvironmentLabel      = "delete-environment-label"
+	StepPutEnvironmentLabel         = "put-environment-label"
+	StepDeleteStageRbac             = "delete-stage-rbac"
+	StepDeleteNamespace             = "delete-namespace"
+	StepDeleteRegistryViewerRbac    = "delete-registry-viewer-rbac"
+)
+
+// StepDependencies contains dependencies that are shared by the chain steps.
+type StepDependencies struct {
+	// Client is a client of the operator cluster.
+	Client client.Client
+	// ClusterClient is a client of the cluster where the stage namespace is located.
+	// It is the same as Client for the stages in the operator cluster.
+	ClusterClient client.Client
+	// Rbac manages RBAC in the cluster where the stage namespace is located.
+	Rbac     rbac.Manager
+	Recorder record.EventRecorder
+}
+
+// StepFactory creates a chain step handler which passes the request to the next handler.
+type StepFactory func(deps StepDependencies, next handler.CdStageHandler) handler.CdStageHandler
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]StepFactory{}
+)
+
+// RegisterStep adds the step to the registry, so it can be used in the chains.
+// It replaces the step if it has already been registered.
+func RegisterStep(name string, factory StepFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[name] = factory
+}
+
+func getStep(name string) (StepFactory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	factory, ok := registry[name]
+
+	return factory, ok
+}
+
+// buildChain creates a chain of the steps in the given order.
+func buildChain(steps []string, deps StepDependencies) (handler.CdStageHandler, error) {
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("chain doesn't contain steps")
+	}
+
+	var next handler.CdStageHandler
+
+	for i := len(steps) - 1; i >= 0; i-- {
+		factory, ok := getStep(steps[i])
+		if !ok {
+			return nil, fmt.Errorf("chain step %s is not registered", steps[i])
+		}
+
+		next = factory(deps, next)
+	}
+
+	return next, nil
+}
+
+// nolint:funlen // it's a list of the steps without any complex logic.
+func init() {
+	RegisterStep(StepPutCodebaseImageStream, func(deps StepDependencies, next handler.CdStageHandler) handler.CdStageHandler {
+		return PutCodebaseImageStream{
+			next:   next,
+			client: deps.Client,
+			log:    ctrl.Log.WithName(putCodebaseImageStreamChain),
+		}
+	})
+	RegisterStep(StepPutNamespace, func(deps StepDependencies, next handler.CdStageHandler) handler.CdStageHandler {
+		return DelegateNamespaceCreation{
+			next:           next,
+			client:         deps.ClusterClient,
+			pipelineClient: deps.Client,
+			log:            ctrl.Log.WithName(logKeyPutNamespace),
+			recorder:       deps.Recorder,
+		}
+	})
+	RegisterStep(StepApplyNamespaceTemplate, func(deps StepDependencies, next handler.CdStageHandler) handler.CdStageHandler {
+		return ApplyNamespaceTemplate{
+			next:          next,
+			client:        deps.Client,
+			clusterClient: deps.ClusterClient,
+			log:           ctrl.Log.WithName(logKeyApplyNamespaceTemplate),
+		}
+	})
+	RegisterStep(StepConfigureJenkinsRbac, func(deps StepDependencies, next handler.CdStageHandler) handler.CdStageHandler {
+		return ConfigureJenkinsRbac{
+			next:          next,
+			client:        deps.Client,
+			clusterClient: deps.ClusterClient,
+			log:           ctrl.Log.WithName(logKeyJenkinsRbac),
+			rbac:          deps.Rbac,
+		}
+	})
+	RegisterStep(StepConfigureRegistryViewerRbac, func(deps StepDependencies, next handler.CdStageHandler) handler.CdStageHandler {
+		return ConfigureRegistryViewerRbac{
+			next:   next,
+			client: deps.ClusterClient,
+			log:    ctrl.Log.WithName(logKeyRegistryViewerRbac),
+			rbac:   deps.Rbac,
+		}
+	})
+	RegisterStep(StepConfigureTenantAdminRbac, func(deps StepDependencies, next handler.CdStageHandler) handler.CdStageHandler {
+		return ConfigureTenantAdminRbac{
+			next:          next,
+			client:        deps.Client,
+			clusterClient: deps.ClusterClient,
+			log:           ctrl.Log.WithName(logKeyTenantAdminRbac),
+			rbac:          deps.Rbac,
+			recorder:      deps.Recorder,
+		}
+	})
+	RegisterStep(StepPutJenkinsJob, func(deps StepDependencies, next handler.CdStageHandler) handler.CdStageHandler {
+		return PutJenkinsJob{
+			next:     next,
+			client:   deps.Client,
+			log:      ctrl.Log.WithName(logKeyPutJenkinsJob),
+			recorder: deps.Recorder,
+		}
+	})
+	RegisterStep(StepDeleteEnvironmentLabel, func(deps StepDependencies, next handler.CdStageHandler) handler.CdStageHandler {
+		return DeleteEnvironmentLabelFromCodebaseImageStreams{
+			next:   next,
+			client: deps.Client,
+			log:    ctrl.Log.WithName(deleteEnvironmentLabelFromCodebaseImageStream),
+		}
+	})
+	RegisterStep(StepPutEnvironmentLabel, func(deps StepDependencies, next handler.CdStageHandler) handler.CdStageHandler {
+		return PutEnvironmentLabelToCodebaseImageStreams{
+			next:     next,
+			client:   deps.Client,
+			log:      ctrl.Log.WithName(logKeyPutEnvironmentLabel),
+			recorder: deps.Recorder,
+		}
+	})
+	RegisterStep(StepDeleteStageRbac, func(deps StepDependencies, next handler.CdStageHandler) handler.CdStageHandler {
+		return DeleteStageRbac{
+			next: next,
+			log:  ctrl.Log.WithName(logKeyDeleteStageRbac),
+			rbac: deps.Rbac,
+		}
+	})
+	RegisterStep(StepDeleteNamespace, func(deps StepDependencies, next handler.CdStageHandler) handler.CdStageHandler {
+		return DelegateNamespaceDeletion{
+			next:     next,
+			client:   deps.ClusterClient,
+			log:      ctrl.Log.WithName(logKeyDeleteNamespace),
+			recorder: deps.Recorder,
+		}
+	})
+	RegisterStep(StepDeleteRegistryViewerRbac, func(deps StepDependencies, next handler.CdStageHandler) handler.CdStageHandler {
+		return DeleteRegistryViewerRbac{
+			next:   next,
+			client: deps.ClusterClient,
+			log:    ctrl.Log.WithName(logKeyDeleteRegistryViewerRbac),
+		}
+	})
+}
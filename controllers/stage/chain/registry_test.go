@@ -0,0 +1,52 @@
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/handler"
+)
+
+type registryTestHandler struct {
+	next  handler.CdStageHandler
+	name  string
+	calls *[]string
+}
+
+func (h registryTestHandler) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
+	*h.calls = append(*h.calls, h.name)
+
+	return nextServeOrNil(ctx, h.next, stage)
+}
+
+func TestBuildChain(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	for _, name := range []string{"registry-test-first", "registry-test-second"} {
+		name := name
+
+		RegisterStep(name, func(_ StepDependencies, next handler.CdStageHandler) handler.CdStageHandler {
+			return registryTestHandler{next: next, name: name, calls: &calls}
+		})
+	}
+
+	steps := []string{"registry-test-second", "registry-test-first"}
+
+	next, err := buildChain(steps, StepDependencies{})
+	require.NoError(t, err)
+
+	stage := &cdPipeApi.Stage{}
+
+	require.NoError(t, instrumentedChain{next: next, steps: steps}.ServeRequest(context.Background(), stage))
+	assert.Equal(t, steps, calls)
+	assert.Equal(t, steps, stage.Status.ChainSteps)
+
+	_, err = buildChain(nil, StepDependencies{})
+	require.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+
+	rbacApi "k8s.io/api/rbac/v1"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/rbac"
+)
+
+// applyRoleBindings creates or updates the role bindings in the namespace and deletes the role bindings
+// with the same labels which are not configured anymore.
+func applyRoleBindings(
+	ctx context.Context,
+	manager rbac.Manager,
+	roleBindings []cdPipeApi.RoleBindingTemplate,
+	namespace string,
+	labels map[string]string,
+) error {
+	for i := range roleBindings {
+		if err := configureRoleBinding(ctx, manager, &roleBindings[i], namespace, labels); err != nil {
+			return fmt.Errorf("failed to configure %s rolebinding: %w", roleBindings[i].Name, err)
+		}
+	}
+
+	return pruneRoleBindings(ctx, manager, roleBindings, namespace, labels)
+}
+
+// configureRoleBinding creates or updates the RoleBinding and the Role if the template contains rules.
+func configureRoleBinding(
+	ctx context.Context,
+	manager rbac.Manager,
+	template *cdPipeApi.RoleBindingTemplate,
+	namespace string,
+	labels map[string]string,
+) error {
+	roleRef := rbacApi.RoleRef{
+		APIGroup: rbacApi.GroupName,
+		Kind:     rbac.RoleKind,
+		Name:     template.Name,
+	}
+
+	if template.RoleRef != nil {
+		roleRef = *template.RoleRef
+		if roleRef.APIGroup == "" {
+			roleRef.APIGroup = rbacApi.GroupName
+		}
+	} else if err := manager.CreateOrUpdateRole(ctx, template.Name, namespace, template.Rules, labels); err != nil {
+		return fmt.Errorf("failed to configure %s role: %w", template.Name, err)
+	}
+
+	if err := manager.CreateOrUpdateRoleBinding(ctx, template.Name, namespace, template.Subjects, roleRef, labels); err != nil {
+		return fmt.Errorf("failed to configure role binding: %w", err)
+	}
+
+	return nil
+}
+
+// pruneRoleBindings deletes the RoleBindings and Roles with the labels which are not configured anymore.
+func pruneRoleBindings(
+	ctx context.Context,
+	manager rbac.Manager,
+	roleBindings []cdPipeApi.RoleBindingTemplate,
+	namespace string,
+	labels map[string]string,
+) error {
+	roleBindingNames := make([]string, 0, len(roleBindings))
+	roleNames := make([]string, 0, len(roleBindings))
+
+	for i := range roleBindings {
+		roleBindingNames = append(roleBindingNames, roleBindings[i].Name)
+
+		if roleBindings[i].RoleRef == nil {
+			roleNames = append(roleNames, roleBindings[i].Name)
+		}
+	}
+
+	if err := manager.PruneRoleBindings(ctx, namespace, labels, roleBindingNames); err != nil {
+		return fmt.Errorf("failed to prune role bindings: %w", err)
+	}
+
+	if err := manager.PruneRoles(ctx, namespace, labels, roleNames); err != nil {
+		return fmt.Errorf("failed to prune roles: %w", err)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"context"
 	"github.com/go-logr/logr"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
@@ -14,8 +15,8 @@ type Skip struct {
 }
 
 // ServeRequest does nothing.
-func (c Skip) ServeRequest(stage *cdPipeApi.Stage) error {
+func (c Skip) ServeRequest(ctx context.Context, stage *cdPipeApi.Stage) error {
 	c.log.Info("skip chain", "name", stage.Name)
 
-	return nextServeOrNil(c.next, stage)
+	return nextServeOrNil(ctx, c.next, stage)
 }
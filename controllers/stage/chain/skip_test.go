@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"context"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -39,7 +40,7 @@ func TestSkip_ServeRequest(t *testing.T) {
 				log: logr.Discard(),
 			}
 
-			tt.wantErr(t, c.ServeRequest(tt.stage))
+			tt.wantErr(t, c.ServeRequest(context.Background(), tt.stage))
 		})
 	}
 }
@@ -0,0 +1,50 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+
+	projectApi "github.com/openshift/api/project/v1"
+	corev1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/platform"
+)
+
+// checkTargetNamespace checks that the operator can configure the stage target namespace.
+// The namespace with the generated name and the shared namespaces are not read,
+// other namespaces should be created for the stage.
+func checkTargetNamespace(ctx context.Context, c client.Client, stage *cdPipeApi.Stage) error {
+	name := util.GetTargetNamespace(stage)
+
+	if name == util.GenerateNamespaceName(stage) || platform.IsSharedNamespace(name) {
+		return nil
+	}
+
+	var ns client.Object = &corev1.Namespace{}
+	if platform.IsOpenshift() {
+		ns = &projectApi.Project{}
+	}
+
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, ns); err != nil {
+		return fmt.Errorf("failed to get %s namespace: %w", name, err)
+	}
+
+	return checkNamespaceCanBeConfigured(ns, stage)
+}
+
+// checkNamespaceCanBeConfigured returns an error if the namespace isn't owned by the stage and isn't shared.
+func checkNamespaceCanBeConfigured(ns metaV1.Object, stage *cdPipeApi.Stage) error {
+	if util.CanConfigureNamespace(ns, stage) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"namespace %s wasn't created for the stage, it can be configured only if it is listed in %s",
+		ns.GetName(),
+		platform.SharedNamespaceEnv,
+	)
+}
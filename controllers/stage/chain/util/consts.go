@@ -1,3 +1,11 @@
 package util
 
 const TenantLabelName = "app.edp.epam.com/tenant"
+
+// StageLabelName is a label of the resources which were created by the operator for the stage,
+// e.g. the namespace or the verified CodebaseImageStreams.
+const StageLabelName = "app.edp.epam.com/stage"
+
+// RbacComponentLabelName is a label of the RBAC resources created by the operator for the stage.
+// It contains a name of the component which the RBAC resources are created for, e.g. tenant or jenkins.
+const RbacComponentLabelName = "app.edp.epam.com/rbac-component"
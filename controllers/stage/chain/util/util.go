@@ -5,18 +5,20 @@ import (
 	"errors"
 	"fmt"
 
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
 	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/helper"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/platform"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/cluster"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/consts"
 )
 
-func GetCdPipeline(k8sClient client.Client, stage *cdPipeApi.Stage) (*cdPipeApi.CDPipeline, error) {
+func GetCdPipeline(ctx context.Context, k8sClient client.Client, stage *cdPipeApi.Stage) (*cdPipeApi.CDPipeline, error) {
 	ownerPipe := helper.GetOwnerReference(consts.CDPipelineKind, stage.GetOwnerReferences())
 	if ownerPipe != nil {
-		pipeline, err := cluster.GetCdPipeline(k8sClient, ownerPipe.Name, stage.Namespace)
+		pipeline, err := cluster.GetCdPipeline(ctx, k8sClient, ownerPipe.Name, stage.Namespace)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get owner pipeline : %w", err)
 		}
@@ -24,7 +26,7 @@ func GetCdPipeline(k8sClient client.Client, stage *cdPipeApi.Stage) (*cdPipeApi.
 		return pipeline, nil
 	}
 
-	pipeline, err := cluster.GetCdPipeline(k8sClient, stage.Spec.CdPipeline, stage.Namespace)
+	pipeline, err := cluster.GetCdPipeline(ctx, k8sClient, stage.Spec.CdPipeline, stage.Namespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pipeline: %w", err)
 	}
@@ -33,8 +35,18 @@ func GetCdPipeline(k8sClient client.Client, stage *cdPipeApi.Stage) (*cdPipeApi.
 }
 
 func FindPreviousStageName(ctx context.Context, k8sClient client.Client, stage *cdPipeApi.Stage) (string, error) {
+	previous, err := FindPreviousStage(ctx, k8sClient, stage)
+	if err != nil {
+		return "", err
+	}
+
+	return previous.Spec.Name, nil
+}
+
+// FindPreviousStage returns the stage which precedes the given stage in the CDPipeline.
+func FindPreviousStage(ctx context.Context, k8sClient client.Client, stage *cdPipeApi.Stage) (*cdPipeApi.Stage, error) {
 	if stage.IsFirst() {
-		return "", errors.New("can't get previous stage from first stage")
+		return nil, errors.New("can't get previous stage from first stage")
 	}
 
 	stages := &cdPipeApi.StageList{}
@@ -44,21 +56,77 @@ func FindPreviousStageName(ctx context.Context, k8sClient client.Client, stage *
 		client.InNamespace(stage.Namespace),
 		client.MatchingLabels{cdPipeApi.StageCdPipelineLabelName: stage.Spec.CdPipeline},
 	); err != nil {
-		return "", fmt.Errorf("failed to list stage names: %w", err)
+		return nil, fmt.Errorf("failed to list stage names: %w", err)
 	}
 
-	//nolint
-	//refactor
-	for _, val := range stages.Items {
-		if val.Spec.CdPipeline == stage.Spec.CdPipeline && val.Spec.Order == (stage.Spec.Order-1) {
-			return val.Spec.Name, nil
+	for i := range stages.Items {
+		if stages.Items[i].Spec.CdPipeline == stage.Spec.CdPipeline && stages.Items[i].Spec.Order == (stage.Spec.Order-1) {
+			return &stages.Items[i], nil
 		}
 	}
 
-	return "", errors.New("previous stage not found")
+	return nil, errors.New("previous stage not found")
 }
 
 // GenerateNamespaceName generates namespace name based on stage name and namespace.
 func GenerateNamespaceName(stage *cdPipeApi.Stage) string {
 	return fmt.Sprintf("%s-%s", stage.Namespace, stage.Name)
 }
+
+// EnvironmentLabelName returns a label which is set on the CodebaseImageStreams deployed to the stage.
+func EnvironmentLabelName(pipeName, stageName string) string {
+	return fmt.Sprintf("%s/%s", pipeName, stageName)
+}
+
+// VerifiedImageStreamName returns a name of the CodebaseImageStream with the application tags verified in the stage.
+func VerifiedImageStreamName(pipeName, stageName, codebase string) string {
+	return fmt.Sprintf("%s-%s-%s-verified", pipeName, stageName, codebase)
+}
+
+// GetTargetNamespace returns the namespace where the stage applications are deployed.
+// The namespace is taken from spec.namespace, the generated name is used
+// only for the stages which don't have spec.namespace yet.
+func GetTargetNamespace(stage *cdPipeApi.Stage) string {
+	if stage.Spec.Namespace != "" {
+		return stage.Spec.Namespace
+	}
+
+	return GenerateNamespaceName(stage)
+}
+
+// IsNamespaceOwnedByStage checks that the namespace was created by the operator for the stage.
+// Namespaces created without StageLabelName are owned by the stage only if they have the generated name,
+// so existing namespaces shared between stages or teams are never deleted together with the stage.
+func IsNamespaceOwnedByStage(ns metaV1.Object, stage *cdPipeApi.Stage) bool {
+	labels := ns.GetLabels()
+
+	if owner, ok := labels[StageLabelName]; ok {
+		return owner == stage.Name && labels[TenantLabelName] == stage.Namespace
+	}
+
+	return ns.GetName() == GenerateNamespaceName(stage)
+}
+
+// CanConfigureNamespace checks that the operator can set the labels, apply the resources
+// and create the role bindings in the namespace for the stage.
+// It is allowed only for the namespaces owned by the stage and the shared namespaces configured in the operator,
+// so the stage can't be used to take over an arbitrary namespace.
+func CanConfigureNamespace(ns metaV1.Object, stage *cdPipeApi.Stage) bool {
+	return IsNamespaceOwnedByStage(ns, stage) || platform.IsSharedNamespace(ns.GetName())
+}
+
+// GetRbacLabels returns labels of the RBAC resources created by the operator for the stage component.
+func GetRbacLabels(stage *cdPipeApi.Stage, component string) map[string]string {
+	labels := GetStageRbacLabels(stage)
+	labels[RbacComponentLabelName] = component
+
+	return labels
+}
+
+// GetStageRbacLabels returns labels of all RBAC resources created by the operator for the stage.
+func GetStageRbacLabels(stage *cdPipeApi.Stage) map[string]string {
+	return map[string]string{
+		TenantLabelName: stage.Namespace,
+		StageLabelName:  stage.Name,
+	}
+}
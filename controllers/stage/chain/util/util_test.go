@@ -11,6 +11,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/platform"
 )
 
 const (
@@ -47,7 +48,7 @@ func TestGetCdPipeline_WithObjectReferences(t *testing.T) {
 
 	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stage, cdPipeline).Build()
 
-	resultCdPipeline, err := GetCdPipeline(client, stage)
+	resultCdPipeline, err := GetCdPipeline(context.Background(), client, stage)
 	assert.NoError(t, err)
 	assert.Equal(t, resultCdPipeline.Name, name)
 }
@@ -76,7 +77,7 @@ func TestGetCdPipeline_WithoutObjectReferences(t *testing.T) {
 
 	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stage, cdPipeline).Build()
 
-	resultCdPipeline, err := GetCdPipeline(client, stage)
+	resultCdPipeline, err := GetCdPipeline(context.Background(), client, stage)
 	assert.NoError(t, err)
 	assert.Equal(t, resultCdPipeline.Name, name)
 }
@@ -230,6 +231,12 @@ func TestFindPreviousStageName_PreviousStageNotFound(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestVerifiedImageStreamName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "pipe-dev-app-verified", VerifiedImageStreamName("pipe", "dev", "app"))
+}
+
 func TestGenerateNamespaceName(t *testing.T) {
 	t.Parallel()
 
@@ -255,3 +262,115 @@ func TestGenerateNamespaceName(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTargetNamespace(t *testing.T) {
+	t.Parallel()
+
+	stage := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "stage1",
+			Namespace: "default",
+		},
+	}
+
+	assert.Equal(t, "default-stage1", GetTargetNamespace(stage))
+
+	stage.Spec.Namespace = "team-dev"
+
+	assert.Equal(t, "team-dev", GetTargetNamespace(stage))
+}
+
+func TestIsNamespaceOwnedByStage(t *testing.T) {
+	t.Parallel()
+
+	stage := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "stage1",
+			Namespace: "default",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		nsName string
+		labels map[string]string
+		want   bool
+	}{
+		{
+			name:   "namespace is created for the stage",
+			nsName: "team-dev",
+			labels: map[string]string{TenantLabelName: "default", StageLabelName: "stage1"},
+			want:   true,
+		},
+		{
+			name:   "namespace is created for another stage",
+			nsName: "team-dev",
+			labels: map[string]string{TenantLabelName: "default", StageLabelName: "stage2"},
+			want:   false,
+		},
+		{
+			name:   "namespace is created for another tenant",
+			nsName: "team-dev",
+			labels: map[string]string{TenantLabelName: "other", StageLabelName: "stage1"},
+			want:   false,
+		},
+		{
+			name:   "namespace with generated name is created before stage label",
+			nsName: "default-stage1",
+			labels: map[string]string{TenantLabelName: "default"},
+			want:   true,
+		},
+		{
+			name:   "existing shared namespace",
+			nsName: "team-dev",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ns := &metaV1.ObjectMeta{Name: tt.nsName, Labels: tt.labels}
+
+			assert.Equal(t, tt.want, IsNamespaceOwnedByStage(ns, stage))
+		})
+	}
+}
+
+func TestCanConfigureNamespace(t *testing.T) {
+	stage := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "stage1",
+			Namespace: "default",
+		},
+	}
+
+	t.Setenv(platform.SharedNamespaceEnv, "shared")
+
+	assert.True(t, CanConfigureNamespace(&metaV1.ObjectMeta{Name: "default-stage1"}, stage))
+	assert.True(t, CanConfigureNamespace(&metaV1.ObjectMeta{Name: "shared"}, stage))
+	assert.False(t, CanConfigureNamespace(&metaV1.ObjectMeta{Name: "kube-system"}, stage))
+}
+
+func TestGetRbacLabels(t *testing.T) {
+	t.Parallel()
+
+	stage := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "dev",
+			Namespace: "edp",
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		TenantLabelName:        "edp",
+		StageLabelName:         "dev",
+		RbacComponentLabelName: "tenant",
+	}, GetRbacLabels(stage, "tenant"))
+	assert.Equal(t, map[string]string{
+		TenantLabelName: "edp",
+		StageLabelName:  "dev",
+	}, GetStageRbacLabels(stage))
+}
@@ -2,15 +2,21 @@ package stage
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/cluster"
+	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
 )
 
 const (
@@ -84,3 +90,173 @@ func (h *PipelineEventHandler) Delete(evt event.DeleteEvent, q workqueue.RateLim
 // Generic does nothing, skip event.
 func (h *PipelineEventHandler) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
 }
+
+// NewNamespaceTemplateMapFunc returns a function which maps NamespaceTemplate
+// to the stages that reference it, so the template changes are applied to the stage namespaces.
+func NewNamespaceTemplateMapFunc(c client.Client, log logr.Logger) func(obj client.Object) []reconcile.Request {
+	return func(obj client.Object) []reconcile.Request {
+		stages := &cdPipeApi.StageList{}
+		if err := c.List(context.Background(), stages, client.InNamespace(obj.GetNamespace())); err != nil {
+			log.Error(err, "unable to get stages for namespace template", "namespace template", obj.GetName())
+			return nil
+		}
+
+		var requests []reconcile.Request
+
+		for i := range stages.Items {
+			if stages.Items[i].Spec.NamespaceTemplate != obj.GetName() {
+				continue
+			}
+
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: stages.Items[i].Namespace,
+				Name:      stages.Items[i].Name,
+			}})
+		}
+
+		return requests
+	}
+}
+
+// imageStreamTagsChangedPredicate passes only the CodebaseImageStream updates which change the tags.
+func imageStreamTagsChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oo, ok := e.ObjectOld.(*codebaseApi.CodebaseImageStream)
+			if !ok {
+				return false
+			}
+
+			no, ok := e.ObjectNew.(*codebaseApi.CodebaseImageStream)
+			if !ok {
+				return false
+			}
+
+			return !reflect.DeepEqual(oo.Spec.Tags, no.Spec.Tags)
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// NewImageStreamMapFunc returns a function which maps CodebaseImageStream to the stages
+// that show its tags in the status, so the application versions of the stages are refreshed.
+// These are the stages of the pipelines which use the stream as an input or contain the stage that verifies the stream.
+func NewImageStreamMapFunc(c client.Client, log logr.Logger) func(obj client.Object) []reconcile.Request {
+	return func(obj client.Object) []reconcile.Request {
+		ctx := context.Background()
+
+		var pipelines []string
+
+		if stageName, ok := obj.GetLabels()[util.StageLabelName]; ok {
+			stage := &cdPipeApi.Stage{}
+			if err := c.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: stageName}, stage); err != nil {
+				log.Error(err, "unable to get stage for codebase image stream", "codebase image stream", obj.GetName())
+				return nil
+			}
+
+			pipelines = append(pipelines, stage.Spec.CdPipeline)
+		}
+
+		pipelineList := &cdPipeApi.CDPipelineList{}
+		if err := c.List(ctx, pipelineList, client.InNamespace(obj.GetNamespace())); err != nil {
+			log.Error(err, "unable to get cd pipelines for codebase image stream", "codebase image stream", obj.GetName())
+			return nil
+		}
+
+		for i := range pipelineList.Items {
+			for _, ids := range pipelineList.Items[i].Spec.InputDockerStreams {
+				if cluster.CodebaseImageStreamName(ids) == obj.GetName() && !slices.Contains(pipelines, pipelineList.Items[i].Name) {
+					pipelines = append(pipelines, pipelineList.Items[i].Name)
+				}
+			}
+		}
+
+		var requests []reconcile.Request
+
+		for _, pipeline := range pipelines {
+			stages := &cdPipeApi.StageList{}
+			if err := c.List(
+				ctx,
+				stages,
+				client.InNamespace(obj.GetNamespace()),
+				client.MatchingLabels{cdPipeApi.StageCdPipelineLabelName: pipeline},
+			); err != nil {
+				log.Error(err, "unable to get stages for cd pipeline", "cd pipeline", pipeline)
+				return nil
+			}
+
+			for i := range stages.Items {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+					Namespace: stages.Items[i].Namespace,
+					Name:      stages.Items[i].Name,
+				}})
+			}
+		}
+
+		return requests
+	}
+}
+
+// NewClusterMapFunc returns a function which maps Cluster to the stages deployed to it,
+// so the stages are reconciled as soon as the cluster becomes reachable.
+func NewClusterMapFunc(c client.Client, log logr.Logger) func(obj client.Object) []reconcile.Request {
+	return func(obj client.Object) []reconcile.Request {
+		stages := &cdPipeApi.StageList{}
+		if err := c.List(context.Background(), stages, client.InNamespace(obj.GetNamespace())); err != nil {
+			log.Error(err, "unable to get stages for cluster", "cluster", obj.GetName())
+			return nil
+		}
+
+		var requests []reconcile.Request
+
+		for i := range stages.Items {
+			if stages.Items[i].Spec.ClusterName != obj.GetName() {
+				continue
+			}
+
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: stages.Items[i].Namespace,
+				Name:      stages.Items[i].Name,
+			}})
+		}
+
+		return requests
+	}
+}
+
+// clusterConnectivityChangedPredicate passes only the Cluster updates which change the cluster connectivity.
+// The periodic probes of the cluster don't trigger the stages.
+func clusterConnectivityChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oo, ok := e.ObjectOld.(*cdPipeApi.Cluster)
+			if !ok {
+				return false
+			}
+
+			no, ok := e.ObjectNew.(*cdPipeApi.Cluster)
+			if !ok {
+				return false
+			}
+
+			return oo.IsHealthy() != no.IsHealthy()
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
@@ -15,6 +15,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
 )
 
 func TestPipelineEventHandler_Update(t *testing.T) {
@@ -208,3 +210,202 @@ func TestPipelineEventHandler_Generic(t *testing.T) {
 
 	assert.Equal(t, 0, q.Len())
 }
+
+func TestNewNamespaceTemplateMapFunc(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	newStage := func(name, namespace, template string) *cdPipeApi.Stage {
+		return &cdPipeApi.Stage{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: cdPipeApi.StageSpec{
+				NamespaceTemplate: template,
+			},
+		}
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newStage("dev", "default", "small"),
+		newStage("qa", "default", "large"),
+		newStage("prod", "default", ""),
+		newStage("dev", "other", "small"),
+	).Build()
+
+	mapFunc := NewNamespaceTemplateMapFunc(k8sClient, logr.Discard())
+
+	got := mapFunc(&cdPipeApi.NamespaceTemplate{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "small",
+			Namespace: "default",
+		},
+	})
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "dev", got[0].Name)
+	assert.Equal(t, "default", got[0].Namespace)
+}
+
+func TestNewImageStreamMapFunc(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	newStage := func(name, pipeline string) *cdPipeApi.Stage {
+		return &cdPipeApi.Stage{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{cdPipeApi.StageCdPipelineLabelName: pipeline},
+			},
+			Spec: cdPipeApi.StageSpec{
+				CdPipeline: pipeline,
+			},
+		}
+	}
+
+	newPipeline := func(name string, streams ...string) *cdPipeApi.CDPipeline {
+		return &cdPipeApi.CDPipeline{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: cdPipeApi.CDPipelineSpec{
+				InputDockerStreams: streams,
+			},
+		}
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newPipeline("pipe1", "app1-main"),
+		newPipeline("pipe2", "app1-main", "app2-main"),
+		newPipeline("pipe3", "app2-main"),
+		newStage("pipe1-dev", "pipe1"),
+		newStage("pipe1-qa", "pipe1"),
+		newStage("pipe2-dev", "pipe2"),
+		newStage("pipe3-dev", "pipe3"),
+	).Build()
+
+	mapFunc := NewImageStreamMapFunc(k8sClient, logr.Discard())
+
+	tests := []struct {
+		name   string
+		stream *codebaseApi.CodebaseImageStream
+		want   []string
+	}{
+		{
+			name: "input stream",
+			stream: &codebaseApi.CodebaseImageStream{
+				ObjectMeta: metaV1.ObjectMeta{Name: "app1-main", Namespace: "default"},
+			},
+			want: []string{"pipe1-dev", "pipe1-qa", "pipe2-dev"},
+		},
+		{
+			name: "verified stream",
+			stream: &codebaseApi.CodebaseImageStream{
+				ObjectMeta: metaV1.ObjectMeta{
+					Name:      "pipe3-dev-app2-verified",
+					Namespace: "default",
+					Labels:    map[string]string{util.StageLabelName: "pipe3-dev"},
+				},
+			},
+			want: []string{"pipe3-dev"},
+		},
+		{
+			name: "stream is not used",
+			stream: &codebaseApi.CodebaseImageStream{
+				ObjectMeta: metaV1.ObjectMeta{Name: "app3-main", Namespace: "default"},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, r := range mapFunc(tt.stream) {
+				got = append(got, r.Name)
+			}
+
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestImageStreamTagsChangedPredicate(t *testing.T) {
+	p := imageStreamTagsChangedPredicate()
+
+	old := &codebaseApi.CodebaseImageStream{
+		Spec: codebaseApi.CodebaseImageStreamSpec{
+			Tags: []codebaseApi.Tag{{Name: "1.0.0"}},
+		},
+	}
+
+	labeled := old.DeepCopy()
+	labeled.Labels = map[string]string{"pipe/dev": ""}
+
+	tagged := old.DeepCopy()
+	tagged.Spec.Tags = append(tagged.Spec.Tags, codebaseApi.Tag{Name: "1.0.1"})
+
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: labeled}))
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: tagged}))
+	assert.False(t, p.Create(event.CreateEvent{Object: old}))
+}
+
+func TestNewClusterMapFunc(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	newStage := func(name, namespace, clusterName string) *cdPipeApi.Stage {
+		return &cdPipeApi.Stage{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: cdPipeApi.StageSpec{
+				ClusterName: clusterName,
+			},
+		}
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newStage("dev", "default", "external"),
+		newStage("qa", "default", cdPipeApi.InCluster),
+		newStage("dev", "other", "external"),
+	).Build()
+
+	mapFunc := NewClusterMapFunc(k8sClient, logr.Discard())
+
+	got := mapFunc(&cdPipeApi.Cluster{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "external",
+			Namespace: "default",
+		},
+	})
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "dev", got[0].Name)
+	assert.Equal(t, "default", got[0].Namespace)
+}
+
+func TestClusterConnectivityChangedPredicate(t *testing.T) {
+	p := clusterConnectivityChangedPredicate()
+
+	old := &cdPipeApi.Cluster{
+		Status: cdPipeApi.ClusterStatus{
+			Connected: false,
+		},
+	}
+
+	probed := old.DeepCopy()
+	probed.Status.LastProbeTime = metaV1.Now()
+
+	connected := old.DeepCopy()
+	connected.Status.Connected = true
+
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: probed}))
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: connected}))
+	assert.False(t, p.Create(event.CreateEvent{Object: old}))
+}
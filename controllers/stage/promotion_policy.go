@@ -0,0 +1,256 @@
+package stage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/consts"
+	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
+	jenkinsApi "github.com/epam/edp-jenkins-operator/v2/pkg/apis/v2/v1"
+)
+
+// autoPromotionRetry is an interval of checking if the previous deployment of the application has been processed.
+const autoPromotionRetry = 30 * time.Second
+
+// tagCreatedLayouts are formats of the CodebaseImageStream tag creation time.
+var tagCreatedLayouts = []string{time.RFC3339, "2006-01-02T15:04:05"}
+
+// autoPromotionEnabled checks if the new versions should be promoted to the stage by its promotion policy.
+func autoPromotionEnabled(stage *cdPipeApi.Stage) bool {
+	return stage.HasAutoPromotion() &&
+		stage.Spec.TriggerType == consts.AutoDeployTriggerType &&
+		!stage.IsFirst()
+}
+
+// autoPromote promotes the latest tags of the previous stage verified CodebaseImageStreams to the stage.
+// A CDStageDeploy is created for every new tag, so the stage deploy pipeline is triggered.
+// The stage verified CodebaseImageStreams are not changed, the tags are added to them by the stage deploy pipeline
+// after the stage quality gates have passed. The promoted tags are recorded in the stage status.
+// It returns the interval after which the tags that are delayed or wait for the previous deployment should be checked again,
+// or zero if there are no such tags.
+func (r *ReconcileStageVersions) autoPromote(ctx context.Context, stage *cdPipeApi.Stage) (time.Duration, error) {
+	if !autoPromotionEnabled(stage) {
+		return 0, nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+	policy := stage.Spec.PromotionPolicy
+
+	pipe, err := util.GetCdPipeline(ctx, r.client, stage)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s cd pipeline: %w", stage.Spec.CdPipeline, err)
+	}
+
+	previous, err := util.FindPreviousStage(ctx, r.client, stage)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get previous stage: %w", err)
+	}
+
+	var (
+		requeue  time.Duration
+		promoted []cdPipeApi.PromotedApplication
+	)
+
+	for _, app := range pipe.Spec.ApplicationsToPromote {
+		stream, err := getImageStream(ctx, r.client, util.VerifiedImageStreamName(pipe.Name, previous.Spec.Name, app), stage.Namespace)
+		if err != nil {
+			return 0, err
+		}
+
+		tag, ok := latestImageTag(stream)
+		if !ok || getAutoPromotedTag(stage, app) == tag.Name {
+			continue
+		}
+
+		if policy.RequireQualityGates {
+			passed, err := r.passedQualityGates(ctx, pipe.Name, previous, app, tag.Name)
+			if err != nil {
+				return 0, err
+			}
+
+			if !passed {
+				log.Info("Tag hasn't passed the previous stage quality gates", "application", app, "tag", tag.Name)
+
+				continue
+			}
+		}
+
+		if wait := promotionDelay(policy, tag); wait > 0 {
+			log.Info("Promotion is delayed", "application", app, "tag", tag.Name, "delay", wait)
+
+			requeue = minRequeue(requeue, wait)
+
+			continue
+		}
+
+		created, err := r.createStageDeploy(ctx, pipe.Name, stage, app, tag.Name)
+		if err != nil {
+			return 0, err
+		}
+
+		if !created {
+			log.Info("Waiting for the previous deployment of the application", "application", app, "tag", tag.Name)
+
+			requeue = minRequeue(requeue, autoPromotionRetry)
+
+			continue
+		}
+
+		promoted = append(promoted, cdPipeApi.PromotedApplication{Name: app, Tag: tag.Name})
+	}
+
+	if len(promoted) == 0 {
+		return requeue, nil
+	}
+
+	setAutoPromotedTags(stage, promoted)
+
+	tags := make([]string, 0, len(promoted))
+	for _, p := range promoted {
+		tags = append(tags, fmt.Sprintf("%s:%s", p.Name, p.Tag))
+	}
+
+	log.Info("Deployment of the new versions has been requested", "tags", tags)
+	r.recorder.Eventf(stage, corev1.EventTypeNormal, cdPipeApi.EventReasonAutoPromoted,
+		"Versions %s have been promoted from %s stage, the deployment has been requested", strings.Join(tags, ", "), previous.Spec.Name)
+
+	return requeue, nil
+}
+
+// createStageDeploy creates a CDStageDeploy of the tag to the stage, which triggers the stage deploy pipeline.
+// The name is the same as the one used by the codebase-operator for the stage deployments of the application,
+// so only one deployment of the application is requested at a time.
+// It returns false if the previous deployment of the application hasn't been processed yet.
+func (r *ReconcileStageVersions) createStageDeploy(ctx context.Context, pipeline string, stage *cdPipeApi.Stage, app, tag string) (bool, error) {
+	name := fmt.Sprintf("%s-%s-%s", pipeline, stage.Spec.Name, app)
+	deployTag := jenkinsApi.Tag{Codebase: app, Tag: tag}
+
+	stageDeploy := &codebaseApi.CDStageDeploy{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      name,
+			Namespace: stage.Namespace,
+		},
+		Spec: codebaseApi.CDStageDeploySpec{
+			Pipeline: pipeline,
+			Stage:    stage.Spec.Name,
+			Tag:      deployTag,
+			Tags:     []jenkinsApi.Tag{deployTag},
+		},
+	}
+
+	if err := r.client.Create(ctx, stageDeploy); err != nil {
+		if k8sErrors.IsAlreadyExists(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to create %s cd stage deploy: %w", name, err)
+	}
+
+	return true, nil
+}
+
+// passedQualityGates checks if the tag has been added to the previous stage verified stream by the stage deploy pipeline,
+// which adds the tags after the stage quality gates have passed.
+// Tags added to the stream by the Promotions to the previous stage haven't passed its quality gates.
+func (r *ReconcileStageVersions) passedQualityGates(
+	ctx context.Context,
+	pipeline string,
+	previous *cdPipeApi.Stage,
+	app, tag string,
+) (bool, error) {
+	promotions := &cdPipeApi.PromotionList{}
+	if err := r.client.List(ctx, promotions, client.InNamespace(previous.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list promotions: %w", err)
+	}
+
+	for i := range promotions.Items {
+		p := &promotions.Items[i]
+
+		if p.Spec.Pipeline != pipeline || p.Spec.TargetStage != previous.Spec.Name || !p.IsCompleted() {
+			continue
+		}
+
+		for _, promotedApp := range p.Spec.Applications {
+			if promotedApp.Name == app && promotedApp.Tag == tag {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// promotionDelay returns the time left before the tag can be promoted.
+// Tags with the unknown creation time are not delayed.
+func promotionDelay(policy *cdPipeApi.PromotionPolicy, tag codebaseApi.Tag) time.Duration {
+	if policy.Delay == nil || policy.Delay.Duration <= 0 {
+		return 0
+	}
+
+	for _, layout := range tagCreatedLayouts {
+		created, err := time.Parse(layout, tag.Created)
+		if err != nil {
+			continue
+		}
+
+		return time.Until(created.Add(policy.Delay.Duration))
+	}
+
+	return 0
+}
+
+func getAutoPromotedTag(stage *cdPipeApi.Stage, app string) string {
+	if stage.Status.AutoPromotion == nil {
+		return ""
+	}
+
+	for _, p := range stage.Status.AutoPromotion.Applications {
+		if p.Name == app {
+			return p.Tag
+		}
+	}
+
+	return ""
+}
+
+// setAutoPromotedTags records the promoted tags in the stage status, replacing the previous tags of the applications.
+func setAutoPromotedTags(stage *cdPipeApi.Stage, promoted []cdPipeApi.PromotedApplication) {
+	if stage.Status.AutoPromotion == nil {
+		stage.Status.AutoPromotion = &cdPipeApi.AutoPromotionStatus{}
+	}
+
+	for _, p := range promoted {
+		found := false
+
+		for i := range stage.Status.AutoPromotion.Applications {
+			if stage.Status.AutoPromotion.Applications[i].Name == p.Name {
+				stage.Status.AutoPromotion.Applications[i].Tag = p.Tag
+				found = true
+			}
+		}
+
+		if !found {
+			stage.Status.AutoPromotion.Applications = append(stage.Status.AutoPromotion.Applications, p)
+		}
+	}
+
+	stage.Status.AutoPromotion.LastPromotionTime = metaV1.Now()
+}
+
+func minRequeue(current, next time.Duration) time.Duration {
+	if current == 0 || next < current {
+		return next
+	}
+
+	return current
+}
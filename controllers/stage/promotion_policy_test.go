@@ -0,0 +1,250 @@
+package stage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/consts"
+	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
+	jenkinsApi "github.com/epam/edp-jenkins-operator/v2/pkg/apis/v2/v1"
+)
+
+func TestReconcileStageVersions_autoPromote(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+	require.NoError(t, codebaseApi.AddToScheme(scheme))
+
+	pipeline := &cdPipeApi.CDPipeline{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "pipe",
+			Namespace: "default",
+		},
+		Spec: cdPipeApi.CDPipelineSpec{
+			InputDockerStreams:    []string{"app1-main"},
+			ApplicationsToPromote: []string{"app1"},
+		},
+	}
+
+	previous := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "pipe-dev",
+			Namespace: "default",
+			Labels:    map[string]string{cdPipeApi.StageCdPipelineLabelName: "pipe"},
+		},
+		Spec: cdPipeApi.StageSpec{
+			Name:       "dev",
+			CdPipeline: "pipe",
+			Order:      0,
+		},
+	}
+
+	newStage := func(triggerType string, policy *cdPipeApi.PromotionPolicy, promotedTag string) *cdPipeApi.Stage {
+		stage := &cdPipeApi.Stage{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      "pipe-qa",
+				Namespace: "default",
+				Labels:    map[string]string{cdPipeApi.StageCdPipelineLabelName: "pipe"},
+			},
+			Spec: cdPipeApi.StageSpec{
+				Name:            "qa",
+				CdPipeline:      "pipe",
+				Order:           1,
+				TriggerType:     triggerType,
+				PromotionPolicy: policy,
+			},
+		}
+
+		if promotedTag != "" {
+			stage.Status.AutoPromotion = &cdPipeApi.AutoPromotionStatus{
+				Applications: []cdPipeApi.PromotedApplication{{Name: "app1", Tag: promotedTag}},
+			}
+		}
+
+		return stage
+	}
+
+	newStream := func(created string) *codebaseApi.CodebaseImageStream {
+		return &codebaseApi.CodebaseImageStream{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      "pipe-dev-app1-verified",
+				Namespace: "default",
+			},
+			Spec: codebaseApi.CodebaseImageStreamSpec{
+				Codebase: "app1",
+				Tags: []codebaseApi.Tag{
+					{Name: "1.0.0", Created: "2023-01-01T00:00:00Z"},
+					{Name: "1.0.1", Created: created},
+				},
+			},
+		}
+	}
+
+	verifiedLongAgo := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	verifiedJustNow := time.Now().UTC().Format(time.RFC3339)
+
+	// Promotion of the tag to the previous stage, so the tag hasn't passed the previous stage quality gates.
+	promotionToPrevious := &cdPipeApi.Promotion{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "dev-promotion",
+			Namespace: "default",
+		},
+		Spec: cdPipeApi.PromotionSpec{
+			Pipeline:     "pipe",
+			TargetStage:  "dev",
+			Applications: []cdPipeApi.PromotedApplication{{Name: "app1", Tag: "1.0.1"}},
+		},
+		Status: cdPipeApi.PromotionStatus{
+			Phase: cdPipeApi.PromotionPhaseSucceeded,
+		},
+	}
+
+	// Deployment of the previous tag which hasn't been processed yet.
+	pendingDeploy := &codebaseApi.CDStageDeploy{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "pipe-qa-app1",
+			Namespace: "default",
+		},
+		Spec: codebaseApi.CDStageDeploySpec{
+			Pipeline: "pipe",
+			Stage:    "qa",
+			Tag:      jenkinsApi.Tag{Codebase: "app1", Tag: "1.0.0"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		stage        *cdPipeApi.Stage
+		stream       *codebaseApi.CodebaseImageStream
+		objects      []client.Object
+		wantPromoted string
+		wantDeployed string
+		wantRequeue  bool
+	}{
+		{
+			name:         "new tag is promoted",
+			stage:        newStage(consts.AutoDeployTriggerType, &cdPipeApi.PromotionPolicy{Auto: true}, "1.0.0"),
+			stream:       newStream(verifiedLongAgo),
+			wantPromoted: "1.0.1",
+			wantDeployed: "1.0.1",
+		},
+		{
+			name: "new tag is promoted after quality gates have passed",
+			stage: newStage(consts.AutoDeployTriggerType, &cdPipeApi.PromotionPolicy{
+				Auto:                true,
+				RequireQualityGates: true,
+				Delay:               &metaV1.Duration{Duration: 10 * time.Minute},
+			}, ""),
+			stream:       newStream(verifiedLongAgo),
+			wantPromoted: "1.0.1",
+			wantDeployed: "1.0.1",
+		},
+		{
+			name: "tag promoted to the previous stage hasn't passed quality gates",
+			stage: newStage(consts.AutoDeployTriggerType, &cdPipeApi.PromotionPolicy{
+				Auto:                true,
+				RequireQualityGates: true,
+			}, "1.0.0"),
+			stream:       newStream(verifiedLongAgo),
+			objects:      []client.Object{promotionToPrevious},
+			wantPromoted: "1.0.0",
+		},
+		{
+			name:         "tag promoted to the previous stage is promoted without quality gates",
+			stage:        newStage(consts.AutoDeployTriggerType, &cdPipeApi.PromotionPolicy{Auto: true}, "1.0.0"),
+			stream:       newStream(verifiedLongAgo),
+			objects:      []client.Object{promotionToPrevious},
+			wantPromoted: "1.0.1",
+			wantDeployed: "1.0.1",
+		},
+		{
+			name: "new tag is delayed",
+			stage: newStage(consts.AutoDeployTriggerType, &cdPipeApi.PromotionPolicy{
+				Auto:  true,
+				Delay: &metaV1.Duration{Duration: 10 * time.Minute},
+			}, "1.0.0"),
+			stream:       newStream(verifiedJustNow),
+			wantPromoted: "1.0.0",
+			wantRequeue:  true,
+		},
+		{
+			name:         "new tag waits for the previous deployment",
+			stage:        newStage(consts.AutoDeployTriggerType, &cdPipeApi.PromotionPolicy{Auto: true}, "1.0.0"),
+			stream:       newStream(verifiedLongAgo),
+			objects:      []client.Object{pendingDeploy},
+			wantPromoted: "1.0.0",
+			wantDeployed: "1.0.0",
+			wantRequeue:  true,
+		},
+		{
+			name:         "tag has been already promoted",
+			stage:        newStage(consts.AutoDeployTriggerType, &cdPipeApi.PromotionPolicy{Auto: true}, "1.0.1"),
+			stream:       newStream(verifiedLongAgo),
+			wantPromoted: "1.0.1",
+		},
+		{
+			name: "stage which requires approvals is skipped",
+			stage: func() *cdPipeApi.Stage {
+				s := newStage(consts.AutoDeployTriggerType, &cdPipeApi.PromotionPolicy{Auto: true}, "")
+				s.Spec.Approval = &cdPipeApi.ApprovalPolicy{Users: []string{"john"}}
+
+				return s
+			}(),
+			stream: newStream(verifiedLongAgo),
+		},
+		{
+			name:   "manual stage is skipped",
+			stage:  newStage("Manual", &cdPipeApi.PromotionPolicy{Auto: true}, ""),
+			stream: newStream(verifiedLongAgo),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				append(tt.objects, pipeline.DeepCopy(), tt.stage, previous.DeepCopy(), tt.stream)...,
+			).Build()
+
+			r := NewReconcileStageVersions(k8sClient, logr.Discard(), record.NewFakeRecorder(10))
+
+			requeue, err := r.autoPromote(context.Background(), tt.stage)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantRequeue, requeue > 0)
+			assert.Equal(t, tt.wantPromoted, getAutoPromotedTag(tt.stage, "app1"))
+
+			stageDeploys := &codebaseApi.CDStageDeployList{}
+			require.NoError(t, k8sClient.List(context.Background(), stageDeploys))
+
+			if tt.wantDeployed == "" {
+				assert.Empty(t, stageDeploys.Items)
+			} else {
+				require.Len(t, stageDeploys.Items, 1)
+				assert.Equal(t, "pipe-qa-app1", stageDeploys.Items[0].Name)
+				assert.Equal(t, "qa", stageDeploys.Items[0].Spec.Stage)
+				assert.Equal(t, jenkinsApi.Tag{Codebase: "app1", Tag: tt.wantDeployed}, stageDeploys.Items[0].Spec.Tag)
+			}
+
+			// The stage verified stream is changed only by the stage deploy pipeline.
+			streams := &codebaseApi.CodebaseImageStreamList{}
+			require.NoError(t, k8sClient.List(context.Background(), streams))
+			require.Len(t, streams.Items, 1)
+			assert.Empty(t, streams.Items[0].Labels)
+		})
+	}
+}
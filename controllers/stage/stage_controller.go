@@ -8,21 +8,27 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
 	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/dryrun"
 	edpError "github.com/epam/edp-cd-pipeline-operator/v2/pkg/error"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/metrics"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/objectmodifier"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/consts"
 )
@@ -31,6 +37,8 @@ const (
 	envLabelDeletionFinalizer   = "envLabelDeletion"
 	const15Requeue              = 15 * time.Second
 	waitForParentStagesDeletion = time.Second
+	// namespaceTemplateResync is an interval of the NamespaceTemplate drift correction.
+	namespaceTemplateResync = 10 * time.Minute
 )
 
 func NewReconcileStage(
@@ -38,12 +46,14 @@ func NewReconcileStage(
 	scheme *runtime.Scheme,
 	log logr.Logger,
 	stageModifier objectmodifier.StageModifier,
+	recorder record.EventRecorder,
 ) *ReconcileStage {
 	return &ReconcileStage{
 		client:        c,
 		scheme:        scheme,
 		log:           log.WithName("cd-stage"),
 		stageModifier: stageModifier,
+		recorder:      recorder,
 	}
 }
 
@@ -52,6 +62,7 @@ type ReconcileStage struct {
 	scheme        *runtime.Scheme
 	log           logr.Logger
 	stageModifier objectmodifier.StageModifier
+	recorder      record.EventRecorder
 }
 
 func (r *ReconcileStage) SetupWithManager(mgr ctrl.Manager) error {
@@ -74,12 +85,24 @@ func (r *ReconcileStage) SetupWithManager(mgr ctrl.Manager) error {
 			if no.Status.ShouldBeHandled {
 				return true
 			}
+			if oo.IsDryRun() != no.IsDryRun() {
+				return true
+			}
 			return false
 		},
 	}
 	if err := ctrl.NewControllerManagedBy(mgr).
 		For(&cdPipeApi.Stage{}, builder.WithPredicates(p)).
 		Watches(&source.Kind{Type: &cdPipeApi.CDPipeline{}}, NewPipelineEventHandler(r.client, r.log)).
+		Watches(
+			&source.Kind{Type: &cdPipeApi.NamespaceTemplate{}},
+			handler.EnqueueRequestsFromMapFunc(NewNamespaceTemplateMapFunc(r.client, r.log)),
+		).
+		Watches(
+			&source.Kind{Type: &cdPipeApi.Cluster{}},
+			handler.EnqueueRequestsFromMapFunc(NewClusterMapFunc(r.client, r.log)),
+			builder.WithPredicates(clusterConnectivityChangedPredicate()),
+		).
 		Complete(r); err != nil {
 		return fmt.Errorf("failed to create controller manager: %w", err)
 	}
@@ -90,6 +113,11 @@ func (r *ReconcileStage) SetupWithManager(mgr ctrl.Manager) error {
 //+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=stages,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=stages/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=stages/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",namespace=placeholder,resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=namespacetemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=clusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=codebaseimagestreams,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups="",namespace=placeholder,resources=events,verbs=create;patch
 
 func (r *ReconcileStage) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -106,6 +134,8 @@ func (r *ReconcileStage) Reconcile(ctx context.Context, request reconcile.Reques
 		return reconcile.Result{}, fmt.Errorf("failed to get namespace: %w", err)
 	}
 
+	// Stage defaults are set by the mutating webhook,
+	// the modifier is kept as a fallback for the case when webhooks are disabled.
 	patched, err := r.stageModifier.Apply(ctx, stage)
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("failed to apply stage changes: %w", err)
@@ -124,10 +154,38 @@ func (r *ReconcileStage) Reconcile(ctx context.Context, request reconcile.Reques
 		return *result, nil
 	}
 
-	if err = chain.CreateChain(ctx, r.client, stage).ServeRequest(stage); err != nil {
+	if err = r.checkCluster(ctx, stage); err != nil {
+		log.Error(err, "Target cluster is not ready. Reconcile again...")
+
+		if statusErr := r.setFailedStatus(ctx, stage, err); statusErr != nil {
+			return reconcile.Result{}, statusErr
+		}
+
+		return reconcile.Result{RequeueAfter: const15Requeue}, nil
+	}
+
+	if stage.IsDryRun() {
+		return r.reconcileDryRun(ctx, stage)
+	}
+
+	meta.RemoveStatusCondition(&stage.Status.Conditions, cdPipeApi.ConditionPlanned)
+
+	ch, err := chain.CreateChain(ctx, r.client, r.recorder, stage)
+	if err != nil {
+		if statusErr := r.setFailedStatus(ctx, stage, err); statusErr != nil {
+			return reconcile.Result{}, statusErr
+		}
+
+		return reconcile.Result{RequeueAfter: const15Requeue}, fmt.Errorf("failed to create the chain: %w", err)
+	}
+
+	if err = ch.ServeRequest(ctx, stage); err != nil {
 		var e edpError.CISNotFoundError
 		if errors.As(err, &e) {
 			log.Error(err, "cis wasn't found. reconcile again...")
+			metrics.CISNotFoundRequeues.Inc()
+			r.recorder.Event(stage, corev1.EventTypeWarning, cdPipeApi.EventReasonImageStreamNotFound, err.Error())
+
 			return reconcile.Result{RequeueAfter: const15Requeue}, nil
 		}
 
@@ -138,12 +196,67 @@ func (r *ReconcileStage) Reconcile(ctx context.Context, request reconcile.Reques
 		return reconcile.Result{RequeueAfter: const15Requeue}, fmt.Errorf("failed to handle the chain: %w", err)
 	}
 
+	firstReady := stage.Status.ReadyTime == nil
+	if firstReady {
+		now := metaV1.Now()
+		stage.Status.ReadyTime = &now
+	}
+
 	if err := r.setFinishStatus(ctx, stage); err != nil {
 		return reconcile.Result{}, err
 	}
 
+	if firstReady {
+		metrics.StageTimeToReady.Observe(stage.Status.ReadyTime.Sub(stage.CreationTimestamp.Time).Seconds())
+	}
+
 	log.Info("Reconciling Stage has been finished")
 
+	if stage.Spec.NamespaceTemplate != "" {
+		return reconcile.Result{RequeueAfter: namespaceTemplateResync}, nil
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// reconcileDryRun serves the chain without applying the changes and records them in the stage status plan.
+// The stage status is kept as it is, except the plan and the ConditionPlanned, because nothing has been changed.
+// E.g. the applied NamespaceTemplate resources are kept, so they are pruned by the next real run.
+func (r *ReconcileStage) reconcileDryRun(ctx context.Context, stage *cdPipeApi.Stage) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	log.Info("Planning Stage changes in the dry-run mode")
+
+	status := stage.Status.DeepCopy()
+
+	plan := dryrun.NewPlan()
+
+	ch, err := chain.CreateDryRunChain(ctx, r.client, stage, plan)
+	if err == nil {
+		err = ch.ServeRequest(ctx, stage)
+	}
+
+	stage.Status = *status
+	stage.Status.Plan = plan.Changes()
+
+	if err != nil {
+		stage.SetCondition(cdPipeApi.ConditionPlanned, metaV1.ConditionFalse, cdPipeApi.ReasonFailed, err.Error())
+		r.recorder.Event(stage, corev1.EventTypeWarning, cdPipeApi.EventReasonReconcileFailed, err.Error())
+	} else {
+		msg := fmt.Sprintf("%d changes have been planned", len(stage.Status.Plan))
+		stage.SetCondition(cdPipeApi.ConditionPlanned, metaV1.ConditionTrue, cdPipeApi.ReasonSucceeded, msg)
+		r.recorder.Event(stage, corev1.EventTypeNormal, cdPipeApi.EventReasonDryRunPlanned, msg)
+	}
+
+	if statusErr := r.client.Status().Update(ctx, stage); statusErr != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to update stage status: %w", statusErr)
+	}
+
+	if err != nil {
+		return reconcile.Result{RequeueAfter: const15Requeue}, fmt.Errorf("failed to plan the chain: %w", err)
+	}
+
+	log.Info("Stage changes have been planned", "changes", len(stage.Status.Plan))
+
 	return reconcile.Result{}, nil
 }
 
@@ -174,13 +287,20 @@ func (r *ReconcileStage) tryToDeleteCDStage(ctx context.Context, stage *cdPipeAp
 	// because in the chain we get previous stage
 	if !isLastStage {
 		log.Info("Stage is not last. Postpone deletion")
+		r.recorder.Event(stage, corev1.EventTypeNormal, cdPipeApi.EventReasonDeletionPostponed,
+			"Deletion is postponed until the next stages of the pipeline are deleted")
 
 		return &reconcile.Result{RequeueAfter: waitForParentStagesDeletion}, nil
 	}
 
 	log.Info("Stage is last. Delete chain")
 
-	if err := chain.CreateDeleteChain(ctx, r.client, stage).ServeRequest(stage); err != nil {
+	ch, err := chain.CreateDeleteChain(ctx, r.client, r.recorder, stage)
+	if err != nil {
+		return &reconcile.Result{}, fmt.Errorf("failed to create delete chain: %w", err)
+	}
+
+	if err := ch.ServeRequest(ctx, stage); err != nil {
 		return &reconcile.Result{}, fmt.Errorf("failed to delete Stage: %w", err)
 	}
 
@@ -197,17 +317,22 @@ func (r *ReconcileStage) tryToDeleteCDStage(ctx context.Context, stage *cdPipeAp
 	return &reconcile.Result{}, nil
 }
 
+// setFinishStatus marks the stage as ready. The status fields set by the chain handlers are kept.
 func (r *ReconcileStage) setFinishStatus(ctx context.Context, s *cdPipeApi.Stage) error {
-	s.Status = cdPipeApi.StageStatus{
-		Status:          consts.FinishedStatus,
-		Available:       true,
-		LastTimeUpdated: metaV1.Now(),
-		Username:        "system",
-		Action:          cdPipeApi.AcceptCDStageRegistration,
-		Result:          cdPipeApi.Success,
-		Value:           "active",
-		ShouldBeHandled: false,
-	}
+	s.SetCondition(cdPipeApi.ConditionReady, metaV1.ConditionTrue, cdPipeApi.ReasonSucceeded, "Stage has been reconciled successfully")
+
+	s.Status.Status = consts.FinishedStatus
+	s.Status.Available = true
+	s.Status.LastTimeUpdated = metaV1.Now()
+	s.Status.Username = "system"
+	s.Status.Action = cdPipeApi.AcceptCDStageRegistration
+	s.Status.Result = cdPipeApi.Success
+	s.Status.DetailedMessage = ""
+	s.Status.Value = "active"
+	s.Status.ShouldBeHandled = false
+	s.Status.ObservedGeneration = s.Generation
+	s.Status.Plan = nil
+
 	if err := r.client.Status().Update(ctx, s); err != nil {
 		if err = r.client.Update(ctx, s); err != nil {
 			return fmt.Errorf("failed to update stage status: %w", err)
@@ -217,18 +342,23 @@ func (r *ReconcileStage) setFinishStatus(ctx context.Context, s *cdPipeApi.Stage
 	return nil
 }
 
+// setFailedStatus marks the stage as failed. The status fields set by the chain handlers are kept.
 func (r *ReconcileStage) setFailedStatus(ctx context.Context, stage *cdPipeApi.Stage, err error) error {
 	log := ctrl.LoggerFrom(ctx)
 
-	stage.Status = cdPipeApi.StageStatus{
-		Status:          consts.FailedStatus,
-		Available:       false,
-		LastTimeUpdated: metaV1.Now(),
-		Username:        stage.Status.Username,
-		Result:          cdPipeApi.Error,
-		DetailedMessage: err.Error(),
-		Value:           consts.FailedStatus,
-	}
+	stage.SetCondition(cdPipeApi.ConditionReady, metaV1.ConditionFalse, cdPipeApi.ReasonFailed, err.Error())
+	r.recorder.Event(stage, corev1.EventTypeWarning, cdPipeApi.EventReasonReconcileFailed, err.Error())
+
+	stage.Status.Status = consts.FailedStatus
+	stage.Status.Available = false
+	stage.Status.LastTimeUpdated = metaV1.Now()
+	stage.Status.Action = ""
+	stage.Status.Result = cdPipeApi.Error
+	stage.Status.DetailedMessage = err.Error()
+	stage.Status.Value = consts.FailedStatus
+	stage.Status.ShouldBeHandled = false
+	stage.Status.ObservedGeneration = stage.Generation
+	stage.Status.Plan = nil
 
 	if err = r.client.Status().Update(ctx, stage); err != nil {
 		return fmt.Errorf("failed to update stage status: %w", err)
@@ -239,6 +369,31 @@ func (r *ReconcileStage) setFailedStatus(ctx context.Context, stage *cdPipeApi.S
 	return nil
 }
 
+// checkCluster checks if the stage target cluster is registered and reachable.
+func (r *ReconcileStage) checkCluster(ctx context.Context, stage *cdPipeApi.Stage) error {
+	if stage.InCluster() {
+		return nil
+	}
+
+	cluster := &cdPipeApi.Cluster{}
+	if err := r.client.Get(ctx, client.ObjectKey{
+		Namespace: stage.Namespace,
+		Name:      stage.Spec.ClusterName,
+	}, cluster); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return fmt.Errorf("cluster %s is not registered", stage.Spec.ClusterName)
+		}
+
+		return fmt.Errorf("failed to get cluster %s: %w", stage.Spec.ClusterName, err)
+	}
+
+	if !cluster.IsHealthy() {
+		return fmt.Errorf("cluster %s is not connected: %s", cluster.Name, cluster.Status.Message)
+	}
+
+	return nil
+}
+
 // isLastStage checks if stage is last in the pipeline.
 func (r *ReconcileStage) isLastStage(ctx context.Context, stage *cdPipeApi.Stage) (bool, error) {
 	stages := &cdPipeApi.StageList{}
@@ -2,6 +2,7 @@ package stage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -13,15 +14,18 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8sApi "k8s.io/api/rbac/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/objectmodifier"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/cluster"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/consts"
@@ -76,9 +80,10 @@ func TestTryToDeleteCDStage_DeletionTimestampIsZero(t *testing.T) {
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stage).Build()
 
 	reconcileStage := ReconcileStage{
-		client: fakeClient,
-		scheme: scheme,
-		log:    logr.Discard(),
+		client:   fakeClient,
+		scheme:   scheme,
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
 	_, err := reconcileStage.tryToDeleteCDStage(ctrl.LoggerInto(context.Background(), logr.Discard()), stage)
@@ -99,6 +104,7 @@ func TestTryToDeleteCDStage_Success(t *testing.T) {
 	err = corev1.AddToScheme(scheme)
 	require.NoError(t, err)
 	require.NoError(t, projectApi.AddToScheme(scheme))
+	require.NoError(t, k8sApi.AddToScheme(scheme))
 
 	stage := &cdPipeApi.Stage{
 		TypeMeta: metaV1.TypeMeta{},
@@ -153,15 +159,16 @@ func TestTryToDeleteCDStage_Success(t *testing.T) {
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cdPipeline, image, stage, jenkins).Build()
 
 	reconcileStage := ReconcileStage{
-		client: fakeClient,
-		scheme: scheme,
-		log:    logr.Discard(),
+		client:   fakeClient,
+		scheme:   scheme,
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
 	_, err = reconcileStage.tryToDeleteCDStage(ctrl.LoggerInto(context.Background(), logr.Discard()), stage)
 	assert.NoError(t, err)
 
-	previousImageStream, err := cluster.GetCodebaseImageStream(reconcileStage.client, dockerImageName, namespace)
+	previousImageStream, err := cluster.GetCodebaseImageStream(context.Background(), reconcileStage.client, dockerImageName, namespace)
 	assert.NoError(t, err)
 	assert.Empty(t, previousImageStream.Labels)
 
@@ -209,36 +216,46 @@ func TestTryToDeleteCDStage_PostponeDeletion(t *testing.T) {
 
 	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stageToRemove, parentStage).Build()
 
+	recorder := record.NewFakeRecorder(10)
 	controller := NewReconcileStage(
 		k8sClient,
 		scheme,
 		logr.Discard(),
 		objectmodifier.NewStageBatchModifier(k8sClient, []objectmodifier.StageModifier{}),
+		recorder,
 	)
 
 	res, err := controller.tryToDeleteCDStage(ctrl.LoggerInto(context.Background(), logr.Discard()), stageToRemove)
 	require.NoError(t, err)
 	assert.Equal(t, &reconcile.Result{RequeueAfter: waitForParentStagesDeletion}, res)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, cdPipeApi.EventReasonDeletionPostponed)
 }
 
 func TestSetFinishStatus_Success(t *testing.T) {
 	scheme := runtime.NewScheme()
 	scheme.AddKnownTypes(k8sApi.SchemeGroupVersion, &cdPipeApi.Stage{})
 
+	readyTime := metaV1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
 	stage := &cdPipeApi.Stage{
 		TypeMeta: metaV1.TypeMeta{},
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
 		},
+		Status: cdPipeApi.StageStatus{
+			ReadyTime: &readyTime,
+		},
 	}
 
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stage).Build()
 
 	reconcileStage := ReconcileStage{
-		client: fakeClient,
-		scheme: scheme,
-		log:    logr.Discard(),
+		client:   fakeClient,
+		scheme:   scheme,
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
 	err := reconcileStage.setFinishStatus(context.Background(), stage)
@@ -246,6 +263,53 @@ func TestSetFinishStatus_Success(t *testing.T) {
 
 	stageAfterReconcile := getStage(t, reconcileStage.client, name)
 	assert.Equal(t, consts.FinishedStatus, stageAfterReconcile.Status.Status)
+	assert.True(t, meta.IsStatusConditionTrue(stageAfterReconcile.Status.Conditions, cdPipeApi.ConditionReady))
+	require.NotNil(t, stageAfterReconcile.Status.ReadyTime)
+	assert.True(t, readyTime.Equal(stageAfterReconcile.Status.ReadyTime))
+}
+
+func TestSetFailedStatus_KeepsHandlerConditions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	stage := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:       name,
+			Namespace:  namespace,
+			Generation: 2,
+		},
+		Status: cdPipeApi.StageStatus{
+			ChainSteps:   []string{"PutNamespace"},
+			Applications: []cdPipeApi.ApplicationVersion{{Name: "app"}},
+		},
+	}
+	stage.SetCondition(cdPipeApi.ConditionNamespaceReady, metaV1.ConditionTrue, cdPipeApi.ReasonSucceeded, "")
+	stage.SetCondition(cdPipeApi.ConditionRBACReady, metaV1.ConditionFalse, cdPipeApi.ReasonFailed, "rbac error")
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stage).Build()
+
+	reconcileStage := ReconcileStage{
+		client:   fakeClient,
+		scheme:   scheme,
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	err := reconcileStage.setFailedStatus(ctrl.LoggerInto(context.Background(), logr.Discard()), stage, errors.New("rbac error"))
+	require.NoError(t, err)
+
+	stageAfterReconcile := getStage(t, reconcileStage.client, name)
+	assert.Equal(t, consts.FailedStatus, stageAfterReconcile.Status.Status)
+	assert.Equal(t, int64(2), stageAfterReconcile.Status.ObservedGeneration)
+	assert.True(t, meta.IsStatusConditionTrue(stageAfterReconcile.Status.Conditions, cdPipeApi.ConditionNamespaceReady))
+	assert.True(t, meta.IsStatusConditionFalse(stageAfterReconcile.Status.Conditions, cdPipeApi.ConditionRBACReady))
+	assert.Equal(t, []string{"PutNamespace"}, stageAfterReconcile.Status.ChainSteps)
+	assert.Equal(t, []cdPipeApi.ApplicationVersion{{Name: "app"}}, stageAfterReconcile.Status.Applications)
+
+	ready := meta.FindStatusCondition(stageAfterReconcile.Status.Conditions, cdPipeApi.ConditionReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, metaV1.ConditionFalse, ready.Status)
+	assert.Equal(t, "rbac error", ready.Message)
 }
 
 func TestReconcileStage_Reconcile_Success(t *testing.T) {
@@ -319,6 +383,7 @@ func TestReconcileStage_Reconcile_Success(t *testing.T) {
 		scheme,
 		logr.Discard(),
 		objectmodifier.NewStageBatchModifier(fakeClient, []objectmodifier.StageModifier{}),
+		record.NewFakeRecorder(10),
 	)
 
 	_, err = reconcileStage.Reconcile(ctrl.LoggerInto(context.Background(), logr.Discard()), reconcile.Request{NamespacedName: types.NamespacedName{
@@ -327,7 +392,7 @@ func TestReconcileStage_Reconcile_Success(t *testing.T) {
 	}})
 	require.NoError(t, err)
 
-	previousImageStream, err := cluster.GetCodebaseImageStream(reconcileStage.client, dockerImageName, namespace)
+	previousImageStream, err := cluster.GetCodebaseImageStream(context.Background(), reconcileStage.client, dockerImageName, namespace)
 	assert.NoError(t, err)
 	assert.Empty(t, previousImageStream.Labels)
 
@@ -347,8 +412,9 @@ func TestReconcileStage_Reconcile_Success(t *testing.T) {
 func TestReconcileStage_ReconcileReconcile_SetOwnerRef(t *testing.T) {
 	scheme := runtime.NewScheme()
 	scheme.AddKnownTypes(k8sApi.SchemeGroupVersion, &cdPipeApi.Stage{},
-		&cdPipeApi.CDPipeline{}, &codebaseApi.CodebaseImageStream{}, &corev1.Namespace{},
-		&componentApi.EDPComponent{}, &k8sApi.RoleBinding{}, &k8sApi.Role{}, &jenkinsApi.JenkinsJob{})
+		&cdPipeApi.CDPipeline{}, &codebaseApi.CodebaseImageStream{}, &codebaseApi.CodebaseImageStreamList{}, &corev1.Namespace{}, &corev1.ConfigMap{},
+		&componentApi.EDPComponent{}, &k8sApi.RoleBinding{}, &k8sApi.RoleBindingList{}, &k8sApi.Role{}, &k8sApi.RoleList{},
+		&jenkinsApi.JenkinsJob{})
 
 	edpComponent := &componentApi.EDPComponent{
 		TypeMeta: metaV1.TypeMeta{},
@@ -407,6 +473,7 @@ func TestReconcileStage_ReconcileReconcile_SetOwnerRef(t *testing.T) {
 		scheme,
 		logr.Discard(),
 		objectmodifier.NewStageBatchModifierAll(fakeClient, scheme),
+		record.NewFakeRecorder(10),
 	)
 
 	_, err := reconcileStage.Reconcile(ctrl.LoggerInto(context.Background(), logr.Discard()), reconcile.Request{NamespacedName: types.NamespacedName{
@@ -422,6 +489,197 @@ func TestReconcileStage_ReconcileReconcile_SetOwnerRef(t *testing.T) {
 	stageAfterReconcile := getStage(t, reconcileStage.client, name)
 	assert.Equal(t, cdPipeline.Name, stageAfterReconcile.OwnerReferences[0].Name)
 	assert.Equal(t, expectedLabels, stageAfterReconcile.Labels)
+	require.NotNil(t, stageAfterReconcile.Status.ReadyTime)
+}
+
+func TestReconcileStage_Reconcile_DryRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypes(k8sApi.SchemeGroupVersion, &cdPipeApi.Stage{},
+		&cdPipeApi.CDPipeline{}, &codebaseApi.CodebaseImageStream{}, &codebaseApi.CodebaseImageStreamList{}, &corev1.Namespace{}, &corev1.ConfigMap{},
+		&componentApi.EDPComponent{}, &k8sApi.RoleBinding{}, &k8sApi.RoleBindingList{}, &k8sApi.Role{}, &k8sApi.RoleList{},
+		&jenkinsApi.JenkinsJob{})
+
+	stage := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Finalizers:  []string{envLabelDeletionFinalizer},
+			Annotations: map[string]string{cdPipeApi.DryRunAnnotation: "true"},
+		},
+		Spec: cdPipeApi.StageSpec{
+			Name:        name,
+			CdPipeline:  cdPipeline,
+			TriggerType: consts.AutoDeployTriggerType,
+		},
+	}
+
+	cdPipeline := &cdPipeApi.CDPipeline{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      cdPipeline,
+			Namespace: namespace,
+		},
+		Spec: cdPipeApi.CDPipelineSpec{
+			InputDockerStreams: []string{dockerImageName},
+			Name:               name,
+		},
+	}
+
+	image := &codebaseApi.CodebaseImageStream{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      dockerImageName,
+			Namespace: namespace,
+		},
+	}
+
+	edpComponent := &componentApi.EDPComponent{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      dockerRegistry,
+			Namespace: namespace,
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cdPipeline, image, stage, edpComponent).Build()
+	recorder := record.NewFakeRecorder(10)
+
+	reconcileStage := NewReconcileStage(
+		fakeClient,
+		scheme,
+		logr.Discard(),
+		objectmodifier.NewStageBatchModifier(fakeClient, []objectmodifier.StageModifier{}),
+		recorder,
+	)
+
+	_, err := reconcileStage.Reconcile(ctrl.LoggerInto(context.Background(), logr.Discard()), reconcile.Request{NamespacedName: types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}})
+	require.NoError(t, err)
+
+	stageAfterReconcile := getStage(t, fakeClient, name)
+	require.NotEmpty(t, stageAfterReconcile.Status.Plan)
+	assert.Contains(t, stageAfterReconcile.Status.Plan, cdPipeApi.PlannedChange{
+		Action: "Create",
+		Kind:   "Namespace",
+		Name:   fmt.Sprintf("%s-%s", namespace, name),
+	})
+	assert.True(t, meta.IsStatusConditionTrue(stageAfterReconcile.Status.Conditions, cdPipeApi.ConditionPlanned))
+	assert.Nil(t, meta.FindStatusCondition(stageAfterReconcile.Status.Conditions, cdPipeApi.ConditionReady))
+	assert.Contains(t, <-recorder.Events, cdPipeApi.EventReasonDryRunPlanned)
+
+	err = fakeClient.Get(context.Background(), types.NamespacedName{Name: fmt.Sprintf("%s-%s", namespace, name)}, &corev1.Namespace{})
+	assert.True(t, k8sErrors.IsNotFound(err))
+
+	imageAfterReconcile := &codebaseApi.CodebaseImageStream{}
+	require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: dockerImageName}, imageAfterReconcile))
+	assert.Empty(t, imageAfterReconcile.Labels)
+}
+
+func TestReconcileStage_Reconcile_DryRunKeepsNamespaceTemplateResources(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+	require.NoError(t, codebaseApi.AddToScheme(scheme))
+	require.NoError(t, k8sApi.AddToScheme(scheme))
+	require.NoError(t, jenkinsApi.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, componentApi.AddToScheme(scheme))
+
+	const targetNamespace = "team-dev"
+
+	// The template has been removed from the stage, so its resources should be deleted by the real run.
+	stage := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Finalizers:  []string{envLabelDeletionFinalizer},
+			Annotations: map[string]string{cdPipeApi.DryRunAnnotation: "true"},
+		},
+		Spec: cdPipeApi.StageSpec{
+			Name:        name,
+			CdPipeline:  cdPipeline,
+			TriggerType: "Manual",
+			Namespace:   targetNamespace,
+		},
+		Status: cdPipeApi.StageStatus{
+			NamespaceTemplateResources: []cdPipeApi.AppliedResource{
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "settings"},
+			},
+		},
+	}
+
+	pipeline := &cdPipeApi.CDPipeline{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      cdPipeline,
+			Namespace: namespace,
+		},
+		Spec: cdPipeApi.CDPipelineSpec{
+			InputDockerStreams: []string{dockerImageName},
+			Name:               cdPipeline,
+		},
+	}
+
+	image := &codebaseApi.CodebaseImageStream{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      dockerImageName,
+			Namespace: namespace,
+		},
+	}
+
+	targetNs := &corev1.Namespace{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:   targetNamespace,
+			Labels: map[string]string{util.TenantLabelName: namespace, util.StageLabelName: name},
+		},
+	}
+
+	settings := &corev1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "settings",
+			Namespace: targetNamespace,
+			Labels:    map[string]string{cdPipeApi.NamespaceTemplateLabelName: "small"},
+		},
+	}
+
+	edpComponent := &componentApi.EDPComponent{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      dockerRegistry,
+			Namespace: namespace,
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stage, pipeline, image, targetNs, settings, edpComponent).Build()
+
+	reconcileStage := NewReconcileStage(
+		fakeClient,
+		scheme,
+		logr.Discard(),
+		objectmodifier.NewStageBatchModifier(fakeClient, []objectmodifier.StageModifier{}),
+		record.NewFakeRecorder(100),
+	)
+
+	ctx := ctrl.LoggerInto(context.Background(), logr.Discard())
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+
+	_, err := reconcileStage.Reconcile(ctx, request)
+	require.NoError(t, err)
+
+	planned := getStage(t, fakeClient, name)
+	assert.True(t, meta.IsStatusConditionTrue(planned.Status.Conditions, cdPipeApi.ConditionPlanned))
+	assert.Contains(t, planned.Status.Plan, cdPipeApi.PlannedChange{Action: "Delete", Kind: "ConfigMap", Name: "settings", Namespace: targetNamespace})
+	assert.Equal(t, stage.Status.NamespaceTemplateResources, planned.Status.NamespaceTemplateResources)
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(settings), &corev1.ConfigMap{}))
+
+	delete(planned.Annotations, cdPipeApi.DryRunAnnotation)
+	require.NoError(t, fakeClient.Update(ctx, planned))
+
+	_, err = reconcileStage.Reconcile(ctx, request)
+	require.NoError(t, err)
+
+	reconciled := getStage(t, fakeClient, name)
+	assert.Empty(t, reconciled.Status.NamespaceTemplateResources)
+	assert.Empty(t, reconciled.Status.Plan)
+
+	err = fakeClient.Get(ctx, client.ObjectKeyFromObject(settings), &corev1.ConfigMap{})
+	assert.True(t, k8sErrors.IsNotFound(err))
 }
 
 func TestReconcileStage_Reconcile_StageIsNotFound(t *testing.T) {
@@ -431,9 +689,10 @@ func TestReconcileStage_Reconcile_StageIsNotFound(t *testing.T) {
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
 
 	reconcileStage := ReconcileStage{
-		client: fakeClient,
-		scheme: scheme,
-		log:    logr.Discard(),
+		client:   fakeClient,
+		scheme:   scheme,
+		log:      logr.Discard(),
+		recorder: record.NewFakeRecorder(10),
 	}
 
 	stage := &cdPipeApi.Stage{}
@@ -557,6 +816,7 @@ func TestReconcileStage_isLastStage(t *testing.T) {
 				scheme,
 				logr.Discard(),
 				objectmodifier.NewStageBatchModifierAll(k8sClient, scheme),
+				record.NewFakeRecorder(10),
 			)
 
 			got, err := r.isLastStage(ctrl.LoggerInto(context.Background(), logr.Discard()), tt.stage)
@@ -565,3 +825,117 @@ func TestReconcileStage_isLastStage(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileStage_checkCluster(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	tests := []struct {
+		name    string
+		stage   *cdPipeApi.Stage
+		objects []client.Object
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name: "should skip check for in-cluster stage",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Name:      "stage-1",
+					Namespace: "ns-1",
+				},
+				Spec: cdPipeApi.StageSpec{
+					ClusterName: cdPipeApi.InCluster,
+				},
+			},
+			wantErr: require.NoError,
+		},
+		{
+			name: "should pass for connected cluster",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Name:      "stage-1",
+					Namespace: "ns-1",
+				},
+				Spec: cdPipeApi.StageSpec{
+					ClusterName: "external",
+				},
+			},
+			objects: []client.Object{
+				&cdPipeApi.Cluster{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      "external",
+						Namespace: "ns-1",
+					},
+					Status: cdPipeApi.ClusterStatus{
+						Connected: true,
+					},
+				},
+			},
+			wantErr: require.NoError,
+		},
+		{
+			name: "should fail if cluster is not registered",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Name:      "stage-1",
+					Namespace: "ns-1",
+				},
+				Spec: cdPipeApi.StageSpec{
+					ClusterName: "external",
+				},
+			},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "cluster external is not registered")
+			},
+		},
+		{
+			name: "should fail if cluster is not connected",
+			stage: &cdPipeApi.Stage{
+				ObjectMeta: metaV1.ObjectMeta{
+					Name:      "stage-1",
+					Namespace: "ns-1",
+				},
+				Spec: cdPipeApi.StageSpec{
+					ClusterName: "external",
+				},
+			},
+			objects: []client.Object{
+				&cdPipeApi.Cluster{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:      "external",
+						Namespace: "ns-1",
+					},
+					Status: cdPipeApi.ClusterStatus{
+						Connected: false,
+						Message:   "connection refused",
+					},
+				},
+			},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "cluster external is not connected: connection refused")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+			r := NewReconcileStage(
+				k8sClient,
+				scheme,
+				logr.Discard(),
+				objectmodifier.NewStageBatchModifierAll(k8sClient, scheme),
+				record.NewFakeRecorder(10),
+			)
+
+			tt.wantErr(t, r.checkCluster(context.Background(), tt.stage))
+		})
+	}
+}
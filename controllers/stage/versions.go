@@ -0,0 +1,119 @@
+package stage
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/exp/slices"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/cluster"
+	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
+)
+
+// getApplicationVersions returns the image tags of the CDPipeline applications in the stage.
+// The input stream of the application is the CDPipeline input stream for the first stage and the applications
+// which are not promoted, otherwise it is the previous stage verified stream.
+// Streams which don't exist yet are shown without tags.
+func getApplicationVersions(ctx context.Context, c client.Client, stage *cdPipeApi.Stage) ([]cdPipeApi.ApplicationVersion, error) {
+	pipe, err := util.GetCdPipeline(ctx, c, stage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s cd pipeline: %w", stage.Spec.CdPipeline, err)
+	}
+
+	var previousStageName string
+
+	versions := make([]cdPipeApi.ApplicationVersion, 0, len(pipe.Spec.InputDockerStreams))
+
+	for _, ids := range pipe.Spec.InputDockerStreams {
+		inputStream, err := getImageStream(ctx, c, cluster.CodebaseImageStreamName(ids), stage.Namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		if inputStream == nil {
+			continue
+		}
+
+		app := inputStream.Spec.Codebase
+		version := cdPipeApi.ApplicationVersion{
+			Name:                app,
+			InputImageStream:    inputStream.Name,
+			VerifiedImageStream: util.VerifiedImageStreamName(pipe.Name, stage.Spec.Name, app),
+		}
+
+		if !stage.IsFirst() && slices.Contains(pipe.Spec.ApplicationsToPromote, app) {
+			if previousStageName == "" {
+				if previousStageName, err = util.FindPreviousStageName(ctx, c, stage); err != nil {
+					return nil, fmt.Errorf("failed to get previous stage name: %w", err)
+				}
+			}
+
+			version.InputImageStream = util.VerifiedImageStreamName(pipe.Name, previousStageName, app)
+
+			if inputStream, err = getImageStream(ctx, c, version.InputImageStream, stage.Namespace); err != nil {
+				return nil, err
+			}
+		}
+
+		version.AvailableTag = latestTag(inputStream)
+
+		verifiedStream, err := getImageStream(ctx, c, version.VerifiedImageStream, stage.Namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		version.VerifiedTag = latestTag(verifiedStream)
+
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
+
+// getImageStream returns the CodebaseImageStream or nil if it doesn't exist.
+func getImageStream(ctx context.Context, c client.Client, name, namespace string) (*codebaseApi.CodebaseImageStream, error) {
+	stream := &codebaseApi.CodebaseImageStream{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, stream); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to get %s codebase image stream: %w", name, err)
+	}
+
+	return stream, nil
+}
+
+// latestTag returns a name of the most recently created tag of the stream.
+func latestTag(stream *codebaseApi.CodebaseImageStream) string {
+	tag, ok := latestImageTag(stream)
+	if !ok {
+		return ""
+	}
+
+	return tag.Name
+}
+
+// latestImageTag returns the most recently created tag of the stream and false if the stream doesn't have tags.
+// Creation time is in the ISO 8601 format, so it is compared as a string.
+// Tags with the same creation time are ordered by their position in the stream.
+func latestImageTag(stream *codebaseApi.CodebaseImageStream) (codebaseApi.Tag, bool) {
+	if stream == nil || len(stream.Spec.Tags) == 0 {
+		return codebaseApi.Tag{}, false
+	}
+
+	latest := stream.Spec.Tags[0]
+
+	for _, tag := range stream.Spec.Tags[1:] {
+		if tag.Created >= latest.Created {
+			latest = tag
+		}
+	}
+
+	return latest, true
+}
@@ -0,0 +1,129 @@
+package stage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
+)
+
+// NewReconcileStageVersions creates a new ReconcileStageVersions.
+func NewReconcileStageVersions(c client.Client, log logr.Logger, recorder record.EventRecorder) *ReconcileStageVersions {
+	return &ReconcileStageVersions{
+		client:   c,
+		log:      log.WithName("cd-stage-versions"),
+		recorder: recorder,
+	}
+}
+
+// ReconcileStageVersions refreshes the application versions of the stage and promotes new versions
+// by the stage promotion policy. It doesn't run the stage chain, so the CodebaseImageStream tag changes
+// are handled without reconfiguring the stage namespace.
+type ReconcileStageVersions struct {
+	client   client.Client
+	log      logr.Logger
+	recorder record.EventRecorder
+}
+
+func (r *ReconcileStageVersions) SetupWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("cd-stage-versions").
+		For(&cdPipeApi.Stage{}, builder.WithPredicates(stageVersionsPredicate())).
+		Watches(&source.Kind{Type: &cdPipeApi.CDPipeline{}}, NewPipelineEventHandler(r.client, r.log)).
+		Watches(
+			&source.Kind{Type: &codebaseApi.CodebaseImageStream{}},
+			handler.EnqueueRequestsFromMapFunc(NewImageStreamMapFunc(r.client, r.log)),
+			builder.WithPredicates(imageStreamTagsChangedPredicate()),
+		).
+		Complete(r); err != nil {
+		return fmt.Errorf("failed to create stage versions controller: %w", err)
+	}
+
+	return nil
+}
+
+//+kubebuilder:rbac:groups=v2.edp.epam.com,namespace=placeholder,resources=cdstagedeployments,verbs=get;list;watch;create
+
+func (r *ReconcileStageVersions) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	stage := &cdPipeApi.Stage{}
+	if err := r.client.Get(ctx, request.NamespacedName, stage); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, fmt.Errorf("failed to get stage: %w", err)
+	}
+
+	if !stage.GetDeletionTimestamp().IsZero() || stage.IsDryRun() {
+		return reconcile.Result{}, nil
+	}
+
+	oldStatus := stage.Status.DeepCopy()
+
+	versions, err := getApplicationVersions(ctx, r.client, stage)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to get application versions: %w", err)
+	}
+
+	stage.Status.Applications = versions
+
+	var requeue time.Duration
+
+	// New versions are promoted only to the configured stage.
+	if meta.IsStatusConditionTrue(stage.Status.Conditions, cdPipeApi.ConditionReady) {
+		if requeue, err = r.autoPromote(ctx, stage); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to promote new versions: %w", err)
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(oldStatus, &stage.Status) {
+		if err = r.client.Status().Update(ctx, stage); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to update stage status: %w", err)
+		}
+
+		log.Info("Stage application versions have been updated")
+	}
+
+	return reconcile.Result{RequeueAfter: requeue}, nil
+}
+
+// stageVersionsPredicate passes the stage creation, spec changes and the stage readiness changes.
+func stageVersionsPredicate() predicate.Predicate {
+	return predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oo, ok := e.ObjectOld.(*cdPipeApi.Stage)
+				if !ok {
+					return false
+				}
+
+				no, ok := e.ObjectNew.(*cdPipeApi.Stage)
+				if !ok {
+					return false
+				}
+
+				return meta.IsStatusConditionTrue(oo.Status.Conditions, cdPipeApi.ConditionReady) !=
+					meta.IsStatusConditionTrue(no.Status.Conditions, cdPipeApi.ConditionReady)
+			},
+		},
+	)
+}
@@ -0,0 +1,88 @@
+package stage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
+)
+
+func TestReconcileStageVersions_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+	require.NoError(t, codebaseApi.AddToScheme(scheme))
+
+	stage := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "pipe-dev",
+			Namespace: "default",
+		},
+		Spec: cdPipeApi.StageSpec{
+			Name:       "dev",
+			CdPipeline: "pipe",
+		},
+		Status: cdPipeApi.StageStatus{
+			ChainSteps: []string{"PutNamespace"},
+		},
+	}
+	stage.SetCondition(cdPipeApi.ConditionReady, metaV1.ConditionTrue, cdPipeApi.ReasonSucceeded, "")
+
+	pipeline := &cdPipeApi.CDPipeline{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "pipe",
+			Namespace: "default",
+		},
+		Spec: cdPipeApi.CDPipelineSpec{
+			InputDockerStreams: []string{"app1-main"},
+		},
+	}
+
+	stream := &codebaseApi.CodebaseImageStream{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "app1-main",
+			Namespace: "default",
+		},
+		Spec: codebaseApi.CodebaseImageStreamSpec{
+			Codebase: "app1",
+			Tags:     []codebaseApi.Tag{{Name: "1.0.0", Created: "2023-01-01T00:00:00Z"}},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stage, pipeline, stream).Build()
+
+	r := NewReconcileStageVersions(k8sClient, logr.Discard(), record.NewFakeRecorder(10))
+
+	_, err := r.Reconcile(ctrl.LoggerInto(context.Background(), logr.Discard()), reconcile.Request{
+		NamespacedName: types.NamespacedName{Namespace: stage.Namespace, Name: stage.Name},
+	})
+	require.NoError(t, err)
+
+	got := &cdPipeApi.Stage{}
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Namespace: stage.Namespace, Name: stage.Name}, got))
+
+	assert.Equal(t, []cdPipeApi.ApplicationVersion{
+		{
+			Name:                "app1",
+			InputImageStream:    "app1-main",
+			AvailableTag:        "1.0.0",
+			VerifiedImageStream: "pipe-dev-app1-verified",
+		},
+	}, got.Status.Applications)
+	assert.Equal(t, []string{"PutNamespace"}, got.Status.ChainSteps)
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, cdPipeApi.ConditionReady))
+}
@@ -0,0 +1,174 @@
+package stage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
+)
+
+func TestGetApplicationVersions(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+	require.NoError(t, codebaseApi.AddToScheme(scheme))
+
+	newStream := func(name, codebase string, tags ...codebaseApi.Tag) *codebaseApi.CodebaseImageStream {
+		return &codebaseApi.CodebaseImageStream{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: codebaseApi.CodebaseImageStreamSpec{
+				Codebase: codebase,
+				Tags:     tags,
+			},
+		}
+	}
+
+	newStage := func(name string, order int) *cdPipeApi.Stage {
+		return &cdPipeApi.Stage{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      "pipe-" + name,
+				Namespace: "default",
+				Labels:    map[string]string{cdPipeApi.StageCdPipelineLabelName: "pipe"},
+			},
+			Spec: cdPipeApi.StageSpec{
+				Name:       name,
+				CdPipeline: "pipe",
+				Order:      order,
+			},
+		}
+	}
+
+	pipeline := &cdPipeApi.CDPipeline{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "pipe",
+			Namespace: "default",
+		},
+		Spec: cdPipeApi.CDPipelineSpec{
+			InputDockerStreams:    []string{"app1-main", "app2-main", "app3-main"},
+			ApplicationsToPromote: []string{"app1"},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		pipeline,
+		newStage("dev", 0),
+		newStage("qa", 1),
+		newStream("app1-main", "app1",
+			codebaseApi.Tag{Name: "1.0.1", Created: "2023-01-02T00:00:00Z"},
+			codebaseApi.Tag{Name: "1.0.0", Created: "2023-01-01T00:00:00Z"},
+		),
+		newStream("app2-main", "app2", codebaseApi.Tag{Name: "2.0.0", Created: "2023-01-01T00:00:00Z"}),
+		newStream("pipe-dev-app1-verified", "app1", codebaseApi.Tag{Name: "1.0.0", Created: "2023-01-03T00:00:00Z"}),
+		newStream("pipe-dev-app2-verified", "app2"),
+	).Build()
+
+	tests := []struct {
+		name  string
+		stage *cdPipeApi.Stage
+		want  []cdPipeApi.ApplicationVersion
+	}{
+		{
+			name:  "first stage",
+			stage: newStage("dev", 0),
+			want: []cdPipeApi.ApplicationVersion{
+				{
+					Name:                "app1",
+					InputImageStream:    "app1-main",
+					AvailableTag:        "1.0.1",
+					VerifiedImageStream: "pipe-dev-app1-verified",
+					VerifiedTag:         "1.0.0",
+				},
+				{
+					Name:                "app2",
+					InputImageStream:    "app2-main",
+					AvailableTag:        "2.0.0",
+					VerifiedImageStream: "pipe-dev-app2-verified",
+				},
+			},
+		},
+		{
+			name:  "promoted application is deployed from previous stage",
+			stage: newStage("qa", 1),
+			want: []cdPipeApi.ApplicationVersion{
+				{
+					Name:                "app1",
+					InputImageStream:    "pipe-dev-app1-verified",
+					AvailableTag:        "1.0.0",
+					VerifiedImageStream: "pipe-qa-app1-verified",
+				},
+				{
+					Name:                "app2",
+					InputImageStream:    "app2-main",
+					AvailableTag:        "2.0.0",
+					VerifiedImageStream: "pipe-qa-app2-verified",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := getApplicationVersions(context.Background(), k8sClient, tt.stage)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLatestTag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		tags []codebaseApi.Tag
+		want string
+	}{
+		{
+			name: "no tags",
+			want: "",
+		},
+		{
+			name: "latest tag by creation time",
+			tags: []codebaseApi.Tag{
+				{Name: "1.0.1", Created: "2023-01-02T00:00:00Z"},
+				{Name: "1.0.2", Created: "2023-01-03T00:00:00Z"},
+				{Name: "1.0.0", Created: "2023-01-01T00:00:00Z"},
+			},
+			want: "1.0.2",
+		},
+		{
+			name: "the last tag with the same creation time",
+			tags: []codebaseApi.Tag{
+				{Name: "1.0.0", Created: "2023-01-01T00:00:00Z"},
+				{Name: "1.0.1", Created: "2023-01-01T00:00:00Z"},
+			},
+			want: "1.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, latestTag(&codebaseApi.CodebaseImageStream{
+				Spec: codebaseApi.CodebaseImageStreamSpec{Tags: tt.tags},
+			}))
+		})
+	}
+}
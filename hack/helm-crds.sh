@@ -0,0 +1,51 @@
+#!/bin/bash
+# Copies the generated CRDs to the Helm chart.
+# CRDs served with the conversion webhook are rendered as chart templates,
+# because the conversion is configured only if the webhooks are enabled.
+set -o errexit -o nounset -o pipefail
+
+SRC_DIR=config/crd/bases
+CRDS_DIR=deploy-templates/crds
+TEMPLATES_DIR=deploy-templates/templates/crds
+CONVERSION_CRDS="v2.edp.epam.com_cdpipelines.yaml v2.edp.epam.com_stages.yaml"
+
+for src in "${SRC_DIR}"/*.yaml; do
+  name=$(basename "${src}")
+
+  if [[ " ${CONVERSION_CRDS} " != *" ${name} "* ]]; then
+    cp "${src}" "${CRDS_DIR}/${name}"
+    continue
+  fi
+
+  rm -f "${CRDS_DIR}/${name}"
+
+  awk '
+    /^  annotations:$/ && !annotations {
+      print
+      print "    helm.sh/resource-policy: keep"
+      print "    {{- if .Values.webhook.enabled }}"
+      print "    cert-manager.io/inject-ca-from: {{ .Release.Namespace }}/{{ .Values.name }}-serving-cert"
+      print "    {{- end }}"
+      annotations = 1
+      next
+    }
+    /^spec:$/ && !spec {
+      print
+      print "  {{- if .Values.webhook.enabled }}"
+      print "  conversion:"
+      print "    strategy: Webhook"
+      print "    webhook:"
+      print "      clientConfig:"
+      print "        service:"
+      print "          name: {{ .Values.name }}-webhook-service"
+      print "          namespace: {{ .Release.Namespace }}"
+      print "          path: /convert"
+      print "      conversionReviewVersions:"
+      print "        - v1"
+      print "  {{- end }}"
+      spec = 1
+      next
+    }
+    { print }
+  ' "${src}" > "${TEMPLATES_DIR}/${name}"
+done
@@ -15,13 +15,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlMetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	cdPipeApiV1 "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
 	cdPipeApiV1Alpha1 "github.com/epam/edp-cd-pipeline-operator/v2/api/v1alpha1"
 	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/cdpipeline"
+	clusterCtrl "github.com/epam/edp-cd-pipeline-operator/v2/controllers/cluster"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/promotion"
 	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/metrics"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/objectmodifier"
 	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/cluster"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/webhook"
 	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
 	buildInfo "github.com/epam/edp-common/pkg/config"
 	edpCompApi "github.com/epam/edp-component-operator/api/v1"
@@ -120,23 +125,69 @@ func main() {
 	}
 
 	ctrlLog := ctrl.Log.WithName("controllers")
-	cdPipeCtrl := cdpipeline.NewReconcileCDPipeline(cl, mgr.GetScheme(), ctrlLog)
+	recorder := mgr.GetEventRecorderFor("cd-pipeline-operator")
+	cdPipeCtrl := cdpipeline.NewReconcileCDPipeline(cl, mgr.GetScheme(), ctrlLog, recorder)
 
 	if err = cdPipeCtrl.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "cd-pipeline")
 		os.Exit(1)
 	}
 
+	if err = cdpipeline.NewReconcileCDPipelineOverview(cl, ctrlLog).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "cd-pipeline-overview")
+		os.Exit(1)
+	}
+
 	if err = stage.NewReconcileStage(
 		cl,
 		mgr.GetScheme(),
 		ctrlLog,
 		objectmodifier.NewStageBatchModifierAll(cl, mgr.GetScheme()),
+		recorder,
 	).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "cd-stage")
 		os.Exit(1)
 	}
 
+	if err = stage.NewReconcileStageVersions(cl, ctrlLog, recorder).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "cd-stage-versions")
+		os.Exit(1)
+	}
+
+	if err = clusterCtrl.NewReconcileCluster(cl, mgr.GetScheme(), ctrlLog).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "cluster")
+		os.Exit(1)
+	}
+
+	if err = promotion.NewReconcilePromotion(cl, mgr.GetScheme(), ctrlLog, recorder, cluster.WebhooksEnabled()).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "promotion")
+		os.Exit(1)
+	}
+
+	if cluster.WebhooksEnabled() {
+		setupLog.Info("Webhooks are enabled")
+
+		if err = webhook.NewStageWebhook(cl, ctrl.Log.WithName("webhooks")).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "stage")
+			os.Exit(1)
+		}
+
+		if err = webhook.NewCDPipelineWebhook(cl, ctrl.Log.WithName("webhooks")).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "cd-pipeline")
+			os.Exit(1)
+		}
+
+		if err = webhook.NewApprovalWebhook(cl, ctrl.Log.WithName("webhooks")).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "approval")
+			os.Exit(1)
+		}
+	}
+
+	if err = ctrlMetrics.Registry.Register(metrics.NewStatusCollector(mgr.GetClient())); err != nil {
+		setupLog.Error(err, "unable to register metrics collector")
+		os.Exit(1)
+	}
+
 	if err = mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
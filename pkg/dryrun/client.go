@@ -0,0 +1,379 @@
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"gomodules.xyz/jsonpatch/v2"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+// Actions of the planned changes.
+const (
+	ActionCreate      = "Create"
+	ActionUpdate      = "Update"
+	ActionDelete      = "Delete"
+	ActionDeleteAllOf = "DeleteAllOf"
+
+	// maxDiffLength limits the size of the diff in the plan, so the Stage status doesn't grow too much.
+	maxDiffLength = 4096
+
+	statusSubResource = "status"
+)
+
+// Plan collects the changes that would be made by the clients.
+type Plan struct {
+	mu      sync.Mutex
+	changes []cdPipeApi.PlannedChange
+}
+
+func NewPlan() *Plan {
+	return &Plan{}
+}
+
+// Changes returns the planned changes in the order they would be made.
+func (p *Plan) Changes() []cdPipeApi.PlannedChange {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]cdPipeApi.PlannedChange(nil), p.changes...)
+}
+
+func (p *Plan) add(change cdPipeApi.PlannedChange) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.changes = append(p.changes, change)
+}
+
+type objectKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+// Client records the changes to the Plan instead of applying them.
+// Get returns the objects with the recorded changes, so the next changes are planned on top of the previous ones.
+// List is served by the wrapped client without the recorded changes.
+type Client struct {
+	client.Client
+	plan *Plan
+
+	mu      sync.Mutex
+	objects map[objectKey]client.Object
+	deleted map[objectKey]bool
+}
+
+func NewClient(c client.Client, plan *Plan) *Client {
+	return &Client{
+		Client:  c,
+		plan:    plan,
+		objects: make(map[objectKey]client.Object),
+		deleted: make(map[objectKey]bool),
+	}
+}
+
+func (c *Client) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	k, err := c.key(obj, key.Namespace, key.Name)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	stored, ok := c.objects[k]
+	deleted := c.deleted[k]
+	c.mu.Unlock()
+
+	if deleted {
+		return k8sErrors.NewNotFound(schema.GroupResource{Group: k.gvk.Group, Resource: k.gvk.Kind}, key.Name)
+	}
+
+	if ok {
+		return copyObject(stored, obj)
+	}
+
+	if err = c.Client.Get(ctx, key, obj, opts...); err != nil {
+		return fmt.Errorf("failed to get object: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) Create(ctx context.Context, obj client.Object, _ ...client.CreateOption) error {
+	k, err := c.key(obj, obj.GetNamespace(), obj.GetName())
+	if err != nil {
+		return err
+	}
+
+	current, err := c.current(ctx, k, obj)
+	if err != nil {
+		return err
+	}
+
+	if current != nil {
+		return k8sErrors.NewAlreadyExists(schema.GroupResource{Group: k.gvk.Group, Resource: k.gvk.Kind}, obj.GetName())
+	}
+
+	c.store(k, obj)
+	c.plan.add(newChange(ActionCreate, k, ""))
+
+	return nil
+}
+
+func (c *Client) Update(ctx context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	return c.update(ctx, obj, "")
+}
+
+// Patch records the object as it is, so the patch should be created from the object changes, e.g. client.MergeFrom.
+func (c *Client) Patch(ctx context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+	return c.update(ctx, obj, "")
+}
+
+func (c *Client) Delete(ctx context.Context, obj client.Object, _ ...client.DeleteOption) error {
+	k, err := c.key(obj, obj.GetNamespace(), obj.GetName())
+	if err != nil {
+		return err
+	}
+
+	current, err := c.current(ctx, k, obj)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		return k8sErrors.NewNotFound(schema.GroupResource{Group: k.gvk.Group, Resource: k.gvk.Kind}, obj.GetName())
+	}
+
+	c.mu.Lock()
+	delete(c.objects, k)
+	c.deleted[k] = true
+	c.mu.Unlock()
+
+	c.plan.add(newChange(ActionDelete, k, ""))
+
+	return nil
+}
+
+func (c *Client) DeleteAllOf(_ context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	k, err := c.key(obj, "", "")
+	if err != nil {
+		return err
+	}
+
+	o := &client.DeleteAllOfOptions{}
+	o.ApplyOptions(opts)
+	k.namespace = o.Namespace
+
+	c.plan.add(newChange(ActionDeleteAllOf, k, ""))
+
+	return nil
+}
+
+func (c *Client) Status() client.SubResourceWriter {
+	return c.SubResource(statusSubResource)
+}
+
+func (c *Client) SubResource(subResource string) client.SubResourceClient {
+	return &subResourceClient{
+		SubResourceClient: c.Client.SubResource(subResource),
+		client:            c,
+		subResource:       subResource,
+	}
+}
+
+func (c *Client) update(ctx context.Context, obj client.Object, subResource string) error {
+	k, err := c.key(obj, obj.GetNamespace(), obj.GetName())
+	if err != nil {
+		return err
+	}
+
+	current, err := c.current(ctx, k, obj)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		return k8sErrors.NewNotFound(schema.GroupResource{Group: k.gvk.Group, Resource: k.gvk.Kind}, obj.GetName())
+	}
+
+	diff, err := makeDiff(current, obj, subResource)
+	if err != nil {
+		return err
+	}
+
+	c.store(k, obj)
+
+	if diff == "" {
+		return nil
+	}
+
+	action := ActionUpdate
+	if subResource != "" {
+		action = fmt.Sprintf("%s %s", ActionUpdate, subResource)
+	}
+
+	c.plan.add(newChange(action, k, diff))
+
+	return nil
+}
+
+// current returns the current state of the object or nil if it doesn't exist.
+func (c *Client) current(ctx context.Context, k objectKey, obj client.Object) (client.Object, error) {
+	current, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("failed to copy %s object", k.gvk.Kind)
+	}
+
+	if err := c.Get(ctx, client.ObjectKey{Namespace: k.namespace, Name: k.name}, current); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return current, nil
+}
+
+func (c *Client) store(k objectKey, obj client.Object) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stored, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return
+	}
+
+	c.objects[k] = stored
+	delete(c.deleted, k)
+}
+
+func (c *Client) key(obj runtime.Object, namespace, name string) (objectKey, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.Client.Scheme())
+	if err != nil {
+		return objectKey{}, fmt.Errorf("failed to get object kind: %w", err)
+	}
+
+	return objectKey{gvk: gvk, namespace: namespace, name: name}, nil
+}
+
+// copyObject copies the stored object to obj which can be of another type, e.g. unstructured.Unstructured.
+func copyObject(stored, obj client.Object) error {
+	if reflect.TypeOf(stored) == reflect.TypeOf(obj) {
+		reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(stored.DeepCopyObject()).Elem())
+
+		return nil
+	}
+
+	// Typed objects usually don't have the kind set, so it is taken from the requested object.
+	typed := stored.DeepCopyObject()
+	typed.GetObjectKind().SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+
+	data, err := json.Marshal(typed)
+	if err != nil {
+		return fmt.Errorf("failed to marshal object: %w", err)
+	}
+
+	if err = json.Unmarshal(data, obj); err != nil {
+		return fmt.Errorf("failed to unmarshal object: %w", err)
+	}
+
+	return nil
+}
+
+type subResourceClient struct {
+	client.SubResourceClient
+	client      *Client
+	subResource string
+}
+
+func (c *subResourceClient) Create(context.Context, client.Object, client.Object, ...client.SubResourceCreateOption) error {
+	return fmt.Errorf("creating %s subresource is not supported in dry-run mode", c.subResource)
+}
+
+func (c *subResourceClient) Update(ctx context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+	return c.client.update(ctx, obj, c.subResource)
+}
+
+func (c *subResourceClient) Patch(ctx context.Context, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+	return c.client.update(ctx, obj, c.subResource)
+}
+
+func newChange(action string, k objectKey, diff string) cdPipeApi.PlannedChange {
+	if len(diff) > maxDiffLength {
+		diff = diff[:maxDiffLength] + "..."
+	}
+
+	return cdPipeApi.PlannedChange{
+		Action:    action,
+		Kind:      k.gvk.Kind,
+		Namespace: k.namespace,
+		Name:      k.name,
+		Diff:      diff,
+	}
+}
+
+// makeDiff returns a JSON patch between the objects.
+// Server-side metadata is ignored. Status is compared only for the status subresource and ignored otherwise.
+func makeDiff(current, desired client.Object, subResource string) (string, error) {
+	from, err := diffContent(current, subResource)
+	if err != nil {
+		return "", err
+	}
+
+	to, err := diffContent(desired, subResource)
+	if err != nil {
+		return "", err
+	}
+
+	ops, err := jsonpatch.CreatePatch(from, to)
+	if err != nil {
+		return "", fmt.Errorf("failed to create diff: %w", err)
+	}
+
+	if len(ops) == 0 {
+		return "", nil
+	}
+
+	diff, err := json.Marshal(ops)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal diff: %w", err)
+	}
+
+	return string(diff), nil
+}
+
+func diffContent(obj client.Object, subResource string) ([]byte, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert object: %w", err)
+	}
+
+	if subResource == statusSubResource {
+		content = map[string]interface{}{statusSubResource: content[statusSubResource]}
+	} else {
+		delete(content, statusSubResource)
+	}
+
+	if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"resourceVersion", "managedFields", "generation", "creationTimestamp", "uid"} {
+			delete(metadata, field)
+		}
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal object: %w", err)
+	}
+
+	return data, nil
+}
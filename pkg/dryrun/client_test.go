@@ -0,0 +1,160 @@
+package dryrun
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	return scheme
+}
+
+func TestClient_Create(t *testing.T) {
+	t.Parallel()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+	plan := NewPlan()
+	c := NewClient(fakeClient, plan)
+
+	ns := &corev1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "test-ns"}}
+	require.NoError(t, c.Create(context.Background(), ns))
+
+	err := c.Create(context.Background(), ns.DeepCopy())
+	assert.True(t, k8sErrors.IsAlreadyExists(err))
+
+	assert.True(t, k8sErrors.IsNotFound(fakeClient.Get(context.Background(), client.ObjectKey{Name: "test-ns"}, &corev1.Namespace{})))
+
+	planned := &unstructured.Unstructured{}
+	planned.SetAPIVersion("v1")
+	planned.SetKind("Namespace")
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "test-ns"}, planned))
+	assert.Equal(t, "test-ns", planned.GetName())
+
+	assert.Equal(t, []cdPipeApi.PlannedChange{
+		{Action: ActionCreate, Kind: "Namespace", Name: "test-ns"},
+	}, plan.Changes())
+}
+
+func TestClient_Update(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		update    func(cm *corev1.ConfigMap)
+		wantPlan  []cdPipeApi.PlannedChange
+		wantValue string
+	}{
+		{
+			name: "data is changed",
+			update: func(cm *corev1.ConfigMap) {
+				cm.Data["key"] = "new"
+			},
+			wantPlan: []cdPipeApi.PlannedChange{
+				{
+					Action:    ActionUpdate,
+					Kind:      "ConfigMap",
+					Namespace: "default",
+					Name:      "test-cm",
+					Diff:      `[{"op":"replace","path":"/data/key","value":"new"}]`,
+				},
+			},
+			wantValue: "old",
+		},
+		{
+			name:      "nothing is changed",
+			update:    func(cm *corev1.ConfigMap) {},
+			wantValue: "old",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fakeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(&corev1.ConfigMap{
+				ObjectMeta: metaV1.ObjectMeta{Name: "test-cm", Namespace: "default"},
+				Data:       map[string]string{"key": "old"},
+			}).Build()
+			plan := NewPlan()
+			c := NewClient(fakeClient, plan)
+
+			cm := &corev1.ConfigMap{}
+			require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "test-cm", Namespace: "default"}, cm))
+
+			tt.update(cm)
+			require.NoError(t, c.Update(context.Background(), cm))
+
+			assert.Equal(t, tt.wantPlan, plan.Changes())
+
+			stored := &corev1.ConfigMap{}
+			require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKey{Name: "test-cm", Namespace: "default"}, stored))
+			assert.Equal(t, tt.wantValue, stored.Data["key"])
+		})
+	}
+}
+
+func TestClient_Delete(t *testing.T) {
+	t.Parallel()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(&corev1.Namespace{
+		ObjectMeta: metaV1.ObjectMeta{Name: "test-ns"},
+	}).Build()
+	plan := NewPlan()
+	c := NewClient(fakeClient, plan)
+
+	ns := &corev1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "test-ns"}}
+	require.NoError(t, c.Delete(context.Background(), ns))
+
+	assert.True(t, k8sErrors.IsNotFound(c.Get(context.Background(), client.ObjectKey{Name: "test-ns"}, &corev1.Namespace{})))
+	assert.True(t, k8sErrors.IsNotFound(c.Delete(context.Background(), ns)))
+	assert.NoError(t, fakeClient.Get(context.Background(), client.ObjectKey{Name: "test-ns"}, &corev1.Namespace{}))
+
+	assert.Equal(t, []cdPipeApi.PlannedChange{
+		{Action: ActionDelete, Kind: "Namespace", Name: "test-ns"},
+	}, plan.Changes())
+}
+
+func TestClient_StatusUpdate(t *testing.T) {
+	t.Parallel()
+
+	stage := &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{Name: "test-stage", Namespace: "default"},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(stage).Build()
+	plan := NewPlan()
+	c := NewClient(fakeClient, plan)
+
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(stage), stage))
+
+	stage.Status.Status = "created"
+	require.NoError(t, c.Status().Update(context.Background(), stage))
+
+	changes := plan.Changes()
+	require.Len(t, changes, 1)
+	assert.Equal(t, "Update status", changes[0].Action)
+	assert.Equal(t, `[{"op":"replace","path":"/status/status","value":"created"}]`, changes[0].Diff)
+
+	stored := &cdPipeApi.Stage{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(stage), stored))
+	assert.Empty(t, stored.Status.Status)
+}
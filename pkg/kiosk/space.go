@@ -17,9 +17,10 @@ import (
 const crdNameKey = "name"
 
 type SpaceManager interface {
-	Create(name, account string) error
-	Get(name string) (*unstructured.Unstructured, error)
-	Delete(name string) error
+	Create(ctx context.Context, name, account string, labels map[string]string) error
+	Get(ctx context.Context, name string) (*unstructured.Unstructured, error)
+	Update(ctx context.Context, space *unstructured.Unstructured) error
+	Delete(ctx context.Context, name string) error
 }
 
 type Space struct {
@@ -34,26 +35,32 @@ func InitSpace(c client.Client) SpaceManager {
 	}
 }
 
-func (s Space) Create(name, account string) error {
+// Create creates the kiosk space of the account. The space is labeled with the account and the given labels.
+func (s Space) Create(ctx context.Context, name, account string, labels map[string]string) error {
 	log := s.Log.WithValues(crdNameKey, name)
 	log.Info("creating loft kiosk space")
 
+	spaceLabels := make(map[string]interface{}, len(labels)+1)
+	for k, v := range labels {
+		spaceLabels[k] = v
+	}
+
+	spaceLabels[util.TenantLabelName] = account
+
 	space := &unstructured.Unstructured{}
 	space.Object = map[string]interface{}{
 		"kind":       "Space",
 		"apiVersion": "tenancy.kiosk.sh/v1alpha1",
 		"metadata": map[string]interface{}{
 			crdNameKey: name,
-			"labels": map[string]interface{}{
-				util.TenantLabelName: account,
-			},
+			"labels":   spaceLabels,
 		},
 		"spec": map[string]interface{}{
 			"account": account,
 		},
 	}
 
-	if err := s.Client.Create(context.Background(), space); err != nil {
+	if err := s.Client.Create(ctx, space); err != nil {
 		return fmt.Errorf("failed to create loft kiosk space: %w", err)
 	}
 
@@ -62,7 +69,7 @@ func (s Space) Create(name, account string) error {
 	return nil
 }
 
-func (s Space) Get(name string) (*unstructured.Unstructured, error) {
+func (s Space) Get(ctx context.Context, name string) (*unstructured.Unstructured, error) {
 	log := s.Log.WithValues(crdNameKey, name)
 	log.Info("getting loft kiosk space resource")
 
@@ -72,7 +79,7 @@ func (s Space) Get(name string) (*unstructured.Unstructured, error) {
 		"apiVersion": "tenancy.kiosk.sh/v1alpha1",
 	}
 
-	if err := s.Client.Get(context.Background(), types.NamespacedName{
+	if err := s.Client.Get(ctx, types.NamespacedName{
 		Name: name,
 	}, space); err != nil {
 		return nil, fmt.Errorf("failed to retrieve loft kiosk space: %w", err)
@@ -83,7 +90,20 @@ func (s Space) Get(name string) (*unstructured.Unstructured, error) {
 	return space, nil
 }
 
-func (s Space) Delete(name string) error {
+func (s Space) Update(ctx context.Context, space *unstructured.Unstructured) error {
+	log := s.Log.WithValues(crdNameKey, space.GetName())
+	log.Info("updating loft kiosk space")
+
+	if err := s.Client.Update(ctx, space); err != nil {
+		return fmt.Errorf("failed to update loft kiosk space: %w", err)
+	}
+
+	log.Info("loft kiosk space has been updated")
+
+	return nil
+}
+
+func (s Space) Delete(ctx context.Context, name string) error {
 	log := s.Log.WithValues(crdNameKey, name)
 	log.Info("deleting loft kiosk space")
 
@@ -97,7 +117,7 @@ func (s Space) Delete(name string) error {
 	}
 
 	if err := s.Client.Delete(
-		context.Background(),
+		ctx,
 		space,
 		&client.DeleteOptions{
 			GracePeriodSeconds: pointer.Int64(0),
@@ -19,6 +19,7 @@ import (
 const (
 	name            = "stub-name"
 	account         = "stub-account"
+	stage           = "stub-stage"
 	resourceVersion = "1"
 )
 
@@ -33,6 +34,7 @@ func expectedSpaceInit(t *testing.T) *unstructured.Unstructured {
 			"name": name,
 			"labels": map[string]interface{}{
 				util.TenantLabelName: account,
+				util.StageLabelName:  stage,
 			},
 			"resourceVersion": resourceVersion,
 		},
@@ -74,7 +76,7 @@ func TestSpace_CreateSuccess(t *testing.T) {
 
 	expectedSpace := expectedSpaceInit(t)
 
-	err := space.Create(name, account)
+	err := space.Create(context.Background(), name, account, map[string]string{util.StageLabelName: stage})
 	assert.NoError(t, err)
 
 	emptySpace := &unstructured.Unstructured{}
@@ -96,10 +98,10 @@ func TestSpace_GetSuccess(t *testing.T) {
 
 	expectedSpace := expectedSpaceInit(t)
 
-	err := space.Create(name, account)
+	err := space.Create(context.Background(), name, account, map[string]string{util.StageLabelName: stage})
 	assert.NoError(t, err)
 
-	createdSpace, err := space.Get(name)
+	createdSpace, err := space.Get(context.Background(), name)
 	assert.NoError(t, err)
 
 	assert.Equal(t, expectedSpace, createdSpace)
@@ -108,15 +110,15 @@ func TestSpace_GetSuccess(t *testing.T) {
 func TestSpace_DeleteSuccess(t *testing.T) {
 	space := emptySpaceInit(t)
 
-	err := space.Create(name, account)
+	err := space.Create(context.Background(), name, account, map[string]string{util.StageLabelName: stage})
 	assert.NoError(t, err)
 
-	_, err = space.Get(name)
+	_, err = space.Get(context.Background(), name)
 	assert.NoError(t, err)
 
-	err = space.Delete(name)
+	err = space.Delete(context.Background(), name)
 	assert.NoError(t, err)
 
-	_, err = space.Get(name)
+	_, err = space.Get(context.Background(), name)
 	assert.True(t, k8sErrors.IsNotFound(err))
 }
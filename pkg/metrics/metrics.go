@@ -0,0 +1,64 @@
+// Package metrics contains custom Prometheus metrics of the operator.
+// Metrics are registered in the controller-runtime metrics registry,
+// so they are exposed on the manager metrics endpoint together with the default controller metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsNamespace = "cd_pipeline_operator"
+
+var (
+	// ChainHandlerDuration is a duration of the stage chain handler execution.
+	// It doesn't include the execution of the next handlers of the chain.
+	ChainHandlerDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "chain_handler_duration_seconds",
+			Help:      "Duration of the stage chain handler execution in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"handler"},
+	)
+
+	// ChainHandlerErrors is a number of errors returned by the stage chain handler.
+	// The error is counted only for the handler where it has occurred.
+	ChainHandlerErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "chain_handler_errors_total",
+			Help:      "Number of errors returned by the stage chain handler.",
+		},
+		[]string{"handler"},
+	)
+
+	// CISNotFoundRequeues is a number of stage reconciliations requeued because CodebaseImageStream was not found.
+	CISNotFoundRequeues = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "stage_cis_not_found_requeues_total",
+			Help:      "Number of stage reconciliations requeued because CodebaseImageStream was not found.",
+		},
+	)
+
+	// StageTimeToReady is a time from the stage creation to the moment when the stage becomes ready.
+	StageTimeToReady = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "stage_time_to_ready_seconds",
+			Help:      "Time from the stage creation to the stage Ready condition in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+		},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		ChainHandlerDuration,
+		ChainHandlerErrors,
+		CISNotFoundRequeues,
+		StageTimeToReady,
+	)
+}
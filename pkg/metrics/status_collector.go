@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+const listTimeout = 10 * time.Second
+
+var (
+	stagesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "stages"),
+		"Number of stages by status.",
+		[]string{"namespace", "status"},
+		nil,
+	)
+	cdPipelinesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "cdpipelines"),
+		"Number of CDPipelines by status.",
+		[]string{"namespace", "status"},
+		nil,
+	)
+)
+
+// StatusCollector collects the number of stages and CDPipelines by status.
+// Resources are listed on every scrape, so the client should be backed by the manager cache.
+type StatusCollector struct {
+	client client.Reader
+}
+
+// NewStatusCollector returns a new StatusCollector.
+func NewStatusCollector(c client.Reader) *StatusCollector {
+	return &StatusCollector{client: c}
+}
+
+// Describe implements prometheus.Collector.
+func (c *StatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- stagesDesc
+	ch <- cdPipelinesDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *StatusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), listTimeout)
+	defer cancel()
+
+	stages := &cdPipeApi.StageList{}
+	if err := c.client.List(ctx, stages); err != nil {
+		ch <- prometheus.NewInvalidMetric(stagesDesc, err)
+	} else {
+		counts := make(map[statusKey]int)
+		for i := range stages.Items {
+			counts[statusKey{namespace: stages.Items[i].Namespace, status: stages.Items[i].Status.Status}]++
+		}
+
+		sendCounts(ch, stagesDesc, counts)
+	}
+
+	pipelines := &cdPipeApi.CDPipelineList{}
+	if err := c.client.List(ctx, pipelines); err != nil {
+		ch <- prometheus.NewInvalidMetric(cdPipelinesDesc, err)
+	} else {
+		counts := make(map[statusKey]int)
+		for i := range pipelines.Items {
+			counts[statusKey{namespace: pipelines.Items[i].Namespace, status: pipelines.Items[i].Status.Status}]++
+		}
+
+		sendCounts(ch, cdPipelinesDesc, counts)
+	}
+}
+
+type statusKey struct {
+	namespace string
+	status    string
+}
+
+func sendCounts(ch chan<- prometheus.Metric, desc *prometheus.Desc, counts map[statusKey]int) {
+	for key, count := range counts {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(count), key.namespace, key.status)
+	}
+}
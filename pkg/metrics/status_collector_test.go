@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+func TestStatusCollector_Collect(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	newStage := func(name, status string) *cdPipeApi.Stage {
+		return &cdPipeApi.Stage{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     cdPipeApi.StageStatus{Status: status},
+		}
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newStage("dev", "created"),
+		newStage("qa", "created"),
+		newStage("prod", "failed"),
+		&cdPipeApi.CDPipeline{
+			ObjectMeta: metaV1.ObjectMeta{Name: "pipe", Namespace: "default"},
+			Status:     cdPipeApi.CDPipelineStatus{Status: "created"},
+		},
+	).Build()
+
+	expected := `
+# HELP cd_pipeline_operator_cdpipelines Number of CDPipelines by status.
+# TYPE cd_pipeline_operator_cdpipelines gauge
+cd_pipeline_operator_cdpipelines{namespace="default",status="created"} 1
+# HELP cd_pipeline_operator_stages Number of stages by status.
+# TYPE cd_pipeline_operator_stages gauge
+cd_pipeline_operator_stages{namespace="default",status="created"} 2
+cd_pipeline_operator_stages{namespace="default",status="failed"} 1
+`
+
+	require.NoError(t, testutil.CollectAndCompare(NewStatusCollector(k8sClient), strings.NewReader(expected)))
+}
+
+func TestStatusCollector_Collect_ListError(t *testing.T) {
+	t.Parallel()
+
+	k8sClient := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+
+	require.Error(t, testutil.CollectAndCompare(NewStatusCollector(k8sClient), strings.NewReader("")))
+}
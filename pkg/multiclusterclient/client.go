@@ -0,0 +1,195 @@
+package multiclusterclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+const (
+	// KubeConfigSecretKey is a Secret key that contains kubeconfig of the external cluster.
+	KubeConfigSecretKey = "config"
+
+	// ServerSecretKey is a Secret key that contains API server URL of the external cluster.
+	// It is used together with TokenSecretKey if KubeConfigSecretKey is not set.
+	ServerSecretKey = "server"
+
+	// TokenSecretKey is a Secret key that contains bearer token for the external cluster.
+	TokenSecretKey = "token"
+
+	// CASecretKey is an optional Secret key that contains CA certificate of the external cluster.
+	CASecretKey = "ca.crt"
+)
+
+// ClientProvider provides clients for the external clusters.
+// External clusters are registered with the Cluster resource,
+// which references the Secret with the cluster credentials.
+type ClientProvider struct {
+	client    client.Client
+	newClient func(config *rest.Config, options client.Options) (client.Client, error)
+	cache     *clientCache
+}
+
+// clientCache keeps the clients of the external clusters, so the client and its RESTMapper
+// are not created on every reconciliation. The client is created again when the cluster Secret is changed.
+type clientCache struct {
+	mu      sync.Mutex
+	clients map[client.ObjectKey]cachedClient
+}
+
+type cachedClient struct {
+	secretName      string
+	secretUID       types.UID
+	resourceVersion string
+	client          client.Client
+}
+
+// defaultCache is shared by the providers, because they are created for every stage reconciliation.
+var defaultCache = newClientCache()
+
+func newClientCache() *clientCache {
+	return &clientCache{clients: make(map[client.ObjectKey]cachedClient)}
+}
+
+func (c *clientCache) get(key client.ObjectKey, secret *corev1.Secret) (client.Client, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.clients[key]
+	if !ok ||
+		cached.secretName != secret.Name ||
+		cached.secretUID != secret.UID ||
+		cached.resourceVersion != secret.ResourceVersion {
+		return nil, false
+	}
+
+	return cached.client, true
+}
+
+func (c *clientCache) set(key client.ObjectKey, secret *corev1.Secret, cl client.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clients[key] = cachedClient{
+		secretName:      secret.Name,
+		secretUID:       secret.UID,
+		resourceVersion: secret.ResourceVersion,
+		client:          cl,
+	}
+}
+
+// NewClientProvider returns a new instance of ClientProvider.
+func NewClientProvider(c client.Client) *ClientProvider {
+	return &ClientProvider{
+		client:    c,
+		newClient: client.New,
+		cache:     defaultCache,
+	}
+}
+
+// GetClusterClient returns a client for the cluster with the given name.
+// Cluster resource and its Secret are searched in the given namespace.
+// The client is reused until the cluster Secret is changed.
+func (p *ClientProvider) GetClusterClient(ctx context.Context, namespace, clusterName string) (client.Client, error) {
+	cluster := &cdPipeApi.Cluster{}
+	if err := p.client.Get(ctx, client.ObjectKey{
+		Namespace: namespace,
+		Name:      clusterName,
+	}, cluster); err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s: %w", clusterName, err)
+	}
+
+	secret, err := getSecret(ctx, p.client, namespace, cluster.Spec.SecretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s config: %w", clusterName, err)
+	}
+
+	key := client.ObjectKeyFromObject(cluster)
+
+	if cl, ok := p.cache.get(key, secret); ok {
+		return cl, nil
+	}
+
+	restConfig, err := RestConfigFromSecret(secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s config: %w", clusterName, err)
+	}
+
+	mapper, err := apiutil.NewDynamicRESTMapper(restConfig, apiutil.WithLazyDiscovery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cluster %s rest mapper: %w", clusterName, err)
+	}
+
+	cl, err := p.newClient(restConfig, client.Options{
+		Scheme: p.client.Scheme(),
+		Mapper: mapper,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cluster %s client: %w", clusterName, err)
+	}
+
+	p.cache.set(key, secret, cl)
+
+	return cl, nil
+}
+
+// GetRestConfig returns rest config from the Secret with the given name.
+func GetRestConfig(ctx context.Context, c client.Reader, namespace, secretName string) (*rest.Config, error) {
+	secret, err := getSecret(ctx, c, namespace, secretName)
+	if err != nil {
+		return nil, err
+	}
+
+	return RestConfigFromSecret(secret)
+}
+
+func getSecret(ctx context.Context, c client.Reader, namespace, secretName string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{
+		Namespace: namespace,
+		Name:      secretName,
+	}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %s: %w", secretName, err)
+	}
+
+	return secret, nil
+}
+
+// RestConfigFromSecret creates rest config from the cluster Secret.
+// Secret should contain kubeconfig in the KubeConfigSecretKey key
+// or API server URL and bearer token in the ServerSecretKey and TokenSecretKey keys.
+func RestConfigFromSecret(secret *corev1.Secret) (*rest.Config, error) {
+	if kubeConfig, ok := secret.Data[KubeConfigSecretKey]; ok {
+		restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse kubeconfig: %w", err)
+		}
+
+		return restConfig, nil
+	}
+
+	server := string(secret.Data[ServerSecretKey])
+	token := string(secret.Data[TokenSecretKey])
+
+	if server == "" || token == "" {
+		return nil, errors.New("secret should contain kubeconfig or server and token")
+	}
+
+	return &rest.Config{
+		Host:        server,
+		BearerToken: token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: secret.Data[CASecretKey],
+		},
+	}, nil
+}
@@ -0,0 +1,243 @@
+package multiclusterclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+const testKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://external-cluster:6443
+  name: external
+contexts:
+- context:
+    cluster: external
+    user: external
+  name: external
+current-context: external
+users:
+- name: external
+  user:
+    token: kube-token
+`
+
+func TestClientProvider_GetClusterClient(t *testing.T) {
+	t.Parallel()
+
+	const ns = "default"
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	cluster := &cdPipeApi.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "external",
+			Namespace: ns,
+		},
+		Spec: cdPipeApi.ClusterSpec{
+			SecretName: "external-secret",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		clusterName string
+		objects     []client.Object
+		wantErr     require.ErrorAssertionFunc
+	}{
+		{
+			name:        "should create client from kubeconfig",
+			clusterName: "external",
+			objects: []client.Object{
+				cluster,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "external-secret",
+						Namespace: ns,
+					},
+					Data: map[string][]byte{
+						KubeConfigSecretKey: []byte(testKubeConfig),
+					},
+				},
+			},
+			wantErr: require.NoError,
+		},
+		{
+			name:        "should create client from bearer token",
+			clusterName: "external",
+			objects: []client.Object{
+				cluster,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "external-secret",
+						Namespace: ns,
+					},
+					Data: map[string][]byte{
+						ServerSecretKey: []byte("https://external-cluster:6443"),
+						TokenSecretKey:  []byte("token"),
+					},
+				},
+			},
+			wantErr: require.NoError,
+		},
+		{
+			name:        "should fail if cluster doesn't exist",
+			clusterName: "external",
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "failed to get cluster external")
+			},
+		},
+		{
+			name:        "should fail if secret doesn't exist",
+			clusterName: "external",
+			objects:     []client.Object{cluster},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "failed to get secret external-secret")
+			},
+		},
+		{
+			name:        "should fail if secret doesn't contain credentials",
+			clusterName: "external",
+			objects: []client.Object{
+				cluster,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "external-secret",
+						Namespace: ns,
+					},
+				},
+			},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "secret should contain kubeconfig or server and token")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := NewClientProvider(fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build())
+			p.cache = newClientCache()
+
+			cl, err := p.GetClusterClient(context.Background(), ns, tt.clusterName)
+			tt.wantErr(t, err)
+
+			if err == nil {
+				assert.NotNil(t, cl)
+			}
+		})
+	}
+}
+
+func TestClientProvider_GetClusterClient_Cache(t *testing.T) {
+	t.Parallel()
+
+	const ns = "default"
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&cdPipeApi.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "external",
+				Namespace: ns,
+			},
+			Spec: cdPipeApi.ClusterSpec{
+				SecretName: "external-secret",
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "external-secret",
+				Namespace: ns,
+			},
+			Data: map[string][]byte{
+				KubeConfigSecretKey: []byte(testKubeConfig),
+			},
+		},
+	).Build()
+
+	created := 0
+
+	p := NewClientProvider(k8sClient)
+	p.cache = newClientCache()
+	p.newClient = func(config *rest.Config, options client.Options) (client.Client, error) {
+		created++
+
+		return client.New(config, options)
+	}
+
+	first, err := p.GetClusterClient(context.Background(), ns, "external")
+	require.NoError(t, err)
+
+	second, err := p.GetClusterClient(context.Background(), ns, "external")
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, created)
+
+	secret := &corev1.Secret{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: "external-secret"}, secret))
+
+	secret.Data[TokenSecretKey] = []byte("new-token")
+	require.NoError(t, k8sClient.Update(context.Background(), secret))
+
+	third, err := p.GetClusterClient(context.Background(), ns, "external")
+	require.NoError(t, err)
+
+	assert.NotSame(t, first, third)
+	assert.Equal(t, 2, created)
+}
+
+func TestRestConfigFromSecret(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := RestConfigFromSecret(&corev1.Secret{
+		Data: map[string][]byte{
+			KubeConfigSecretKey: []byte(testKubeConfig),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://external-cluster:6443", cfg.Host)
+	assert.Equal(t, "kube-token", cfg.BearerToken)
+
+	cfg, err = RestConfigFromSecret(&corev1.Secret{
+		Data: map[string][]byte{
+			ServerSecretKey: []byte("https://external-cluster:6443"),
+			TokenSecretKey:  []byte("token"),
+			CASecretKey:     []byte("ca"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://external-cluster:6443", cfg.Host)
+	assert.Equal(t, "token", cfg.BearerToken)
+	assert.Equal(t, []byte("ca"), cfg.CAData)
+
+	_, err = RestConfigFromSecret(&corev1.Secret{
+		Data: map[string][]byte{
+			KubeConfigSecretKey: []byte("invalid"),
+		},
+	})
+	require.Error(t, err)
+}
@@ -29,7 +29,39 @@ func (f StageModifierFunc) Apply(ctx context.Context, stage *cdPipeApi.Stage) (b
 	return f(ctx, stage)
 }
 
-// StageBatchModifier is a modifier that applies a list of modifiers.
+// StageModifiers is a list of modifiers that are applied to the stage object in memory.
+// It is used by the mutating webhook to set stage defaults at admission time.
+type StageModifiers []StageModifier
+
+// NewStageModifiersAll returns all the stage modifiers.
+func NewStageModifiersAll(k8sClient client.Client, scheme *runtime.Scheme) StageModifiers {
+	return StageModifiers{
+		StageModifierFunc(setStageLabel),
+		StageModifierFunc(updateStageNamespaceSpec),
+		newStageOwnerRefModifier(k8sClient, scheme),
+	}
+}
+
+// Apply applies all the modifiers to the stage without saving it.
+// It returns true if at least one modifier has changed the stage.
+func (m StageModifiers) Apply(ctx context.Context, stage *cdPipeApi.Stage) (bool, error) {
+	changed := false
+
+	for _, modifier := range m {
+		modified, err := modifier.Apply(ctx, stage)
+		if err != nil {
+			return false, fmt.Errorf("failed to apply modifier: %w", err)
+		}
+
+		if modified {
+			changed = true
+		}
+	}
+
+	return changed, nil
+}
+
+// StageBatchModifier is a modifier that applies a list of modifiers and patches the stage.
 type StageBatchModifier struct {
 	k8sClient client.Writer
 	modifiers []StageModifier
@@ -42,29 +74,16 @@ func NewStageBatchModifier(k8sClient client.Client, modifiers []StageModifier) *
 
 // NewStageBatchModifierAll returns a new instance of StageBatchModifier with all the modifiers.
 func NewStageBatchModifierAll(k8sClient client.Client, scheme *runtime.Scheme) *StageBatchModifier {
-	modifiers := []StageModifier{
-		StageModifierFunc(setStageLabel),
-		StageModifierFunc(updateStageNamespaceSpec),
-		newStageOwnerRefModifier(k8sClient, scheme),
-	}
-
-	return &StageBatchModifier{k8sClient: k8sClient, modifiers: modifiers}
+	return &StageBatchModifier{k8sClient: k8sClient, modifiers: NewStageModifiersAll(k8sClient, scheme)}
 }
 
 // Apply applies all the modifiers to the stage.
 func (m *StageBatchModifier) Apply(ctx context.Context, stage *cdPipeApi.Stage) (bool, error) {
 	patch := client.MergeFrom(stage.DeepCopy())
-	needToPatch := false
 
-	for _, modifier := range m.modifiers {
-		changed, err := modifier.Apply(ctx, stage)
-		if err != nil {
-			return false, fmt.Errorf("failed to apply modifier: %w", err)
-		}
-
-		if changed {
-			needToPatch = true
-		}
+	needToPatch, err := StageModifiers(m.modifiers).Apply(ctx, stage)
+	if err != nil {
+		return false, err
 	}
 
 	if needToPatch {
@@ -495,3 +495,47 @@ func TestNewStageBatchModifier(t *testing.T) {
 	got := NewStageBatchModifier(fake.NewClientBuilder().Build(), []StageModifier{})
 	assert.NotNil(t, got)
 }
+
+func TestStageModifiers_Apply(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	pipeline := &cdPipeApi.CDPipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pipeline",
+			Namespace: "default",
+			UID:       types.UID("pipeline-uid"),
+		},
+	}
+
+	stage := &cdPipeApi.Stage{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-stage",
+			Namespace: "default",
+		},
+		Spec: cdPipeApi.StageSpec{
+			CdPipeline: "test-pipeline",
+		},
+	}
+
+	modifiers := NewStageModifiersAll(fake.NewClientBuilder().WithScheme(scheme).WithObjects(pipeline).Build(), scheme)
+	ctx := logr.NewContext(context.Background(), logr.Discard())
+
+	changed, err := modifiers.Apply(ctx, stage)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "test-pipeline", stage.Labels[cdPipeApi.StageCdPipelineLabelName])
+	assert.Equal(t, util.GenerateNamespaceName(stage), stage.Spec.Namespace)
+	require.Len(t, stage.OwnerReferences, 1)
+	assert.Equal(t, pipeline.UID, stage.OwnerReferences[0].UID)
+
+	changed, err = modifiers.Apply(ctx, stage)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	_, err = StageModifiers{StageModifierFunc(setStageLabel)}.Apply(ctx, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to apply modifier")
+}
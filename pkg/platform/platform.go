@@ -3,6 +3,7 @@ package platform
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -11,6 +12,7 @@ const (
 	Kubernetes         = "kubernetes"
 	KioskEnabledEnv    = "KIOSK_ENABLED"
 	ManageNamespaceEnv = "MANAGE_NAMESPACE"
+	SharedNamespaceEnv = "SHARED_NAMESPACES"
 )
 
 func GetPlatformTypeEnv() string {
@@ -63,3 +65,21 @@ func ManageNamespace() bool {
 
 	return b
 }
+
+// IsSharedNamespace returns true if the namespace is in the comma-separated list
+// of the environment variable SHARED_NAMESPACES.
+// Shared namespaces are not created for the stage, but the operator can configure them for any stage.
+func IsSharedNamespace(name string) bool {
+	namespaces, ok := os.LookupEnv(SharedNamespaceEnv)
+	if !ok {
+		return false
+	}
+
+	for _, ns := range strings.Split(namespaces, ",") {
+		if strings.TrimSpace(ns) == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -143,3 +143,39 @@ func TestManageNamespace(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSharedNamespace(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T)
+		want    bool
+	}{
+		{
+			name: "namespace is shared",
+			prepare: func(t *testing.T) {
+				t.Setenv(SharedNamespaceEnv, "team-a, shared")
+			},
+			want: true,
+		},
+		{
+			name: "namespace is not shared",
+			prepare: func(t *testing.T) {
+				t.Setenv(SharedNamespaceEnv, "team-a")
+			},
+			want: false,
+		},
+		{
+			name: "shared namespaces are not set",
+			prepare: func(t *testing.T) {
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare(t)
+			assert.Equal(t, tt.want, IsSharedNamespace("shared"))
+		})
+	}
+}
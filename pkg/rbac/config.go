@@ -0,0 +1,96 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacApi "k8s.io/api/rbac/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+const (
+	// StageRoleBindingsConfigMapName is a name of the ConfigMap that contains role bindings for the stage namespaces.
+	// The ConfigMap is configured per tenant: it is read from the namespace of the stage,
+	// so it can be changed by the same users who can change the stage role bindings in the stage spec.
+	// Each key is a stage name (spec.name) with a YAML list of RoleBindingTemplate,
+	// the DefaultRoleBindingsKey key is used for the stages without their own key.
+	StageRoleBindingsConfigMapName = "cd-pipeline-operator-rbac"
+
+	// DefaultRoleBindingsKey is a key of the role bindings which are used for all stages by default.
+	DefaultRoleBindingsKey = "default"
+
+	// JenkinsRoleBindingsKey is a key of the role bindings for Jenkins which are used for all stages.
+	// ServiceAccount subjects without namespace are bound from the stage namespace.
+	// Like DefaultRoleBindingsKey, it can't be used as a key of the stage role bindings.
+	JenkinsRoleBindingsKey = "jenkins"
+)
+
+// GetStageRoleBindings returns role bindings that should be created in the stage target namespace.
+// Role bindings from the stage spec have the highest priority,
+// then role bindings for the stage name and the default role bindings from the StageRoleBindingsConfigMapName ConfigMap are used.
+// It returns nil if role bindings are not configured.
+func GetStageRoleBindings(ctx context.Context, c client.Client, stage *cdPipeApi.Stage) ([]cdPipeApi.RoleBindingTemplate, error) {
+	if len(stage.Spec.RoleBindings) > 0 {
+		return stage.Spec.RoleBindings, nil
+	}
+
+	return getConfigMapRoleBindings(ctx, c, stage.Namespace, stage.Spec.Name, DefaultRoleBindingsKey)
+}
+
+// GetJenkinsRoleBindings returns role bindings for Jenkins that should be created in the stage target namespace.
+// They are taken from the JenkinsRoleBindingsKey key of the StageRoleBindingsConfigMapName ConfigMap.
+// It returns nil if role bindings are not configured.
+func GetJenkinsRoleBindings(ctx context.Context, c client.Client, stage *cdPipeApi.Stage) ([]cdPipeApi.RoleBindingTemplate, error) {
+	roleBindings, err := getConfigMapRoleBindings(ctx, c, stage.Namespace, JenkinsRoleBindingsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range roleBindings {
+		for j := range roleBindings[i].Subjects {
+			subject := &roleBindings[i].Subjects[j]
+			if subject.Kind == rbacApi.ServiceAccountKind && subject.Namespace == "" {
+				subject.Namespace = stage.Namespace
+			}
+		}
+	}
+
+	return roleBindings, nil
+}
+
+// getConfigMapRoleBindings returns role bindings from the first key of the StageRoleBindingsConfigMapName ConfigMap
+// which is set. It returns nil if the ConfigMap or the keys don't exist.
+func getConfigMapRoleBindings(ctx context.Context, c client.Client, namespace string, keys ...string) ([]cdPipeApi.RoleBindingTemplate, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{
+		Namespace: namespace,
+		Name:      StageRoleBindingsConfigMapName,
+	}, cm); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to get %s ConfigMap: %w", StageRoleBindingsConfigMapName, err)
+	}
+
+	for _, key := range keys {
+		raw, ok := cm.Data[key]
+		if !ok {
+			continue
+		}
+
+		var roleBindings []cdPipeApi.RoleBindingTemplate
+		if err := yaml.Unmarshal([]byte(raw), &roleBindings); err != nil {
+			return nil, fmt.Errorf("failed to parse %s key of %s ConfigMap: %w", key, StageRoleBindingsConfigMapName, err)
+		}
+
+		return roleBindings, nil
+	}
+
+	return nil, nil
+}
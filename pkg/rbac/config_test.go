@@ -0,0 +1,197 @@
+package rbac
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacApi "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+func TestGetStageRoleBindings(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	newStage := func(name string, roleBindings ...cdPipeApi.RoleBindingTemplate) *cdPipeApi.Stage {
+		return &cdPipeApi.Stage{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pipeline-" + name,
+				Namespace: "default",
+			},
+			Spec: cdPipeApi.StageSpec{
+				Name:         name,
+				RoleBindings: roleBindings,
+			},
+		}
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      StageRoleBindingsConfigMapName,
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			DefaultRoleBindingsKey: `
+- name: developers
+  subjects:
+    - kind: Group
+      apiGroup: rbac.authorization.k8s.io
+      name: developers
+  roleRef:
+    kind: ClusterRole
+    apiGroup: rbac.authorization.k8s.io
+    name: edit
+`,
+			"prod": `
+- name: developers
+  subjects:
+    - kind: Group
+      apiGroup: rbac.authorization.k8s.io
+      name: developers
+  roleRef:
+    kind: ClusterRole
+    apiGroup: rbac.authorization.k8s.io
+    name: view
+`,
+			"broken": "name: developers",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		stage    *cdPipeApi.Stage
+		objects  []client.Object
+		wantRole string
+		wantLen  int
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			name: "role bindings from stage",
+			stage: newStage("prod", cdPipeApi.RoleBindingTemplate{
+				Name:    "admins",
+				RoleRef: &rbacApi.RoleRef{Kind: ClusterRoleKind, Name: "admin"},
+			}),
+			objects:  []client.Object{configMap},
+			wantRole: "admin",
+			wantLen:  1,
+			wantErr:  require.NoError,
+		},
+		{
+			name:     "role bindings for stage name from ConfigMap",
+			stage:    newStage("prod"),
+			objects:  []client.Object{configMap},
+			wantRole: "view",
+			wantLen:  1,
+			wantErr:  require.NoError,
+		},
+		{
+			name:     "default role bindings from ConfigMap",
+			stage:    newStage("dev"),
+			objects:  []client.Object{configMap},
+			wantRole: "edit",
+			wantLen:  1,
+			wantErr:  require.NoError,
+		},
+		{
+			name:    "ConfigMap doesn't exist",
+			stage:   newStage("dev"),
+			wantLen: 0,
+			wantErr: require.NoError,
+		},
+		{
+			name:    "invalid ConfigMap data",
+			stage:   newStage("broken"),
+			objects: []client.Object{configMap},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "failed to parse broken key")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+
+			got, err := GetStageRoleBindings(context.Background(), k8sClient, tt.stage)
+			tt.wantErr(t, err)
+
+			if err != nil {
+				return
+			}
+
+			assert.Len(t, got, tt.wantLen)
+
+			if tt.wantLen > 0 {
+				assert.Equal(t, tt.wantRole, got[0].RoleRef.Name)
+			}
+		})
+	}
+}
+
+func TestGetJenkinsRoleBindings(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	stage := &cdPipeApi.Stage{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pipeline-dev",
+			Namespace: "default",
+		},
+		Spec: cdPipeApi.StageSpec{
+			Name: "dev",
+		},
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      StageRoleBindingsConfigMapName,
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			JenkinsRoleBindingsKey: `
+- name: jenkins-deployer
+  subjects:
+    - kind: ServiceAccount
+      name: jenkins
+    - kind: ServiceAccount
+      name: deployer
+      namespace: ci
+  roleRef:
+    kind: ClusterRole
+    name: edit
+`,
+		},
+	}
+
+	got, err := GetJenkinsRoleBindings(
+		context.Background(),
+		fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build(),
+		stage,
+	)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, []rbacApi.Subject{
+		{Kind: rbacApi.ServiceAccountKind, Name: "jenkins", Namespace: "default"},
+		{Kind: rbacApi.ServiceAccountKind, Name: "deployer", Namespace: "ci"},
+	}, got[0].Subjects)
+
+	got, err = GetJenkinsRoleBindings(context.Background(), fake.NewClientBuilder().WithScheme(scheme).Build(), stage)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
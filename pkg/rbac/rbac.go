@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
 	rbacApi "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -15,15 +17,32 @@ import (
 const (
 	crNameLogKey    = "name"
 	ClusterRoleKind = "ClusterRole"
+	RoleKind        = "Role"
+
+	// ManagedByLabelName is a label of the RBAC resources created or updated by the operator.
+	ManagedByLabelName = "app.kubernetes.io/managed-by"
+	// ManagedByLabelValue is a value of the ManagedByLabelName label.
+	ManagedByLabelValue = "edp-cd-pipeline-operator"
 )
 
 type Manager interface {
-	GetRoleBinding(name, namespace string) (*rbacApi.RoleBinding, error)
+	GetRoleBinding(ctx context.Context, name, namespace string) (*rbacApi.RoleBinding, error)
 	RoleBindingExists(ctx context.Context, name, namespace string) (bool, error)
-	CreateRoleBinding(name, namespace string, subjects []rbacApi.Subject, roleRef rbacApi.RoleRef) error
+	CreateRoleBinding(ctx context.Context, name, namespace string, subjects []rbacApi.Subject, roleRef rbacApi.RoleRef) error
 	CreateRoleBindingIfNotExists(ctx context.Context, name, namespace string, subjects []rbacApi.Subject, roleRef rbacApi.RoleRef) error
-	GetRole(name, namespace string) (*rbacApi.Role, error)
-	CreateRole(name, namespace string, rules []rbacApi.PolicyRule) error
+	GetRole(ctx context.Context, name, namespace string) (*rbacApi.Role, error)
+	CreateRole(ctx context.Context, name, namespace string, rules []rbacApi.PolicyRule) error
+	CreateRoleIfNotExists(ctx context.Context, name, namespace string, rules []rbacApi.PolicyRule) error
+	CreateOrUpdateRoleBinding(
+		ctx context.Context,
+		name, namespace string,
+		subjects []rbacApi.Subject,
+		roleRef rbacApi.RoleRef,
+		labels map[string]string,
+	) error
+	CreateOrUpdateRole(ctx context.Context, name, namespace string, rules []rbacApi.PolicyRule, labels map[string]string) error
+	PruneRoleBindings(ctx context.Context, namespace string, labels map[string]string, keep []string) error
+	PruneRoles(ctx context.Context, namespace string, labels map[string]string, keep []string) error
 }
 
 type KubernetesRbac struct {
@@ -38,12 +57,12 @@ func NewRbacManager(c client.Client, log logr.Logger) Manager {
 	}
 }
 
-func (s KubernetesRbac) GetRoleBinding(name, namespace string) (*rbacApi.RoleBinding, error) {
+func (s KubernetesRbac) GetRoleBinding(ctx context.Context, name, namespace string) (*rbacApi.RoleBinding, error) {
 	log := s.log.WithValues(crNameLogKey, name, "namespace", namespace)
 	log.Info("getting role binding")
 
 	rb := &rbacApi.RoleBinding{}
-	if err := s.client.Get(context.Background(), types.NamespacedName{
+	if err := s.client.Get(ctx, types.NamespacedName{
 		Namespace: namespace,
 		Name:      name,
 	}, rb); err != nil {
@@ -76,7 +95,12 @@ func (s KubernetesRbac) RoleBindingExists(ctx context.Context, name, namespace s
 	return true, nil
 }
 
-func (s KubernetesRbac) CreateRoleBinding(name, namespace string, subjects []rbacApi.Subject, roleRef rbacApi.RoleRef) error {
+func (s KubernetesRbac) CreateRoleBinding(
+	ctx context.Context,
+	name, namespace string,
+	subjects []rbacApi.Subject,
+	roleRef rbacApi.RoleRef,
+) error {
 	log := s.log.WithValues(crNameLogKey, name)
 	log.Info("creating rolebinding")
 
@@ -88,7 +112,7 @@ func (s KubernetesRbac) CreateRoleBinding(name, namespace string, subjects []rba
 		Subjects: subjects,
 		RoleRef:  roleRef,
 	}
-	if err := s.client.Create(context.Background(), rb); err != nil {
+	if err := s.client.Create(ctx, rb); err != nil {
 		return fmt.Errorf("failed to create role binding: %w", err)
 	}
 
@@ -114,15 +138,15 @@ func (s KubernetesRbac) CreateRoleBindingIfNotExists(
 		return nil
 	}
 
-	return s.CreateRoleBinding(name, namespace, subjects, roleRef)
+	return s.CreateRoleBinding(ctx, name, namespace, subjects, roleRef)
 }
 
-func (s KubernetesRbac) GetRole(name, namespace string) (*rbacApi.Role, error) {
+func (s KubernetesRbac) GetRole(ctx context.Context, name, namespace string) (*rbacApi.Role, error) {
 	log := s.log.WithValues(crNameLogKey, name, "namespace", namespace)
 	log.Info("getting role binding")
 
 	r := &rbacApi.Role{}
-	if err := s.client.Get(context.Background(), types.NamespacedName{
+	if err := s.client.Get(ctx, types.NamespacedName{
 		Namespace: namespace,
 		Name:      name,
 	}, r); err != nil {
@@ -132,7 +156,7 @@ func (s KubernetesRbac) GetRole(name, namespace string) (*rbacApi.Role, error) {
 	return r, nil
 }
 
-func (s KubernetesRbac) CreateRole(name, namespace string, rules []rbacApi.PolicyRule) error {
+func (s KubernetesRbac) CreateRole(ctx context.Context, name, namespace string, rules []rbacApi.PolicyRule) error {
 	log := s.log.WithValues(crNameLogKey, name)
 	log.Info("creating role")
 
@@ -143,7 +167,7 @@ func (s KubernetesRbac) CreateRole(name, namespace string, rules []rbacApi.Polic
 		},
 		Rules: rules,
 	}
-	if err := s.client.Create(context.Background(), r); err != nil {
+	if err := s.client.Create(ctx, r); err != nil {
 		return fmt.Errorf("failed to create role: %w", err)
 	}
 
@@ -151,3 +175,217 @@ func (s KubernetesRbac) CreateRole(name, namespace string, rules []rbacApi.Polic
 
 	return nil
 }
+
+// CreateRoleIfNotExists creates a Role if it does not exist in the given namespace.
+func (s KubernetesRbac) CreateRoleIfNotExists(ctx context.Context, name, namespace string, rules []rbacApi.PolicyRule) error {
+	log := s.log.WithValues(crNameLogKey, name)
+	log.Info("Checking if Role exists")
+
+	err := s.client.Get(ctx, types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}, &rbacApi.Role{})
+	if err == nil {
+		log.Info("Role exists")
+
+		return nil
+	}
+
+	if !k8sErrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get role: %w", err)
+	}
+
+	return s.CreateRole(ctx, name, namespace, rules)
+}
+
+// CreateOrUpdateRoleBinding creates a RoleBinding or updates its subjects and labels if they differ.
+// RoleBinding is recreated if its roleRef differs because roleRef is immutable.
+// RoleBinding is labeled with the given labels and the ManagedByLabelName label.
+func (s KubernetesRbac) CreateOrUpdateRoleBinding(
+	ctx context.Context,
+	name, namespace string,
+	subjects []rbacApi.Subject,
+	roleRef rbacApi.RoleRef,
+	labels map[string]string,
+) error {
+	log := s.log.WithValues(crNameLogKey, name, "namespace", namespace)
+
+	rb := &rbacApi.RoleBinding{}
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, rb); err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get role binding: %w", err)
+		}
+
+		return s.createLabeledRoleBinding(ctx, name, namespace, subjects, roleRef, labels)
+	}
+
+	if rb.RoleRef != roleRef {
+		log.Info("RoleBinding roleRef has been changed, recreating RoleBinding")
+
+		if err := s.client.Delete(ctx, rb); err != nil && !k8sErrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete role binding: %w", err)
+		}
+
+		return s.createLabeledRoleBinding(ctx, name, namespace, subjects, roleRef, labels)
+	}
+
+	newLabels := managedLabels(rb.Labels, labels)
+
+	if equality.Semantic.DeepEqual(rb.Subjects, subjects) && equality.Semantic.DeepEqual(rb.Labels, newLabels) {
+		return nil
+	}
+
+	rb.Subjects = subjects
+	rb.Labels = newLabels
+
+	if err := s.client.Update(ctx, rb); err != nil {
+		return fmt.Errorf("failed to update role binding: %w", err)
+	}
+
+	log.Info("RoleBinding has been updated")
+
+	return nil
+}
+
+func (s KubernetesRbac) createLabeledRoleBinding(
+	ctx context.Context,
+	name, namespace string,
+	subjects []rbacApi.Subject,
+	roleRef rbacApi.RoleRef,
+	labels map[string]string,
+) error {
+	rb := &rbacApi.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    managedLabels(nil, labels),
+		},
+		Subjects: subjects,
+		RoleRef:  roleRef,
+	}
+
+	if err := s.client.Create(ctx, rb); err != nil {
+		return fmt.Errorf("failed to create role binding: %w", err)
+	}
+
+	s.log.Info("RoleBinding has been created", crNameLogKey, name, "namespace", namespace)
+
+	return nil
+}
+
+// CreateOrUpdateRole creates a Role or updates its rules and labels if they differ.
+// Role is labeled with the given labels and the ManagedByLabelName label.
+func (s KubernetesRbac) CreateOrUpdateRole(
+	ctx context.Context,
+	name, namespace string,
+	rules []rbacApi.PolicyRule,
+	labels map[string]string,
+) error {
+	log := s.log.WithValues(crNameLogKey, name, "namespace", namespace)
+
+	role := &rbacApi.Role{}
+
+	err := s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, role)
+	if k8sErrors.IsNotFound(err) {
+		role = &rbacApi.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    managedLabels(nil, labels),
+			},
+			Rules: rules,
+		}
+
+		if err = s.client.Create(ctx, role); err != nil {
+			return fmt.Errorf("failed to create role: %w", err)
+		}
+
+		log.Info("Role has been created")
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to get role: %w", err)
+	}
+
+	newLabels := managedLabels(role.Labels, labels)
+
+	if equality.Semantic.DeepEqual(role.Rules, rules) && equality.Semantic.DeepEqual(role.Labels, newLabels) {
+		return nil
+	}
+
+	role.Rules = rules
+	role.Labels = newLabels
+
+	if err = s.client.Update(ctx, role); err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+
+	log.Info("Role has been updated")
+
+	return nil
+}
+
+// PruneRoleBindings deletes RoleBindings managed by the operator which have the given labels
+// and are not in the keep list.
+func (s KubernetesRbac) PruneRoleBindings(ctx context.Context, namespace string, labels map[string]string, keep []string) error {
+	list := &rbacApi.RoleBindingList{}
+	if err := s.client.List(ctx, list, client.InNamespace(namespace), client.MatchingLabels(managedLabels(nil, labels))); err != nil {
+		return fmt.Errorf("failed to list role bindings: %w", err)
+	}
+
+	for i := range list.Items {
+		if slices.Contains(keep, list.Items[i].Name) {
+			continue
+		}
+
+		if err := s.client.Delete(ctx, &list.Items[i]); err != nil && !k8sErrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete role binding %s: %w", list.Items[i].Name, err)
+		}
+
+		s.log.Info("Orphaned RoleBinding has been deleted", crNameLogKey, list.Items[i].Name, "namespace", namespace)
+	}
+
+	return nil
+}
+
+// PruneRoles deletes Roles managed by the operator which have the given labels
+// and are not in the keep list.
+func (s KubernetesRbac) PruneRoles(ctx context.Context, namespace string, labels map[string]string, keep []string) error {
+	list := &rbacApi.RoleList{}
+	if err := s.client.List(ctx, list, client.InNamespace(namespace), client.MatchingLabels(managedLabels(nil, labels))); err != nil {
+		return fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	for i := range list.Items {
+		if slices.Contains(keep, list.Items[i].Name) {
+			continue
+		}
+
+		if err := s.client.Delete(ctx, &list.Items[i]); err != nil && !k8sErrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete role %s: %w", list.Items[i].Name, err)
+		}
+
+		s.log.Info("Orphaned Role has been deleted", crNameLogKey, list.Items[i].Name, "namespace", namespace)
+	}
+
+	return nil
+}
+
+// managedLabels returns a copy of the current labels merged with the given labels and the ManagedByLabelName label.
+func managedLabels(current, labels map[string]string) map[string]string {
+	result := make(map[string]string, len(current)+len(labels)+1)
+
+	for k, v := range current {
+		result[k] = v
+	}
+
+	for k, v := range labels {
+		result[k] = v
+	}
+
+	result[ManagedByLabelName] = ManagedByLabelValue
+
+	return result
+}
@@ -74,6 +74,7 @@ func TestCreateRole_Success(t *testing.T) {
 			s := NewRbacManager(k8sClient, logr.Discard())
 
 			err := s.CreateRole(
+				context.Background(),
 				tt.args.name,
 				tt.args.namespace,
 				[]rbacApi.PolicyRule{},
@@ -148,6 +149,7 @@ func TestGetRole_Success(t *testing.T) {
 			)
 
 			role, err := s.GetRole(
+				context.Background(),
 				tt.args.name,
 				tt.args.namespace,
 			)
@@ -280,3 +282,281 @@ func TestKubernetesRbac_CreateRoleBindingIfNotExists(t *testing.T) {
 		})
 	}
 }
+
+func TestKubernetesRbac_CreateRoleIfNotExists(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, rbacApi.AddToScheme(scheme))
+
+	rules := []rbacApi.PolicyRule{
+		{
+			APIGroups: []string{""},
+			Resources: []string{"pods"},
+			Verbs:     []string{"get"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		objects   []client.Object
+		wantErr   assert.ErrorAssertionFunc
+		wantCheck func(t *testing.T, k8sClient client.Client)
+	}{
+		{
+			name:    "Role does not exist, create it",
+			wantErr: assert.NoError,
+			wantCheck: func(t *testing.T, k8sClient client.Client) {
+				role := &rbacApi.Role{}
+				err := k8sClient.Get(context.Background(), types.NamespacedName{
+					Namespace: "test-namespace",
+					Name:      "test-role",
+				}, role)
+				assert.NoError(t, err)
+				assert.Equal(t, rules, role.Rules)
+			},
+		},
+		{
+			name: "Role exists, do not create it",
+			objects: []client.Object{
+				&rbacApi.Role{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-role",
+						Namespace: "test-namespace",
+					},
+				},
+			},
+			wantErr: assert.NoError,
+			wantCheck: func(t *testing.T, k8sClient client.Client) {
+				role := &rbacApi.Role{}
+				err := k8sClient.Get(context.Background(), types.NamespacedName{
+					Namespace: "test-namespace",
+					Name:      "test-role",
+				}, role)
+				assert.NoError(t, err)
+				assert.Empty(t, role.Rules)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+			s := NewRbacManager(k8sClient, logr.Discard())
+
+			tt.wantErr(t, s.CreateRoleIfNotExists(context.Background(), "test-role", "test-namespace", rules))
+			tt.wantCheck(t, k8sClient)
+		})
+	}
+}
+
+func TestKubernetesRbac_CreateOrUpdateRoleBinding(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, rbacApi.AddToScheme(scheme))
+
+	subjects := []rbacApi.Subject{
+		{
+			APIGroup: rbacApi.GroupName,
+			Kind:     rbacApi.GroupKind,
+			Name:     "developers",
+		},
+	}
+	roleRef := rbacApi.RoleRef{
+		APIGroup: rbacApi.GroupName,
+		Kind:     ClusterRoleKind,
+		Name:     "view",
+	}
+	labels := map[string]string{"app.edp.epam.com/stage": "dev"}
+
+	tests := []struct {
+		name      string
+		objects   []client.Object
+		wantErr   assert.ErrorAssertionFunc
+		wantCheck func(t *testing.T, rb *rbacApi.RoleBinding)
+	}{
+		{
+			name:    "RoleBinding does not exist, create it",
+			wantErr: assert.NoError,
+			wantCheck: func(t *testing.T, rb *rbacApi.RoleBinding) {
+				assert.Equal(t, subjects, rb.Subjects)
+				assert.Equal(t, roleRef, rb.RoleRef)
+				assert.Equal(t, "dev", rb.Labels["app.edp.epam.com/stage"])
+				assert.Equal(t, ManagedByLabelValue, rb.Labels[ManagedByLabelName])
+			},
+		},
+		{
+			name: "RoleBinding subjects are changed, update them",
+			objects: []client.Object{
+				&rbacApi.RoleBinding{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-rb",
+						Namespace: "test-namespace",
+						Labels:    map[string]string{"custom": "label"},
+					},
+					Subjects: []rbacApi.Subject{{Kind: rbacApi.UserKind, Name: "user"}},
+					RoleRef:  roleRef,
+				},
+			},
+			wantErr: assert.NoError,
+			wantCheck: func(t *testing.T, rb *rbacApi.RoleBinding) {
+				assert.Equal(t, subjects, rb.Subjects)
+				assert.Equal(t, "label", rb.Labels["custom"])
+				assert.Equal(t, ManagedByLabelValue, rb.Labels[ManagedByLabelName])
+			},
+		},
+		{
+			name: "RoleBinding roleRef is changed, recreate it",
+			objects: []client.Object{
+				&rbacApi.RoleBinding{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-rb",
+						Namespace: "test-namespace",
+					},
+					Subjects: subjects,
+					RoleRef: rbacApi.RoleRef{
+						APIGroup: rbacApi.GroupName,
+						Kind:     ClusterRoleKind,
+						Name:     "cluster-admin",
+					},
+				},
+			},
+			wantErr: assert.NoError,
+			wantCheck: func(t *testing.T, rb *rbacApi.RoleBinding) {
+				assert.Equal(t, roleRef, rb.RoleRef)
+				assert.Equal(t, subjects, rb.Subjects)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+			s := NewRbacManager(k8sClient, logr.Discard())
+
+			tt.wantErr(t, s.CreateOrUpdateRoleBinding(context.Background(), "test-rb", "test-namespace", subjects, roleRef, labels))
+
+			rb := &rbacApi.RoleBinding{}
+			require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{
+				Namespace: "test-namespace",
+				Name:      "test-rb",
+			}, rb))
+			tt.wantCheck(t, rb)
+		})
+	}
+}
+
+func TestKubernetesRbac_CreateOrUpdateRole(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, rbacApi.AddToScheme(scheme))
+
+	rules := []rbacApi.PolicyRule{
+		{
+			APIGroups: []string{""},
+			Resources: []string{"pods"},
+			Verbs:     []string{"get"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		objects []client.Object
+	}{
+		{
+			name: "Role does not exist, create it",
+		},
+		{
+			name: "Role rules are changed, update them",
+			objects: []client.Object{
+				&rbacApi.Role{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-role",
+						Namespace: "test-namespace",
+					},
+					Rules: []rbacApi.PolicyRule{
+						{
+							APIGroups: []string{"*"},
+							Resources: []string{"*"},
+							Verbs:     []string{"*"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+			s := NewRbacManager(k8sClient, logr.Discard())
+
+			require.NoError(t, s.CreateOrUpdateRole(context.Background(), "test-role", "test-namespace", rules, nil))
+
+			role := &rbacApi.Role{}
+			require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{
+				Namespace: "test-namespace",
+				Name:      "test-role",
+			}, role))
+			assert.Equal(t, rules, role.Rules)
+			assert.Equal(t, ManagedByLabelValue, role.Labels[ManagedByLabelName])
+		})
+	}
+}
+
+func TestKubernetesRbac_Prune(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, rbacApi.AddToScheme(scheme))
+
+	labels := map[string]string{"app.edp.epam.com/stage": "dev"}
+	managed := map[string]string{
+		"app.edp.epam.com/stage": "dev",
+		ManagedByLabelName:       ManagedByLabelValue,
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&rbacApi.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "kept", Namespace: "test-namespace", Labels: managed}},
+		&rbacApi.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "orphan", Namespace: "test-namespace", Labels: managed}},
+		&rbacApi.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: "test-namespace"}},
+		&rbacApi.Role{ObjectMeta: metav1.ObjectMeta{Name: "kept", Namespace: "test-namespace", Labels: managed}},
+		&rbacApi.Role{ObjectMeta: metav1.ObjectMeta{Name: "orphan", Namespace: "test-namespace", Labels: managed}},
+		&rbacApi.Role{ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: "test-namespace"}},
+	).Build()
+	s := NewRbacManager(k8sClient, logr.Discard())
+
+	require.NoError(t, s.PruneRoleBindings(context.Background(), "test-namespace", labels, []string{"kept"}))
+	require.NoError(t, s.PruneRoles(context.Background(), "test-namespace", labels, []string{"kept"}))
+
+	roleBindings := &rbacApi.RoleBindingList{}
+	require.NoError(t, k8sClient.List(context.Background(), roleBindings))
+
+	roleBindingNames := make([]string, 0, len(roleBindings.Items))
+	for i := range roleBindings.Items {
+		roleBindingNames = append(roleBindingNames, roleBindings.Items[i].Name)
+	}
+
+	assert.ElementsMatch(t, []string{"kept", "foreign"}, roleBindingNames)
+
+	roles := &rbacApi.RoleList{}
+	require.NoError(t, k8sClient.List(context.Background(), roles))
+
+	roleNames := make([]string, 0, len(roles.Items))
+	for i := range roles.Items {
+		roleNames = append(roleNames, roles.Items[i].Name)
+	}
+
+	assert.ElementsMatch(t, []string{"kept", "foreign"}, roleNames)
+}
@@ -19,29 +19,34 @@ import (
 const (
 	watchNamespaceEnvVar   = "WATCH_NAMESPACE"
 	debugModeEnvVar        = "DEBUG_MODE"
+	enableWebhooksEnvVar   = "ENABLE_WEBHOOKS"
 	inClusterNamespacePath = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
 )
 
-func GetCdPipeline(c client.Client, name, namespace string) (*cdPipeApi.CDPipeline, error) {
+func GetCdPipeline(ctx context.Context, c client.Client, name, namespace string) (*cdPipeApi.CDPipeline, error) {
 	nsn := types.NamespacedName{
 		Name:      name,
 		Namespace: namespace,
 	}
 
 	i := &cdPipeApi.CDPipeline{}
-	if err := c.Get(context.TODO(), nsn, i); err != nil {
+	if err := c.Get(ctx, nsn, i); err != nil {
 		return nil, fmt.Errorf("failed to get cd pipeline: %w", err)
 	}
 
 	return i, nil
 }
 
-func GetCodebaseImageStream(c client.Client, name, namespace string) (*codebaseApi.CodebaseImageStream, error) {
-	re := strings.NewReplacer("/", "-", ".", "-")
-	name = re.Replace(name)
+// CodebaseImageStreamName converts the docker stream name to the CodebaseImageStream resource name.
+func CodebaseImageStreamName(stream string) string {
+	return strings.NewReplacer("/", "-", ".", "-").Replace(stream)
+}
+
+func GetCodebaseImageStream(ctx context.Context, c client.Client, name, namespace string) (*codebaseApi.CodebaseImageStream, error) {
+	name = CodebaseImageStreamName(name)
 	i := &codebaseApi.CodebaseImageStream{}
 
-	if err := c.Get(context.Background(), types.NamespacedName{
+	if err := c.Get(ctx, types.NamespacedName{
 		Name:      name,
 		Namespace: namespace,
 	}, i); err != nil {
@@ -76,6 +81,23 @@ func GetDebugMode() (bool, error) {
 	return b, nil
 }
 
+// WebhooksEnabled returns true if the webhook server should be started.
+// It is enabled if the environment variable ENABLE_WEBHOOKS is set to true.
+// The webhook server requires TLS certificates, so it is disabled by default.
+func WebhooksEnabled() bool {
+	enabled, found := os.LookupEnv(enableWebhooksEnvVar)
+	if !found {
+		return false
+	}
+
+	b, err := strconv.ParseBool(enabled)
+	if err != nil {
+		return false
+	}
+
+	return b
+}
+
 // Check whether the operator is running in cluster or locally.
 func RunningInCluster() bool {
 	_, err := os.Stat(inClusterNamespacePath)
@@ -13,6 +13,7 @@ import (
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -40,7 +41,7 @@ func TestGetCdPipeline_Success(t *testing.T) {
 
 	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cdPipeline).Build()
 
-	_, err := GetCdPipeline(c, name, namespace)
+	_, err := GetCdPipeline(context.Background(), c, name, namespace)
 	assert.NoError(t, err)
 }
 
@@ -49,7 +50,7 @@ func TestGetCdPipeline_IsNotFound(t *testing.T) {
 	scheme.AddKnownTypes(k8sApi.SchemeGroupVersion, &cdPipeApi.CDPipeline{})
 	c := fake.NewClientBuilder().WithScheme(scheme).Build()
 
-	_, err := GetCdPipeline(c, name, namespace)
+	_, err := GetCdPipeline(context.Background(), c, name, namespace)
 	assert.True(t, k8sErrors.IsNotFound(err))
 }
 
@@ -66,7 +67,7 @@ func TestGetCodebaseImageStream_Success(t *testing.T) {
 
 	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cdPipeline).Build()
 
-	_, err := GetCodebaseImageStream(c, name, namespace)
+	_, err := GetCodebaseImageStream(context.Background(), c, name, namespace)
 	assert.NoError(t, err)
 }
 
@@ -75,10 +76,17 @@ func TestGetCodebaseImageStream_isNotFound(t *testing.T) {
 	scheme.AddKnownTypes(k8sApi.SchemeGroupVersion, &codebaseApi.CodebaseImageStream{})
 	c := fake.NewClientBuilder().WithScheme(scheme).Build()
 
-	_, err := GetCodebaseImageStream(c, name, namespace)
+	_, err := GetCodebaseImageStream(context.Background(), c, name, namespace)
 	assert.True(t, k8sErrors.IsNotFound(err))
 }
 
+func TestCodebaseImageStreamName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "my-app-master", CodebaseImageStreamName("my-app-master"))
+	assert.Equal(t, "my-app-release-1-0", CodebaseImageStreamName("my-app-release/1.0"))
+}
+
 func TestGetWatchNamespace_Success(t *testing.T) {
 	err := os.Setenv(watchNamespaceEnvVar, namespace)
 	if err != nil {
@@ -144,6 +152,44 @@ func TestGetDebugMode_IsNotSet(t *testing.T) {
 	assert.False(t, debugMode)
 }
 
+func TestWebhooksEnabled(t *testing.T) {
+	tests := []struct {
+		name  string
+		value *string
+		want  bool
+	}{
+		{
+			name: "should be disabled if env is not set",
+			want: false,
+		},
+		{
+			name:  "should be enabled",
+			value: pointer.String("true"),
+			want:  true,
+		},
+		{
+			name:  "should be disabled",
+			value: pointer.String("false"),
+			want:  false,
+		},
+		{
+			name:  "should be disabled if env is invalid",
+			value: pointer.String("invalid"),
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.value != nil {
+				t.Setenv(enableWebhooksEnvVar, *tt.value)
+			}
+
+			assert.Equal(t, tt.want, WebhooksEnabled())
+		})
+	}
+}
+
 func TestJenkinsEnabled(t *testing.T) {
 	scheme := runtime.NewScheme()
 	err := jenkinsApi.AddToScheme(scheme)
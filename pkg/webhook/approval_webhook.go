@@ -0,0 +1,250 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
+	admissionv1 "k8s.io/api/admission/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+//+kubebuilder:webhook:path=/mutate-v2-edp-epam-com-v1-approval,mutating=true,failurePolicy=fail,sideEffects=None,groups=v2.edp.epam.com,resources=approvals,verbs=create,versions=v1,name=mapproval.edp.epam.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-v2-edp-epam-com-v1-approval,mutating=false,failurePolicy=fail,sideEffects=None,groups=v2.edp.epam.com,resources=approvals,verbs=create;update,versions=v1,name=vapproval.edp.epam.com,admissionReviewVersions=v1
+
+// ApprovalWebhook is a webhook for the Approval resource.
+type ApprovalWebhook struct {
+	client client.Client
+	log    logr.Logger
+}
+
+var (
+	_ admission.CustomValidator = &ApprovalWebhook{}
+	_ admission.CustomDefaulter = &ApprovalWebhook{}
+)
+
+// NewApprovalWebhook returns a new instance of ApprovalWebhook.
+func NewApprovalWebhook(c client.Client, log logr.Logger) *ApprovalWebhook {
+	return &ApprovalWebhook{
+		client: c,
+		log:    log.WithName("approval-webhook"),
+	}
+}
+
+// SetupWebhookWithManager registers the Approval webhooks in the manager.
+func (r *ApprovalWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&cdPipeApi.Approval{}).
+		WithDefaulter(r).
+		WithValidator(r).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to create approval webhook: %w", err)
+	}
+
+	return nil
+}
+
+// Default sets the approver and the approver groups from the admission request user info,
+// so the Approval is always made on behalf of the user who creates it.
+// The UID and the generation of the approved Promotion are set, so the Approval is counted only for it.
+func (r *ApprovalWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	approval, ok := obj.(*cdPipeApi.Approval)
+	if !ok {
+		return fmt.Errorf("expected an Approval but got a %T", obj)
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get admission request: %w", err)
+	}
+
+	if req.Operation != admissionv1.Create {
+		return nil
+	}
+
+	r.log.Info("Defaulting Approval", "name", approval.Name, "approver", req.UserInfo.Username)
+
+	approval.Spec.Approver = req.UserInfo.Username
+	approval.Spec.ApproverGroups = req.UserInfo.Groups
+	approval.Spec.PromotionUID = ""
+	approval.Spec.PromotionGeneration = 0
+
+	promotion := &cdPipeApi.Promotion{}
+	if err = r.client.Get(ctx, client.ObjectKey{
+		Namespace: approval.Namespace,
+		Name:      approval.Spec.Promotion,
+	}, promotion); err != nil {
+		// Approval of the missing Promotion is rejected by the validation.
+		if k8sErrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to get Promotion %s: %w", approval.Spec.Promotion, err)
+	}
+
+	approval.Spec.PromotionUID = promotion.UID
+	approval.Spec.PromotionGeneration = promotion.Generation
+
+	return nil
+}
+
+// ValidateCreate checks that the approver is the user who creates the Approval,
+// the approved Promotion hasn't been completed yet and the user is an approver of the Promotion target stage.
+func (r *ApprovalWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	approval, ok := obj.(*cdPipeApi.Approval)
+	if !ok {
+		return fmt.Errorf("expected an Approval but got a %T", obj)
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get admission request: %w", err)
+	}
+
+	r.log.Info("Validating Approval creation", "name", approval.Name, "approver", req.UserInfo.Username)
+
+	errs := validateApprover(approval, req.UserInfo.Username, req.UserInfo.Groups)
+
+	if len(errs) == 0 {
+		if errs, err = r.validatePromotion(ctx, approval); err != nil {
+			return err
+		}
+	}
+
+	return toInvalidApprovalError(approval, errs)
+}
+
+// ValidateUpdate checks that the Approval spec hasn't been changed.
+// Updates of the Approval which is being deleted are not validated to allow finalizers removal.
+func (r *ApprovalWebhook) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) error {
+	oldApproval, ok := oldObj.(*cdPipeApi.Approval)
+	if !ok {
+		return fmt.Errorf("expected an Approval but got a %T", oldObj)
+	}
+
+	approval, ok := newObj.(*cdPipeApi.Approval)
+	if !ok {
+		return fmt.Errorf("expected an Approval but got a %T", newObj)
+	}
+
+	if !approval.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	r.log.Info("Validating Approval update", "name", approval.Name)
+
+	if !reflect.DeepEqual(oldApproval.Spec, approval.Spec) {
+		return toInvalidApprovalError(approval, field.ErrorList{
+			field.Forbidden(field.NewPath("spec"), "approval can't be changed"),
+		})
+	}
+
+	return nil
+}
+
+// ValidateDelete doesn't validate the Approval deletion.
+func (*ApprovalWebhook) ValidateDelete(context.Context, runtime.Object) error {
+	return nil
+}
+
+// validatePromotion checks that the Promotion exists, it is the Promotion set by the defaulting, isn't completed yet
+// and the approver can approve the promotions to its target stage.
+func (r *ApprovalWebhook) validatePromotion(ctx context.Context, approval *cdPipeApi.Approval) (field.ErrorList, error) {
+	promotionPath := field.NewPath("spec", "promotion")
+
+	promotion := &cdPipeApi.Promotion{}
+	if err := r.client.Get(ctx, client.ObjectKey{
+		Namespace: approval.Namespace,
+		Name:      approval.Spec.Promotion,
+	}, promotion); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return field.ErrorList{field.NotFound(promotionPath, approval.Spec.Promotion)}, nil
+		}
+
+		return nil, fmt.Errorf("failed to get Promotion %s: %w", approval.Spec.Promotion, err)
+	}
+
+	if approval.Spec.PromotionUID != promotion.UID || approval.Spec.PromotionGeneration != promotion.Generation {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec", "promotionUID"),
+			"approval should be given to the current Promotion")}, nil
+	}
+
+	if promotion.IsCompleted() {
+		return field.ErrorList{field.Forbidden(promotionPath, "promotion has been already completed")}, nil
+	}
+
+	stage, err := r.getTargetStage(ctx, promotion)
+	if err != nil {
+		return nil, err
+	}
+
+	if stage == nil {
+		return field.ErrorList{field.Invalid(promotionPath, approval.Spec.Promotion,
+			fmt.Sprintf("target stage %s doesn't exist in %s pipeline", promotion.Spec.TargetStage, promotion.Spec.Pipeline))}, nil
+	}
+
+	if stage.Spec.Approval == nil {
+		return field.ErrorList{field.Invalid(promotionPath, approval.Spec.Promotion,
+			fmt.Sprintf("target stage %s doesn't require approvals", stage.Spec.Name))}, nil
+	}
+
+	if !stage.Spec.Approval.IsApprover(approval.Spec.Approver, approval.Spec.ApproverGroups) {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec", "approver"),
+			fmt.Sprintf("user %s can't approve promotions to %s stage", approval.Spec.Approver, stage.Spec.Name))}, nil
+	}
+
+	return nil, nil
+}
+
+// getTargetStage returns the Promotion target stage or nil if it doesn't exist.
+func (r *ApprovalWebhook) getTargetStage(ctx context.Context, promotion *cdPipeApi.Promotion) (*cdPipeApi.Stage, error) {
+	stages := &cdPipeApi.StageList{}
+	if err := r.client.List(
+		ctx,
+		stages,
+		client.InNamespace(promotion.Namespace),
+		client.MatchingLabels{cdPipeApi.StageCdPipelineLabelName: promotion.Spec.Pipeline},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list stages: %w", err)
+	}
+
+	for i := range stages.Items {
+		if stages.Items[i].Spec.Name == promotion.Spec.TargetStage {
+			return &stages.Items[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// validateApprover checks that the approver identity matches the admission request user info.
+func validateApprover(approval *cdPipeApi.Approval, user string, groups []string) field.ErrorList {
+	var errs field.ErrorList
+
+	if approval.Spec.Approver != user {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "approver"), "approver should be the user who creates the approval"))
+	}
+
+	if !slices.Equal(approval.Spec.ApproverGroups, groups) {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "approverGroups"), "approver groups should be the groups of the user who creates the approval"))
+	}
+
+	return errs
+}
+
+func toInvalidApprovalError(approval *cdPipeApi.Approval, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return k8sErrors.NewInvalid(cdPipeApi.GroupVersion.WithKind("Approval").GroupKind(), approval.Name, errs)
+}
@@ -0,0 +1,273 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+)
+
+func TestApprovalWebhook_SetupWebhookWithManager(t *testing.T) {
+	t.Parallel()
+
+	mgr := newTestManager(t)
+
+	require.NoError(t, NewApprovalWebhook(fake.NewClientBuilder().Build(), logr.Discard()).SetupWebhookWithManager(mgr))
+	requireHandled(t, mgr, "/mutate-v2-edp-epam-com-v1-approval")
+	requireHandled(t, mgr, "/validate-v2-edp-epam-com-v1-approval")
+}
+
+func newApprovalRequestContext(operation admissionv1.Operation, user string, groups ...string) context.Context {
+	return admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Namespace: "default",
+			Operation: operation,
+			UserInfo: authenticationv1.UserInfo{
+				Username: user,
+				Groups:   groups,
+			},
+		},
+	})
+}
+
+func newTestApproval(approver string, groups ...string) *cdPipeApi.Approval {
+	return &cdPipeApi.Approval{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "approval",
+			Namespace: "default",
+		},
+		Spec: cdPipeApi.ApprovalSpec{
+			Promotion:           "promotion",
+			PromotionUID:        "promotion-uid",
+			PromotionGeneration: 1,
+			Approver:            approver,
+			ApproverGroups:      groups,
+		},
+	}
+}
+
+func TestApprovalWebhook_Default(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	promotion := &cdPipeApi.Promotion{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:       "promotion",
+			Namespace:  "default",
+			UID:        "current-promotion-uid",
+			Generation: 2,
+		},
+	}
+
+	w := NewApprovalWebhook(fake.NewClientBuilder().WithScheme(scheme).WithObjects(promotion).Build(), logr.Discard())
+
+	approval := newTestApproval("someone-else", "admins")
+
+	require.NoError(t, w.Default(newApprovalRequestContext(admissionv1.Create, "john", "release-managers"), approval))
+	assert.Equal(t, "john", approval.Spec.Approver)
+	assert.Equal(t, []string{"release-managers"}, approval.Spec.ApproverGroups)
+	assert.Equal(t, promotion.UID, approval.Spec.PromotionUID)
+	assert.Equal(t, int64(2), approval.Spec.PromotionGeneration)
+
+	require.NoError(t, w.Default(newApprovalRequestContext(admissionv1.Update, "jane"), approval))
+	assert.Equal(t, "john", approval.Spec.Approver)
+
+	require.Error(t, w.Default(context.Background(), approval))
+}
+
+func TestApprovalWebhook_ValidateCreate(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	newPromotion := func(phase string) *cdPipeApi.Promotion {
+		return &cdPipeApi.Promotion{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:       "promotion",
+				Namespace:  "default",
+				UID:        "promotion-uid",
+				Generation: 1,
+			},
+			Spec: cdPipeApi.PromotionSpec{
+				Pipeline:    "pipeline",
+				SourceStage: "qa",
+				TargetStage: "prod",
+			},
+			Status: cdPipeApi.PromotionStatus{
+				Phase: phase,
+			},
+		}
+	}
+
+	newTargetStage := func(approval *cdPipeApi.ApprovalPolicy) *cdPipeApi.Stage {
+		s := newTestStage("prod", 2)
+		s.Labels = map[string]string{cdPipeApi.StageCdPipelineLabelName: "pipeline"}
+		s.Spec.Approval = approval
+
+		return s
+	}
+
+	policy := &cdPipeApi.ApprovalPolicy{
+		Users:  []string{"john"},
+		Groups: []string{"release-managers"},
+	}
+
+	tests := []struct {
+		name     string
+		approval *cdPipeApi.Approval
+		ctx      context.Context
+		objects  []client.Object
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			name:     "approver from users list",
+			approval: newTestApproval("john"),
+			ctx:      newApprovalRequestContext(admissionv1.Create, "john"),
+			objects: []client.Object{
+				newPromotion(cdPipeApi.PromotionPhaseWaitingForApproval),
+				newTargetStage(policy),
+			},
+			wantErr: require.NoError,
+		},
+		{
+			name:     "approver from groups list",
+			approval: newTestApproval("jane", "developers", "release-managers"),
+			ctx:      newApprovalRequestContext(admissionv1.Create, "jane", "developers", "release-managers"),
+			objects: []client.Object{
+				newPromotion(cdPipeApi.PromotionPhaseWaitingForApproval),
+				newTargetStage(policy),
+			},
+			wantErr: require.NoError,
+		},
+		{
+			name:     "approver is not the request user",
+			approval: newTestApproval("john", "release-managers"),
+			ctx:      newApprovalRequestContext(admissionv1.Create, "jane"),
+			objects: []client.Object{
+				newPromotion(cdPipeApi.PromotionPhaseWaitingForApproval),
+				newTargetStage(policy),
+			},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				requireInvalid("spec.approver: Forbidden")(t, err, i...)
+				requireInvalid("spec.approverGroups: Forbidden")(t, err, i...)
+			},
+		},
+		{
+			name:     "user can't approve",
+			approval: newTestApproval("jane", "developers"),
+			ctx:      newApprovalRequestContext(admissionv1.Create, "jane", "developers"),
+			objects: []client.Object{
+				newPromotion(cdPipeApi.PromotionPhaseWaitingForApproval),
+				newTargetStage(policy),
+			},
+			wantErr: requireInvalid("user jane can't approve promotions to prod stage"),
+		},
+		{
+			name:     "promotion doesn't exist",
+			approval: newTestApproval("john"),
+			ctx:      newApprovalRequestContext(admissionv1.Create, "john"),
+			objects:  []client.Object{newTargetStage(policy)},
+			wantErr:  requireInvalid("spec.promotion: Not found"),
+		},
+		{
+			name: "promotion has been recreated",
+			approval: func() *cdPipeApi.Approval {
+				a := newTestApproval("john")
+				a.Spec.PromotionUID = "previous-promotion-uid"
+
+				return a
+			}(),
+			ctx: newApprovalRequestContext(admissionv1.Create, "john"),
+			objects: []client.Object{
+				newPromotion(cdPipeApi.PromotionPhaseWaitingForApproval),
+				newTargetStage(policy),
+			},
+			wantErr: requireInvalid("spec.promotionUID: Forbidden"),
+		},
+		{
+			name:     "promotion has been completed",
+			approval: newTestApproval("john"),
+			ctx:      newApprovalRequestContext(admissionv1.Create, "john"),
+			objects: []client.Object{
+				newPromotion(cdPipeApi.PromotionPhaseSucceeded),
+				newTargetStage(policy),
+			},
+			wantErr: requireInvalid("promotion has been already completed"),
+		},
+		{
+			name:     "target stage doesn't require approvals",
+			approval: newTestApproval("john"),
+			ctx:      newApprovalRequestContext(admissionv1.Create, "john"),
+			objects: []client.Object{
+				newPromotion(""),
+				newTargetStage(nil),
+			},
+			wantErr: requireInvalid("target stage prod doesn't require approvals"),
+		},
+		{
+			name:     "target stage doesn't exist",
+			approval: newTestApproval("john"),
+			ctx:      newApprovalRequestContext(admissionv1.Create, "john"),
+			objects:  []client.Object{newPromotion("")},
+			wantErr:  requireInvalid("target stage prod doesn't exist in pipeline pipeline"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := NewApprovalWebhook(
+				fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build(),
+				logr.Discard(),
+			)
+
+			tt.wantErr(t, w.ValidateCreate(tt.ctx, tt.approval))
+		})
+	}
+}
+
+func TestApprovalWebhook_ValidateUpdate(t *testing.T) {
+	t.Parallel()
+
+	w := NewApprovalWebhook(fake.NewClientBuilder().Build(), logr.Discard())
+
+	oldApproval := newTestApproval("john")
+
+	approval := oldApproval.DeepCopy()
+	approval.Labels = map[string]string{"app": "approval"}
+
+	require.NoError(t, w.ValidateUpdate(context.Background(), oldApproval, approval))
+
+	approval.Spec.Approver = "jane"
+
+	requireInvalid("spec: Forbidden: approval can't be changed")(t, w.ValidateUpdate(context.Background(), oldApproval, approval))
+
+	now := metaV1.Now()
+	approval.DeletionTimestamp = &now
+
+	require.NoError(t, w.ValidateUpdate(context.Background(), oldApproval, approval))
+}
+
+func TestApprovalWebhook_ValidateDelete(t *testing.T) {
+	t.Parallel()
+
+	w := NewApprovalWebhook(fake.NewClientBuilder().Build(), logr.Discard())
+
+	require.NoError(t, w.ValidateDelete(context.Background(), newTestApproval("john")))
+}
@@ -0,0 +1,272 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/cluster"
+	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
+)
+
+//+kubebuilder:webhook:path=/validate-v2-edp-epam-com-v1-cdpipeline,mutating=false,failurePolicy=fail,sideEffects=None,groups=v2.edp.epam.com,resources=cdpipelines,verbs=create;update,versions=v1,name=vcdpipeline.edp.epam.com,admissionReviewVersions=v1
+
+// CDPipelineWebhook is a webhook for the CDPipeline resource.
+type CDPipelineWebhook struct {
+	client client.Client
+	log    logr.Logger
+}
+
+var _ admission.CustomValidator = &CDPipelineWebhook{}
+
+// NewCDPipelineWebhook returns a new instance of CDPipelineWebhook.
+func NewCDPipelineWebhook(c client.Client, log logr.Logger) *CDPipelineWebhook {
+	return &CDPipelineWebhook{
+		client: c,
+		log:    log.WithName("cdpipeline-webhook"),
+	}
+}
+
+// SetupWebhookWithManager registers the CDPipeline webhooks in the manager.
+// The conversion webhook is registered because v1 CDPipeline is a conversion hub.
+func (r *CDPipelineWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&cdPipeApi.CDPipeline{}).
+		WithValidator(r).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to create cdpipeline webhook: %w", err)
+	}
+
+	return nil
+}
+
+// ValidateCreate checks that the deployment type is supported,
+// applications to promote are included in the applications list
+// and all input docker streams exist.
+func (r *CDPipelineWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	pipeline, ok := obj.(*cdPipeApi.CDPipeline)
+	if !ok {
+		return fmt.Errorf("expected a CDPipeline but got a %T", obj)
+	}
+
+	r.log.Info("Validating CDPipeline creation", "name", pipeline.Name)
+
+	errs, err := r.validateSpec(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+
+	return toInvalidCDPipelineError(pipeline, errs)
+}
+
+// ValidateUpdate validates the CDPipeline spec in the same way as ValidateCreate
+// and checks that removed applications haven't been promoted by the pipeline stages.
+// Promoted applications can be removed with the ForceRemoveApplicationsAnnotation annotation.
+// Updates of the CDPipeline which is being deleted are not validated to allow finalizers removal.
+func (r *CDPipelineWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldPipeline, ok := oldObj.(*cdPipeApi.CDPipeline)
+	if !ok {
+		return fmt.Errorf("expected a CDPipeline but got a %T", oldObj)
+	}
+
+	pipeline, ok := newObj.(*cdPipeApi.CDPipeline)
+	if !ok {
+		return fmt.Errorf("expected a CDPipeline but got a %T", newObj)
+	}
+
+	if !pipeline.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	r.log.Info("Validating CDPipeline update", "name", pipeline.Name)
+
+	errs, err := r.validateSpec(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+
+	if pipeline.GetAnnotations()[cdPipeApi.ForceRemoveApplicationsAnnotation] != "true" {
+		removalErrs, err := r.validateApplicationsRemoval(ctx, oldPipeline, pipeline)
+		if err != nil {
+			return err
+		}
+
+		errs = append(errs, removalErrs...)
+	}
+
+	return toInvalidCDPipelineError(pipeline, errs)
+}
+
+// ValidateDelete doesn't validate the CDPipeline deletion.
+func (*CDPipelineWebhook) ValidateDelete(context.Context, runtime.Object) error {
+	return nil
+}
+
+func (r *CDPipelineWebhook) validateSpec(ctx context.Context, pipeline *cdPipeApi.CDPipeline) (field.ErrorList, error) {
+	errs := validateDeploymentType(pipeline)
+	errs = append(errs, validateApplicationsToPromote(pipeline)...)
+	errs = append(errs, validateNamespaceMetadata(
+		pipeline.Spec.NamespaceLabels,
+		pipeline.Spec.NamespaceAnnotations,
+		field.NewPath("spec"),
+	)...)
+
+	streamErrs, err := r.validateInputDockerStreams(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(errs, streamErrs...), nil
+}
+
+// validateInputDockerStreams checks that CodebaseImageStream exists for every input docker stream.
+func (r *CDPipelineWebhook) validateInputDockerStreams(ctx context.Context, pipeline *cdPipeApi.CDPipeline) (field.ErrorList, error) {
+	var errs field.ErrorList
+
+	for i, stream := range pipeline.Spec.InputDockerStreams {
+		name := cluster.CodebaseImageStreamName(stream)
+
+		if err := r.client.Get(ctx, client.ObjectKey{
+			Namespace: pipeline.Namespace,
+			Name:      name,
+		}, &codebaseApi.CodebaseImageStream{}); err != nil {
+			if k8sErrors.IsNotFound(err) {
+				errs = append(errs, field.NotFound(field.NewPath("spec", "inputDockerStreams").Index(i), stream))
+
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to get CodebaseImageStream %s: %w", name, err)
+		}
+	}
+
+	return errs, nil
+}
+
+// validateApplicationsRemoval checks that applications removed from the CDPipeline
+// haven't been promoted by the pipeline stages, i.e. their verified CodebaseImageStreams don't have tags.
+func (r *CDPipelineWebhook) validateApplicationsRemoval(
+	ctx context.Context,
+	oldPipeline, pipeline *cdPipeApi.CDPipeline,
+) (field.ErrorList, error) {
+	removed := difference(oldPipeline.Spec.Applications, pipeline.Spec.Applications)
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	stages := &cdPipeApi.StageList{}
+	if err := r.client.List(ctx, stages, client.InNamespace(pipeline.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list stages: %w", err)
+	}
+
+	var errs field.ErrorList
+
+	for _, app := range removed {
+		for i := range stages.Items {
+			stage := &stages.Items[i]
+			if stage.Spec.CdPipeline != pipeline.Name {
+				continue
+			}
+
+			cisName := util.VerifiedImageStreamName(pipeline.Name, stage.Spec.Name, app)
+			cis := &codebaseApi.CodebaseImageStream{}
+
+			err := r.client.Get(ctx, client.ObjectKey{
+				Namespace: pipeline.Namespace,
+				Name:      cisName,
+			}, cis)
+			if k8sErrors.IsNotFound(err) {
+				continue
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("failed to get CodebaseImageStream %s: %w", cisName, err)
+			}
+
+			// The verified stream is created for every application of the stage, it has tags only if they have been promoted.
+			if len(cis.Spec.Tags) == 0 {
+				continue
+			}
+
+			errs = append(errs, field.Forbidden(
+				field.NewPath("spec", "applications"),
+				fmt.Sprintf("application %s has been promoted by stage %s, set %s annotation to \"true\" to remove it",
+					app, stage.Spec.Name, cdPipeApi.ForceRemoveApplicationsAnnotation),
+			))
+
+			break
+		}
+	}
+
+	return errs, nil
+}
+
+func validateDeploymentType(pipeline *cdPipeApi.CDPipeline) field.ErrorList {
+	supportedTypes := []string{cdPipeApi.DeploymentTypeContainer, cdPipeApi.DeploymentTypeCustom}
+
+	for _, t := range supportedTypes {
+		if pipeline.Spec.DeploymentType == t {
+			return nil
+		}
+	}
+
+	return field.ErrorList{
+		field.NotSupported(field.NewPath("spec", "deploymentType"), pipeline.Spec.DeploymentType, supportedTypes),
+	}
+}
+
+func validateApplicationsToPromote(pipeline *cdPipeApi.CDPipeline) field.ErrorList {
+	var errs field.ErrorList
+
+	apps := make(map[string]struct{}, len(pipeline.Spec.Applications))
+	for _, app := range pipeline.Spec.Applications {
+		apps[app] = struct{}{}
+	}
+
+	for i, app := range pipeline.Spec.ApplicationsToPromote {
+		if _, ok := apps[app]; !ok {
+			errs = append(errs, field.Invalid(
+				field.NewPath("spec", "applicationsToPromote").Index(i),
+				app,
+				"application should be included in spec.applications",
+			))
+		}
+	}
+
+	return errs
+}
+
+// difference returns elements of a that are not present in b.
+func difference(a, b []string) []string {
+	set := make(map[string]struct{}, len(b))
+	for _, s := range b {
+		set[s] = struct{}{}
+	}
+
+	var diff []string
+
+	for _, s := range a {
+		if _, ok := set[s]; !ok {
+			diff = append(diff, s)
+		}
+	}
+
+	return diff
+}
+
+func toInvalidCDPipelineError(pipeline *cdPipeApi.CDPipeline, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return k8sErrors.NewInvalid(cdPipeApi.GroupVersion.WithKind("CDPipeline").GroupKind(), pipeline.Name, errs)
+}
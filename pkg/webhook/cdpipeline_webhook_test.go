@@ -0,0 +1,249 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	codebaseApi "github.com/epam/edp-codebase-operator/v2/api/v1"
+)
+
+func TestCDPipelineWebhook_SetupWebhookWithManager(t *testing.T) {
+	t.Parallel()
+
+	mgr := newTestManager(t)
+
+	require.NoError(t, NewCDPipelineWebhook(fake.NewClientBuilder().Build(), logr.Discard()).SetupWebhookWithManager(mgr))
+	requireHandled(t, mgr, "/convert")
+	requireHandled(t, mgr, "/validate-v2-edp-epam-com-v1-cdpipeline")
+}
+
+func newTestCDPipeline() *cdPipeApi.CDPipeline {
+	return &cdPipeApi.CDPipeline{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "pipeline",
+			Namespace: "default",
+		},
+		Spec: cdPipeApi.CDPipelineSpec{
+			Name:                  "pipeline",
+			DeploymentType:        cdPipeApi.DeploymentTypeContainer,
+			InputDockerStreams:    []string{"app-master", "lib-release/1.0"},
+			Applications:          []string{"app", "lib"},
+			ApplicationsToPromote: []string{"app"},
+		},
+	}
+}
+
+func newTestCodebaseImageStream(name string) *codebaseApi.CodebaseImageStream {
+	return &codebaseApi.CodebaseImageStream{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+	}
+}
+
+func newTestWebhookScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+	require.NoError(t, codebaseApi.AddToScheme(scheme))
+
+	return scheme
+}
+
+func TestCDPipelineWebhook_ValidateCreate(t *testing.T) {
+	t.Parallel()
+
+	scheme := newTestWebhookScheme(t)
+	streams := []client.Object{
+		newTestCodebaseImageStream("app-master"),
+		newTestCodebaseImageStream("lib-release-1-0"),
+	}
+
+	tests := []struct {
+		name     string
+		pipeline func() *cdPipeApi.CDPipeline
+		objects  []client.Object
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			name:     "valid pipeline",
+			pipeline: newTestCDPipeline,
+			objects:  streams,
+			wantErr:  require.NoError,
+		},
+		{
+			name: "custom deployment type",
+			pipeline: func() *cdPipeApi.CDPipeline {
+				p := newTestCDPipeline()
+				p.Spec.DeploymentType = cdPipeApi.DeploymentTypeCustom
+
+				return p
+			},
+			objects: streams,
+			wantErr: require.NoError,
+		},
+		{
+			name: "unsupported deployment type",
+			pipeline: func() *cdPipeApi.CDPipeline {
+				p := newTestCDPipeline()
+				p.Spec.DeploymentType = "helm"
+
+				return p
+			},
+			objects: streams,
+			wantErr: requireInvalid("spec.deploymentType: Unsupported value: \"helm\""),
+		},
+		{
+			name: "application to promote isn't in applications",
+			pipeline: func() *cdPipeApi.CDPipeline {
+				p := newTestCDPipeline()
+				p.Spec.ApplicationsToPromote = []string{"app", "unknown"}
+
+				return p
+			},
+			objects: streams,
+			wantErr: requireInvalid("spec.applicationsToPromote[1]: Invalid value: \"unknown\""),
+		},
+		{
+			name:     "input docker stream doesn't exist",
+			pipeline: newTestCDPipeline,
+			objects:  []client.Object{newTestCodebaseImageStream("app-master")},
+			wantErr:  requireInvalid("spec.inputDockerStreams[1]: Not found: \"lib-release/1.0\""),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := NewCDPipelineWebhook(
+				fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build(),
+				logr.Discard(),
+			)
+
+			tt.wantErr(t, w.ValidateCreate(context.Background(), tt.pipeline()))
+		})
+	}
+}
+
+func TestCDPipelineWebhook_ValidateUpdate(t *testing.T) {
+	t.Parallel()
+
+	scheme := newTestWebhookScheme(t)
+	objects := []client.Object{
+		newTestCodebaseImageStream("app-master"),
+		newTestCodebaseImageStream("lib-release-1-0"),
+		newTestStage("dev", 0),
+		func() *codebaseApi.CodebaseImageStream {
+			cis := newTestCodebaseImageStream("pipeline-dev-app-verified")
+			cis.Spec.Tags = []codebaseApi.Tag{{Name: "1.0.0"}}
+
+			return cis
+		}(),
+		// Verified stream is created for every stage application, even if nothing has been promoted.
+		newTestCodebaseImageStream("pipeline-dev-lib-verified"),
+	}
+
+	tests := []struct {
+		name     string
+		pipeline func() *cdPipeApi.CDPipeline
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			name:     "pipeline isn't changed",
+			pipeline: newTestCDPipeline,
+			wantErr:  require.NoError,
+		},
+		{
+			name: "not promoted application is removed",
+			pipeline: func() *cdPipeApi.CDPipeline {
+				p := newTestCDPipeline()
+				p.Spec.Applications = []string{"app"}
+				p.Spec.InputDockerStreams = []string{"app-master"}
+
+				return p
+			},
+			wantErr: require.NoError,
+		},
+		{
+			name: "promoted application is removed",
+			pipeline: func() *cdPipeApi.CDPipeline {
+				p := newTestCDPipeline()
+				p.Spec.Applications = []string{"lib"}
+				p.Spec.ApplicationsToPromote = nil
+				p.Spec.InputDockerStreams = []string{"lib-release/1.0"}
+
+				return p
+			},
+			wantErr: requireInvalid("application app has been promoted by stage dev"),
+		},
+		{
+			name: "promoted application is removed with force annotation",
+			pipeline: func() *cdPipeApi.CDPipeline {
+				p := newTestCDPipeline()
+				p.Annotations = map[string]string{cdPipeApi.ForceRemoveApplicationsAnnotation: "true"}
+				p.Spec.Applications = []string{"lib"}
+				p.Spec.ApplicationsToPromote = nil
+				p.Spec.InputDockerStreams = []string{"lib-release/1.0"}
+
+				return p
+			},
+			wantErr: require.NoError,
+		},
+		{
+			name: "invalid spec",
+			pipeline: func() *cdPipeApi.CDPipeline {
+				p := newTestCDPipeline()
+				p.Spec.DeploymentType = ""
+
+				return p
+			},
+			wantErr: requireInvalid("spec.deploymentType"),
+		},
+		{
+			name: "pipeline is being deleted",
+			pipeline: func() *cdPipeApi.CDPipeline {
+				p := newTestCDPipeline()
+				p.Spec.DeploymentType = ""
+				now := metaV1.Now()
+				p.DeletionTimestamp = &now
+
+				return p
+			},
+			wantErr: require.NoError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := NewCDPipelineWebhook(
+				fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+				logr.Discard(),
+			)
+
+			tt.wantErr(t, w.ValidateUpdate(context.Background(), newTestCDPipeline(), tt.pipeline()))
+		})
+	}
+}
+
+func TestCDPipelineWebhook_ValidateDelete(t *testing.T) {
+	t.Parallel()
+
+	w := NewCDPipelineWebhook(fake.NewClientBuilder().Build(), logr.Discard())
+
+	require.NoError(t, w.ValidateDelete(context.Background(), newTestCDPipeline()))
+}
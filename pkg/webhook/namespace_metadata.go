@@ -0,0 +1,26 @@
+package webhook
+
+import (
+	apiValidation "k8s.io/apimachinery/pkg/api/validation"
+	metaValidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+)
+
+// validateNamespaceMetadata checks that the namespace labels and annotations are valid
+// and don't override the labels managed by the operator.
+func validateNamespaceMetadata(labels, annotations map[string]string, specPath *field.Path) field.ErrorList {
+	labelsPath := specPath.Child("namespaceLabels")
+
+	errs := metaValidation.ValidateLabels(labels, labelsPath)
+	errs = append(errs, apiValidation.ValidateAnnotations(annotations, specPath.Child("namespaceAnnotations"))...)
+
+	for _, key := range []string{util.TenantLabelName, util.StageLabelName} {
+		if _, ok := labels[key]; ok {
+			errs = append(errs, field.Forbidden(labelsPath.Key(key), "label is managed by the operator"))
+		}
+	}
+
+	return errs
+}
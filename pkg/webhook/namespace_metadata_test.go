@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+)
+
+func Test_validateNamespaceMetadata(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		want        []string
+	}{
+		{
+			name: "valid metadata",
+			labels: map[string]string{
+				"pod-security.kubernetes.io/enforce": "restricted",
+			},
+			annotations: map[string]string{
+				"cost-center": "any value is allowed",
+			},
+		},
+		{
+			name: "empty metadata",
+		},
+		{
+			name: "invalid label value",
+			labels: map[string]string{
+				"istio-injection": "not valid",
+			},
+			want: []string{"spec.namespaceLabels"},
+		},
+		{
+			name: "invalid annotation key",
+			annotations: map[string]string{
+				"-cost-center": "42",
+			},
+			want: []string{"spec.namespaceAnnotations"},
+		},
+		{
+			name: "operator labels are forbidden",
+			labels: map[string]string{
+				util.TenantLabelName: "other",
+				util.StageLabelName:  "other",
+			},
+			want: []string{
+				"spec.namespaceLabels[app.edp.epam.com/tenant]",
+				"spec.namespaceLabels[app.edp.epam.com/stage]",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			errs := validateNamespaceMetadata(tt.labels, tt.annotations, field.NewPath("spec"))
+
+			fields := make([]string, 0, len(errs))
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+
+			assert.ElementsMatch(t, tt.want, fields)
+		})
+	}
+}
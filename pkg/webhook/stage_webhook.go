@@ -0,0 +1,395 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/controllers/stage/chain/util"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/objectmodifier"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/rbac"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/consts"
+)
+
+//+kubebuilder:webhook:path=/mutate-v2-edp-epam-com-v1-stage,mutating=true,failurePolicy=fail,sideEffects=None,groups=v2.edp.epam.com,resources=stages,verbs=create;update,versions=v1,name=mstage.edp.epam.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-v2-edp-epam-com-v1-stage,mutating=false,failurePolicy=fail,sideEffects=None,groups=v2.edp.epam.com,resources=stages,verbs=create;update,versions=v1,name=vstage.edp.epam.com,admissionReviewVersions=v1
+
+// StageWebhook is a webhook for the Stage resource.
+type StageWebhook struct {
+	client    client.Client
+	defaulter objectmodifier.StageModifier
+	log       logr.Logger
+}
+
+var (
+	_ admission.CustomValidator = &StageWebhook{}
+	_ admission.CustomDefaulter = &StageWebhook{}
+)
+
+// NewStageWebhook returns a new instance of StageWebhook.
+func NewStageWebhook(c client.Client, log logr.Logger) *StageWebhook {
+	return &StageWebhook{
+		client:    c,
+		defaulter: objectmodifier.NewStageModifiersAll(c, c.Scheme()),
+		log:       log.WithName("stage-webhook"),
+	}
+}
+
+// SetupWebhookWithManager registers the Stage webhooks in the manager.
+// The conversion webhook is registered because v1 Stage is a conversion hub.
+func (r *StageWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&cdPipeApi.Stage{}).
+		WithDefaulter(r).
+		WithValidator(r).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to create stage webhook: %w", err)
+	}
+
+	return nil
+}
+
+// Default sets the stage label, spec.namespace and the CDPipeline owner reference
+// at admission time, so the stage is correct from the first write.
+// The same modifiers are applied by the stage controller if webhooks are disabled.
+func (r *StageWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	stage, ok := obj.(*cdPipeApi.Stage)
+	if !ok {
+		return fmt.Errorf("expected a Stage but got a %T", obj)
+	}
+
+	if !stage.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	if stage.Namespace == "" {
+		if req, err := admission.RequestFromContext(ctx); err == nil {
+			stage.Namespace = req.Namespace
+		}
+	}
+
+	log := r.log.WithValues("name", stage.Name)
+	log.Info("Defaulting Stage")
+
+	if _, err := r.defaulter.Apply(ctrl.LoggerInto(ctx, log), stage); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			// Missing CDPipeline is reported by the validating webhook.
+			log.Info("CDPipeline doesn't exist, skip defaulting", "cdPipeline", stage.Spec.CdPipeline)
+
+			return nil
+		}
+
+		return fmt.Errorf("failed to set stage defaults: %w", err)
+	}
+
+	return nil
+}
+
+// ValidateCreate checks that the stage belongs to the existing CDPipeline,
+// its order doesn't break the stages order sequence, quality gates and approval policy are valid.
+func (r *StageWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	stage, ok := obj.(*cdPipeApi.Stage)
+	if !ok {
+		return fmt.Errorf("expected a Stage but got a %T", obj)
+	}
+
+	r.log.Info("Validating Stage creation", "name", stage.Name)
+
+	errs := validateQualityGates(stage)
+	errs = append(errs, validateTargetNamespace(stage)...)
+	errs = append(errs, validateRoleBindings(stage)...)
+	errs = append(errs, validateApprovalPolicy(stage)...)
+	errs = append(errs, validateNamespaceMetadata(
+		stage.Spec.NamespaceLabels,
+		stage.Spec.NamespaceAnnotations,
+		field.NewPath("spec"),
+	)...)
+
+	pipelineErrs, err := r.validatePipeline(ctx, stage)
+	if err != nil {
+		return err
+	}
+
+	errs = append(errs, pipelineErrs...)
+
+	orderErrs, err := r.validateOrder(ctx, stage)
+	if err != nil {
+		return err
+	}
+
+	errs = append(errs, orderErrs...)
+
+	return toInvalidStageError(stage, errs)
+}
+
+// ValidateUpdate checks that the stage pipeline, name, order and target namespace haven't been changed
+// and validates only the changed spec fields, so the stages created before the validation rules were added
+// can still be updated, e.g. by the controller adding a finalizer.
+// Updates of the stage which is being deleted are not validated to allow finalizers removal.
+func (r *StageWebhook) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) error {
+	oldStage, ok := oldObj.(*cdPipeApi.Stage)
+	if !ok {
+		return fmt.Errorf("expected a Stage but got a %T", oldObj)
+	}
+
+	stage, ok := newObj.(*cdPipeApi.Stage)
+	if !ok {
+		return fmt.Errorf("expected a Stage but got a %T", newObj)
+	}
+
+	if !stage.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	r.log.Info("Validating Stage update", "name", stage.Name)
+
+	errs := validateImmutableFields(oldStage, stage)
+
+	if !equality.Semantic.DeepEqual(oldStage.Spec.QualityGates, stage.Spec.QualityGates) {
+		errs = append(errs, validateQualityGates(stage)...)
+	}
+
+	if oldStage.Spec.Namespace != stage.Spec.Namespace {
+		errs = append(errs, validateTargetNamespace(stage)...)
+	}
+
+	if !equality.Semantic.DeepEqual(oldStage.Spec.RoleBindings, stage.Spec.RoleBindings) {
+		errs = append(errs, validateRoleBindings(stage)...)
+	}
+
+	if !equality.Semantic.DeepEqual(oldStage.Spec.Approval, stage.Spec.Approval) ||
+		!equality.Semantic.DeepEqual(oldStage.Spec.PromotionPolicy, stage.Spec.PromotionPolicy) ||
+		oldStage.Spec.TriggerType != stage.Spec.TriggerType {
+		errs = append(errs, validateApprovalPolicy(stage)...)
+	}
+
+	if !equality.Semantic.DeepEqual(oldStage.Spec.NamespaceLabels, stage.Spec.NamespaceLabels) ||
+		!equality.Semantic.DeepEqual(oldStage.Spec.NamespaceAnnotations, stage.Spec.NamespaceAnnotations) {
+		errs = append(errs, validateNamespaceMetadata(
+			stage.Spec.NamespaceLabels,
+			stage.Spec.NamespaceAnnotations,
+			field.NewPath("spec"),
+		)...)
+	}
+
+	return toInvalidStageError(stage, errs)
+}
+
+// ValidateDelete doesn't validate the stage deletion.
+func (*StageWebhook) ValidateDelete(context.Context, runtime.Object) error {
+	return nil
+}
+
+// validatePipeline checks that the stage CDPipeline exists.
+func (r *StageWebhook) validatePipeline(ctx context.Context, stage *cdPipeApi.Stage) (field.ErrorList, error) {
+	if err := r.client.Get(ctx, client.ObjectKey{
+		Namespace: stage.Namespace,
+		Name:      stage.Spec.CdPipeline,
+	}, &cdPipeApi.CDPipeline{}); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return field.ErrorList{field.NotFound(field.NewPath("spec", "cdPipeline"), stage.Spec.CdPipeline)}, nil
+		}
+
+		return nil, fmt.Errorf("failed to get CDPipeline %s: %w", stage.Spec.CdPipeline, err)
+	}
+
+	return nil, nil
+}
+
+// validateOrder checks that the stage order is unique within the CDPipeline
+// and the stages orders are a contiguous sequence starting from 0.
+func (r *StageWebhook) validateOrder(ctx context.Context, stage *cdPipeApi.Stage) (field.ErrorList, error) {
+	orderPath := field.NewPath("spec", "order")
+
+	stages := &cdPipeApi.StageList{}
+	if err := r.client.List(ctx, stages, client.InNamespace(stage.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list stages: %w", err)
+	}
+
+	orders := []int{stage.Spec.Order}
+
+	for i := range stages.Items {
+		s := &stages.Items[i]
+
+		if s.Name == stage.Name || s.Spec.CdPipeline != stage.Spec.CdPipeline {
+			continue
+		}
+
+		if s.Spec.Order == stage.Spec.Order {
+			return field.ErrorList{
+				field.Duplicate(orderPath, fmt.Sprintf("%d is already used by stage %s", stage.Spec.Order, s.Name)),
+			}, nil
+		}
+
+		orders = append(orders, s.Spec.Order)
+	}
+
+	sort.Ints(orders)
+
+	for i, order := range orders {
+		if order != i {
+			return field.ErrorList{
+				field.Invalid(orderPath, stage.Spec.Order, fmt.Sprintf("stages order should be a sequence without gaps starting from 0, expected order %d", i)),
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// validateImmutableFields checks that the fields which identify the stage in its CDPipeline
+// and the stage target namespace haven't been changed.
+// The target namespace can be set only to the generated name for the stages created without it.
+func validateImmutableFields(oldStage, stage *cdPipeApi.Stage) field.ErrorList {
+	var errs field.ErrorList
+
+	if oldStage.Spec.CdPipeline != stage.Spec.CdPipeline {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "cdPipeline"), "cdPipeline of the existing stage can't be changed"))
+	}
+
+	if oldStage.Spec.Name != stage.Spec.Name {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "name"), "name of the existing stage can't be changed"))
+	}
+
+	if oldStage.Spec.Order != stage.Spec.Order {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "order"), "order of the existing stage can't be changed"))
+	}
+
+	if oldStage.Spec.Namespace != stage.Spec.Namespace &&
+		(oldStage.Spec.Namespace != "" || stage.Spec.Namespace != util.GenerateNamespaceName(stage)) {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "namespace"), "namespace of the existing stage can't be changed"))
+	}
+
+	return errs
+}
+
+// validateQualityGates checks that quality gates have a known type and autotests have a name.
+func validateQualityGates(stage *cdPipeApi.Stage) field.ErrorList {
+	var errs field.ErrorList
+
+	supportedTypes := []string{cdPipeApi.QualityGateTypeManual, cdPipeApi.QualityGateTypeAutotests}
+
+	for i, qg := range stage.Spec.QualityGates {
+		qgPath := field.NewPath("spec", "qualityGates").Index(i)
+
+		switch qg.QualityGateType {
+		case cdPipeApi.QualityGateTypeManual:
+		case cdPipeApi.QualityGateTypeAutotests:
+			if qg.AutotestName == nil || *qg.AutotestName == "" {
+				errs = append(errs, field.Required(qgPath.Child("autotestName"), "autotestName is required for autotests quality gate"))
+			}
+		default:
+			errs = append(errs, field.NotSupported(qgPath.Child("qualityGateType"), qg.QualityGateType, supportedTypes))
+		}
+	}
+
+	return errs
+}
+
+// validateApprovalPolicy checks that the approval policy has approvers
+// and the stage which requires approvals isn't promoted or deployed automatically.
+func validateApprovalPolicy(stage *cdPipeApi.Stage) field.ErrorList {
+	if stage.Spec.Approval == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+
+	approvalPath := field.NewPath("spec", "approval")
+
+	if len(stage.Spec.Approval.Users) == 0 && len(stage.Spec.Approval.Groups) == 0 {
+		errs = append(errs, field.Required(approvalPath, "at least one user or group is required"))
+	}
+
+	if stage.Spec.PromotionPolicy != nil && stage.Spec.PromotionPolicy.Auto {
+		errs = append(errs, field.Forbidden(
+			field.NewPath("spec", "promotionPolicy", "auto"),
+			"stage which requires approvals can't be promoted automatically",
+		))
+	}
+
+	if stage.Spec.TriggerType == consts.AutoDeployTriggerType {
+		errs = append(errs, field.Forbidden(
+			field.NewPath("spec", "triggerType"),
+			"stage which requires approvals can't be deployed automatically",
+		))
+	}
+
+	return errs
+}
+
+// validateTargetNamespace checks that spec.namespace is a valid namespace name.
+func validateTargetNamespace(stage *cdPipeApi.Stage) field.ErrorList {
+	if stage.Spec.Namespace == "" {
+		return nil
+	}
+
+	var errs field.ErrorList
+
+	for _, msg := range validation.IsDNS1123Label(stage.Spec.Namespace) {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "namespace"), stage.Spec.Namespace, msg))
+	}
+
+	return errs
+}
+
+// validateRoleBindings checks that every role binding has a valid name, subjects
+// and references either a Role or ClusterRole or contains the rules of the Role.
+func validateRoleBindings(stage *cdPipeApi.Stage) field.ErrorList {
+	var errs field.ErrorList
+
+	names := make(map[string]struct{}, len(stage.Spec.RoleBindings))
+
+	for i := range stage.Spec.RoleBindings {
+		rb := &stage.Spec.RoleBindings[i]
+		rbPath := field.NewPath("spec", "roleBindings").Index(i)
+
+		for _, msg := range validation.IsDNS1123Subdomain(rb.Name) {
+			errs = append(errs, field.Invalid(rbPath.Child("name"), rb.Name, msg))
+		}
+
+		if _, ok := names[rb.Name]; ok {
+			errs = append(errs, field.Duplicate(rbPath.Child("name"), rb.Name))
+		}
+
+		names[rb.Name] = struct{}{}
+
+		if len(rb.Subjects) == 0 {
+			errs = append(errs, field.Required(rbPath.Child("subjects"), "at least one subject is required"))
+		}
+
+		switch {
+		case rb.RoleRef == nil && len(rb.Rules) == 0:
+			errs = append(errs, field.Required(rbPath, "either roleRef or rules should be set"))
+		case rb.RoleRef != nil && len(rb.Rules) > 0:
+			errs = append(errs, field.Forbidden(rbPath.Child("rules"), "rules can't be set together with roleRef"))
+		case rb.RoleRef != nil && rb.RoleRef.Kind != rbac.RoleKind && rb.RoleRef.Kind != rbac.ClusterRoleKind:
+			errs = append(errs, field.NotSupported(
+				rbPath.Child("roleRef", "kind"),
+				rb.RoleRef.Kind,
+				[]string{rbac.RoleKind, rbac.ClusterRoleKind},
+			))
+		}
+	}
+
+	return errs
+}
+
+func toInvalidStageError(stage *cdPipeApi.Stage, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return k8sErrors.NewInvalid(cdPipeApi.GroupVersion.WithKind("Stage").GroupKind(), stage.Name, errs)
+}
@@ -0,0 +1,591 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	rbacApi "k8s.io/api/rbac/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	cdPipeApi "github.com/epam/edp-cd-pipeline-operator/v2/api/v1"
+	cdPipeApiV1Alpha1 "github.com/epam/edp-cd-pipeline-operator/v2/api/v1alpha1"
+	"github.com/epam/edp-cd-pipeline-operator/v2/pkg/util/consts"
+)
+
+func newTestManager(t *testing.T) manager.Manager {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+	require.NoError(t, cdPipeApiV1Alpha1.AddToScheme(scheme))
+
+	mgr, err := ctrl.NewManager(&rest.Config{Host: "https://127.0.0.1:6443"}, ctrl.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+		MapperProvider: func(c *rest.Config) (meta.RESTMapper, error) {
+			return meta.NewDefaultRESTMapper(nil), nil
+		},
+	})
+	require.NoError(t, err)
+
+	return mgr
+}
+
+func requireHandled(t *testing.T, mgr manager.Manager, path string) {
+	t.Helper()
+
+	_, pattern := mgr.GetWebhookServer().WebhookMux.Handler(httptest.NewRequest(http.MethodPost, path, http.NoBody))
+	require.Equal(t, path, pattern)
+}
+
+func TestStageWebhook_SetupWebhookWithManager(t *testing.T) {
+	t.Parallel()
+
+	mgr := newTestManager(t)
+
+	require.NoError(t, NewStageWebhook(fake.NewClientBuilder().Build(), logr.Discard()).SetupWebhookWithManager(mgr))
+	requireHandled(t, mgr, "/convert")
+	requireHandled(t, mgr, "/mutate-v2-edp-epam-com-v1-stage")
+	requireHandled(t, mgr, "/validate-v2-edp-epam-com-v1-stage")
+}
+
+func newTestStage(name string, order int) *cdPipeApi.Stage {
+	return &cdPipeApi.Stage{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: cdPipeApi.StageSpec{
+			Name:       name,
+			CdPipeline: "pipeline",
+			Order:      order,
+			QualityGates: []cdPipeApi.QualityGate{
+				{
+					QualityGateType: cdPipeApi.QualityGateTypeManual,
+					StepName:        "approve",
+				},
+			},
+		},
+	}
+}
+
+func requireInvalid(contains string) require.ErrorAssertionFunc {
+	return func(t require.TestingT, err error, i ...interface{}) {
+		require.Error(t, err)
+		require.True(t, k8sErrors.IsInvalid(err), "expected invalid error, got %v", err)
+		require.Contains(t, err.Error(), contains)
+	}
+}
+
+func TestStageWebhook_Default(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	pipeline := &cdPipeApi.CDPipeline{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "pipeline",
+			Namespace: "default",
+			UID:       "pipeline-uid",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		stage     func() *cdPipeApi.Stage
+		objects   []client.Object
+		wantStage func(t *testing.T, stage *cdPipeApi.Stage)
+		wantErr   require.ErrorAssertionFunc
+	}{
+		{
+			name: "defaults are set",
+			stage: func() *cdPipeApi.Stage {
+				return newTestStage("dev", 0)
+			},
+			objects: []client.Object{pipeline},
+			wantStage: func(t *testing.T, stage *cdPipeApi.Stage) {
+				assert.Equal(t, "pipeline", stage.Labels[cdPipeApi.StageCdPipelineLabelName])
+				assert.Equal(t, "default-dev", stage.Spec.Namespace)
+				require.Len(t, stage.OwnerReferences, 1)
+				assert.Equal(t, pipeline.UID, stage.OwnerReferences[0].UID)
+			},
+			wantErr: require.NoError,
+		},
+		{
+			name: "namespace is taken from admission request",
+			stage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Namespace = ""
+
+				return s
+			},
+			objects: []client.Object{pipeline},
+			wantStage: func(t *testing.T, stage *cdPipeApi.Stage) {
+				assert.Equal(t, "default", stage.Namespace)
+				assert.Equal(t, "default-dev", stage.Spec.Namespace)
+			},
+			wantErr: require.NoError,
+		},
+		{
+			name: "custom namespace is kept",
+			stage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.Namespace = "custom-ns"
+
+				return s
+			},
+			objects: []client.Object{pipeline},
+			wantStage: func(t *testing.T, stage *cdPipeApi.Stage) {
+				assert.Equal(t, "custom-ns", stage.Spec.Namespace)
+			},
+			wantErr: require.NoError,
+		},
+		{
+			name: "pipeline doesn't exist",
+			stage: func() *cdPipeApi.Stage {
+				return newTestStage("dev", 0)
+			},
+			wantStage: func(t *testing.T, stage *cdPipeApi.Stage) {
+				assert.Empty(t, stage.OwnerReferences)
+			},
+			wantErr: require.NoError,
+		},
+		{
+			name: "stage is being deleted",
+			stage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				now := metaV1.Now()
+				s.DeletionTimestamp = &now
+
+				return s
+			},
+			objects: []client.Object{pipeline},
+			wantStage: func(t *testing.T, stage *cdPipeApi.Stage) {
+				assert.Empty(t, stage.Labels)
+				assert.Empty(t, stage.Spec.Namespace)
+			},
+			wantErr: require.NoError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := NewStageWebhook(
+				fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build(),
+				logr.Discard(),
+			)
+
+			ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{Namespace: "default"},
+			})
+
+			stage := tt.stage()
+
+			tt.wantErr(t, w.Default(ctx, stage))
+			tt.wantStage(t, stage)
+		})
+	}
+}
+
+func TestStageWebhook_ValidateCreate(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, cdPipeApi.AddToScheme(scheme))
+
+	pipeline := &cdPipeApi.CDPipeline{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "pipeline",
+			Namespace: "default",
+		},
+	}
+
+	otherPipelineStage := newTestStage("other", 3)
+	otherPipelineStage.Spec.CdPipeline = "other-pipeline"
+
+	tests := []struct {
+		name    string
+		stage   *cdPipeApi.Stage
+		objects []client.Object
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:    "first stage is valid",
+			stage:   newTestStage("dev", 0),
+			objects: []client.Object{pipeline, otherPipelineStage},
+			wantErr: require.NoError,
+		},
+		{
+			name:    "next stage is valid",
+			stage:   newTestStage("qa", 1),
+			objects: []client.Object{pipeline, newTestStage("dev", 0)},
+			wantErr: require.NoError,
+		},
+		{
+			name:    "pipeline doesn't exist",
+			stage:   newTestStage("dev", 0),
+			wantErr: requireInvalid("spec.cdPipeline: Not found"),
+		},
+		{
+			name:    "duplicate order",
+			stage:   newTestStage("qa", 0),
+			objects: []client.Object{pipeline, newTestStage("dev", 0)},
+			wantErr: requireInvalid("0 is already used by stage dev"),
+		},
+		{
+			name: "invalid namespace",
+			stage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.Namespace = "Team_Dev"
+
+				return s
+			}(),
+			objects: []client.Object{pipeline},
+			wantErr: requireInvalid("spec.namespace: Invalid value: \"Team_Dev\""),
+		},
+		{
+			name: "valid role bindings",
+			stage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.RoleBindings = []cdPipeApi.RoleBindingTemplate{
+					{
+						Name:     "developers",
+						Subjects: []rbacApi.Subject{{Kind: rbacApi.GroupKind, Name: "developers"}},
+						RoleRef:  &rbacApi.RoleRef{Kind: "ClusterRole", Name: "edit"},
+					},
+					{
+						Name:     "log-readers",
+						Subjects: []rbacApi.Subject{{Kind: rbacApi.GroupKind, Name: "support"}},
+						Rules:    []rbacApi.PolicyRule{{Resources: []string{"pods/log"}, Verbs: []string{"get"}}},
+					},
+				}
+
+				return s
+			}(),
+			objects: []client.Object{pipeline},
+			wantErr: require.NoError,
+		},
+		{
+			name: "role binding without role",
+			stage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.RoleBindings = []cdPipeApi.RoleBindingTemplate{
+					{
+						Name:     "developers",
+						Subjects: []rbacApi.Subject{{Kind: rbacApi.GroupKind, Name: "developers"}},
+					},
+				}
+
+				return s
+			}(),
+			objects: []client.Object{pipeline},
+			wantErr: requireInvalid("spec.roleBindings[0]: Required value: either roleRef or rules should be set"),
+		},
+		{
+			name: "role binding with both role and rules",
+			stage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.RoleBindings = []cdPipeApi.RoleBindingTemplate{
+					{
+						Name:     "developers",
+						Subjects: []rbacApi.Subject{{Kind: rbacApi.GroupKind, Name: "developers"}},
+						RoleRef:  &rbacApi.RoleRef{Kind: "ClusterRole", Name: "edit"},
+						Rules:    []rbacApi.PolicyRule{{Resources: []string{"pods"}, Verbs: []string{"get"}}},
+					},
+				}
+
+				return s
+			}(),
+			objects: []client.Object{pipeline},
+			wantErr: requireInvalid("spec.roleBindings[0].rules: Forbidden"),
+		},
+		{
+			name: "role binding with unsupported kind and duplicate name",
+			stage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.RoleBindings = []cdPipeApi.RoleBindingTemplate{
+					{
+						Name:     "developers",
+						Subjects: []rbacApi.Subject{{Kind: rbacApi.GroupKind, Name: "developers"}},
+						RoleRef:  &rbacApi.RoleRef{Kind: "ClusterRole", Name: "edit"},
+					},
+					{
+						Name:    "developers",
+						RoleRef: &rbacApi.RoleRef{Kind: "Group", Name: "edit"},
+					},
+				}
+
+				return s
+			}(),
+			objects: []client.Object{pipeline},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				requireInvalid(`spec.roleBindings[1].roleRef.kind: Unsupported value: "Group"`)(t, err, i...)
+				requireInvalid(`spec.roleBindings[1].name: Duplicate value: "developers"`)(t, err, i...)
+				requireInvalid("spec.roleBindings[1].subjects: Required value")(t, err, i...)
+			},
+		},
+		{
+			name:    "gap in order",
+			stage:   newTestStage("prod", 2),
+			objects: []client.Object{pipeline, newTestStage("dev", 0)},
+			wantErr: requireInvalid("expected order 1"),
+		},
+		{
+			name: "unknown quality gate type",
+			stage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.QualityGates[0].QualityGateType = "unknown"
+
+				return s
+			}(),
+			objects: []client.Object{pipeline},
+			wantErr: requireInvalid(`spec.qualityGates[0].qualityGateType: Unsupported value: "unknown"`),
+		},
+		{
+			name: "autotests without autotestName",
+			stage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.QualityGates[0].QualityGateType = cdPipeApi.QualityGateTypeAutotests
+
+				return s
+			}(),
+			objects: []client.Object{pipeline},
+			wantErr: requireInvalid("spec.qualityGates[0].autotestName: Required value"),
+		},
+		{
+			name: "autotests with autotestName",
+			stage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.QualityGates[0].QualityGateType = cdPipeApi.QualityGateTypeAutotests
+				s.Spec.QualityGates[0].AutotestName = pointer.String("autotests")
+
+				return s
+			}(),
+			objects: []client.Object{pipeline},
+			wantErr: require.NoError,
+		},
+		{
+			name: "approval with approvers",
+			stage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.Approval = &cdPipeApi.ApprovalPolicy{Groups: []string{"release-managers"}}
+
+				return s
+			}(),
+			objects: []client.Object{pipeline},
+			wantErr: require.NoError,
+		},
+		{
+			name: "approval without approvers and with auto promotion",
+			stage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.Approval = &cdPipeApi.ApprovalPolicy{RequiredApprovals: 2}
+				s.Spec.PromotionPolicy = &cdPipeApi.PromotionPolicy{Auto: true}
+
+				return s
+			}(),
+			objects: []client.Object{pipeline},
+			wantErr: func(t require.TestingT, err error, i ...interface{}) {
+				requireInvalid("spec.approval: Required value")(t, err, i...)
+				requireInvalid("spec.promotionPolicy.auto: Forbidden")(t, err, i...)
+			},
+		},
+		{
+			name: "approval with auto trigger type",
+			stage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.Approval = &cdPipeApi.ApprovalPolicy{Users: []string{"john"}}
+				s.Spec.TriggerType = consts.AutoDeployTriggerType
+
+				return s
+			}(),
+			objects: []client.Object{pipeline},
+			wantErr: requireInvalid("spec.triggerType: Forbidden"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := NewStageWebhook(
+				fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build(),
+				logr.Discard(),
+			)
+
+			tt.wantErr(t, w.ValidateCreate(context.Background(), tt.stage))
+		})
+	}
+}
+
+func TestStageWebhook_ValidateUpdate(t *testing.T) {
+	t.Parallel()
+
+	deleted := newTestStage("dev", 1)
+	now := metaV1.Now()
+	deleted.DeletionTimestamp = &now
+
+	// invalid stage has been created before the validation rules were added.
+	invalid := newTestStage("dev", 0)
+	invalid.Spec.QualityGates[0].QualityGateType = "Manual"
+	invalid.Spec.Approval = &cdPipeApi.ApprovalPolicy{}
+
+	tests := []struct {
+		name     string
+		oldStage *cdPipeApi.Stage
+		newStage *cdPipeApi.Stage
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			name:     "order is not changed",
+			oldStage: newTestStage("dev", 0),
+			newStage: newTestStage("dev", 0),
+			wantErr:  require.NoError,
+		},
+		{
+			name:     "order is changed",
+			oldStage: newTestStage("dev", 0),
+			newStage: newTestStage("dev", 1),
+			wantErr:  requireInvalid("order of the existing stage can't be changed"),
+		},
+		{
+			name:     "cdPipeline is changed",
+			oldStage: newTestStage("dev", 0),
+			newStage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.CdPipeline = "other"
+
+				return s
+			}(),
+			wantErr: requireInvalid("cdPipeline of the existing stage can't be changed"),
+		},
+		{
+			name:     "name is changed",
+			oldStage: newTestStage("dev", 0),
+			newStage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.Name = "qa"
+
+				return s
+			}(),
+			wantErr: requireInvalid("name of the existing stage can't be changed"),
+		},
+		{
+			name: "namespace is changed",
+			oldStage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.Namespace = "team-dev"
+
+				return s
+			}(),
+			newStage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.Namespace = "kube-system"
+
+				return s
+			}(),
+			wantErr: requireInvalid("namespace of the existing stage can't be changed"),
+		},
+		{
+			name:     "generated namespace is set",
+			oldStage: newTestStage("dev", 0),
+			newStage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.Namespace = "default-dev"
+
+				return s
+			}(),
+			wantErr: require.NoError,
+		},
+		{
+			name:     "custom namespace is set",
+			oldStage: newTestStage("dev", 0),
+			newStage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.Namespace = "kube-system"
+
+				return s
+			}(),
+			wantErr: requireInvalid("namespace of the existing stage can't be changed"),
+		},
+		{
+			name:     "stage is being deleted",
+			oldStage: newTestStage("dev", 0),
+			newStage: deleted,
+			wantErr:  require.NoError,
+		},
+		{
+			name:     "unknown quality gate type",
+			oldStage: newTestStage("dev", 0),
+			newStage: func() *cdPipeApi.Stage {
+				s := newTestStage("dev", 0)
+				s.Spec.QualityGates[0].QualityGateType = "Manual"
+
+				return s
+			}(),
+			wantErr: requireInvalid("qualityGateType"),
+		},
+		{
+			name:     "finalizer is added to the stage with invalid spec",
+			oldStage: invalid,
+			newStage: func() *cdPipeApi.Stage {
+				s := invalid.DeepCopy()
+				s.Finalizers = []string{"envLabelDeletion"}
+
+				return s
+			}(),
+			wantErr: require.NoError,
+		},
+		{
+			name:     "invalid spec field is changed",
+			oldStage: invalid,
+			newStage: func() *cdPipeApi.Stage {
+				s := invalid.DeepCopy()
+				s.Spec.TriggerType = consts.AutoDeployTriggerType
+
+				return s
+			}(),
+			wantErr: requireInvalid("spec.triggerType: Forbidden"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := NewStageWebhook(fake.NewClientBuilder().Build(), logr.Discard())
+
+			tt.wantErr(t, w.ValidateUpdate(context.Background(), tt.oldStage, tt.newStage))
+		})
+	}
+}
+
+func TestStageWebhook_ValidateDelete(t *testing.T) {
+	t.Parallel()
+
+	w := NewStageWebhook(fake.NewClientBuilder().Build(), logr.Discard())
+
+	assert.NoError(t, w.ValidateDelete(context.Background(), newTestStage("dev", 0)))
+}